@@ -3,12 +3,23 @@ package main
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/delivery/http"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/delivery/http/handler"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/delivery/http/middleware"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/mail"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/memory"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/oidc"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/persistence"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/ratelimit"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/search"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/storage"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/config"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "fmt"
     "log"
+    "os"
+    "time"
 )
 
 func main() {
@@ -21,37 +32,320 @@ func main() {
     // 初始化日志
     logger.InitLogger(cfg.LogLevel)
 
-    // 初始化数据库
-    db, err := persistence.NewMySQLConnection(cfg)
+    // 数据库迁移子命令
+    if len(os.Args) > 1 && os.Args[1] == "migrate" {
+        if err := runMigrate(cfg, os.Args[2:]); err != nil {
+            log.Fatalf("数据库迁移失败: %v", err)
+        }
+        return
+    }
+
+    // 初始化数据库和仓库
+    repos, err := newRepositories(cfg)
     if err != nil {
         logger.Error("无法连接数据库", err)
         return
     }
+    userRepo := repos.userRepo
+    postRepo := repos.postRepo
+    commentRepo := repos.commentRepo
+    tagRepo := repos.tagRepo
+    categoryRepo := repos.categoryRepo
 
-    // 初始化仓库
-    userRepo := persistence.NewUserRepository(db)
-    postRepo := persistence.NewPostRepository(db)
-    commentRepo := persistence.NewCommentRepository(db)
+    // 初始化上传文件存储
+    fileStorage, err := newFileStorage(cfg)
+    if err != nil {
+        logger.Error("无法初始化文件存储", err)
+        return
+    }
+    uploadMaxSize := int64(cfg.StorageConfig.MaxSizeMB) << 20
+
+    // 初始化邮件发送
+    mailer, err := newMailer(cfg)
+    if err != nil {
+        logger.Error("无法初始化邮件发送", err)
+        return
+    }
 
     // 初始化JWT服务
-    jwtService := auth.NewJWTService(cfg)
+    jwtService, err := auth.NewJWTService(cfg, repos.revocationStore)
+    if err != nil {
+        logger.Error("无法初始化JWT服务", err)
+        return
+    }
+
+    // 初始化密码哈希和密码规则
+    passwordHasher, passwordPolicy, err := newPasswordSettings(cfg)
+    if err != nil {
+        logger.Error("无法初始化密码设置", err)
+        return
+    }
 
     // 初始化用例
-    userUseCase := usecase.NewUserUseCase(userRepo, jwtService)
-    postUseCase := usecase.NewPostUseCase(postRepo, userRepo)
-    commentUseCase := usecase.NewCommentUseCase(commentRepo, postRepo, userRepo)
+    lc := cfg.LockoutConfig
+    loginGuard := usecase.NewLoginGuard(repos.loginAttemptRepo, repos.loginLockoutRepo, userRepo, usecase.LockoutPolicy{
+        UsernameThreshold: lc.UsernameThreshold,
+        IPThreshold:       lc.IPThreshold,
+        BaseDuration:      time.Duration(lc.BaseMinutes) * time.Minute,
+        MaxDuration:       time.Duration(lc.MaxMinutes) * time.Minute,
+        ResetAfter:        time.Duration(lc.ResetHours) * time.Hour,
+        AttemptRetention:  time.Duration(lc.AttemptRetentionDays) * 24 * time.Hour,
+    })
+    ac := cfg.AccountConfig
+    accountOptions := usecase.AccountOptions{
+        BaseURL:    ac.BaseURL,
+        VerifyTTL:  time.Duration(ac.VerifyTTLHours) * time.Hour,
+        ResetTTL:   time.Duration(ac.ResetTTLMinutes) * time.Minute,
+        TOTPIssuer: ac.TOTPIssuer,
+    }
+    userUseCase := usecase.NewUserUseCase(userRepo, repos.userTokenRepo, repos.userTOTPRepo, repos.recoveryCodeRepo, repos.apiTokenRepo,
+        repos.transactor, repos.searchIndex, jwtService, loginGuard, mailer, accountOptions, passwordHasher, passwordPolicy)
+    postUseCase := usecase.NewPostUseCase(postRepo, userRepo, commentRepo, tagRepo, categoryRepo, repos.revisionRepo, repos.transactor, repos.searchIndex)
+    commentUseCase := usecase.NewCommentUseCase(commentRepo, postRepo, userRepo, repos.searchIndex)
+    tagUseCase := usecase.NewTagUseCase(tagRepo, userRepo)
+    categoryUseCase := usecase.NewCategoryUseCase(categoryRepo, userRepo)
+    exportUseCase := usecase.NewExportUseCase(userRepo, postRepo, commentRepo, repos.revisionRepo, repos.bookmarkRepo)
+    apiTokenUseCase := usecase.NewAPITokenUseCase(repos.apiTokenRepo, userRepo, repos.userTOTPRepo)
+    reactionUseCase := usecase.NewReactionUseCase(repos.reactionRepo, repos.bookmarkRepo, postRepo, commentRepo, userRepo, cfg.ReactionEmojis)
+    uploadUseCase := usecase.NewUploadUseCase(repos.attachmentRepo, postRepo, userRepo, fileStorage, uploadMaxSize)
+    trashUseCase := usecase.NewTrashUseCase(userRepo, postRepo, commentRepo, repos.attachmentRepo, fileStorage, time.Duration(cfg.TrashRetentionDays)*24*time.Hour)
+
+    // 内存搜索索引需要从已有数据重建
+    if cfg.SearchBackend == config.SearchMemory {
+        if err := postUseCase.RebuildSearchIndex(); err != nil {
+            logger.Error("重建搜索索引失败", err)
+            return
+        }
+    }
+
+    // 为升级前创建的文章生成content_html
+    if count, err := postUseCase.RenderMissingContentHTML(); err != nil {
+        logger.Error("生成文章HTML失败", err)
+        return
+    } else if count > 0 {
+        logger.Info(fmt.Sprintf("已为 %d 篇文章生成HTML", count))
+    }
+
+    // 启动定时发布和回收站清理调度器
+    publishScheduler := usecase.NewScheduler("发布定时文章", time.Duration(cfg.SchedulerInterval)*time.Second, postUseCase.PublishDuePosts)
+    publishScheduler.Start()
+    defer publishScheduler.Stop()
+
+    purgeScheduler := usecase.NewScheduler("清理回收站", time.Duration(cfg.TrashPurgeInterval)*time.Minute, trashUseCase.PurgeExpired)
+    purgeScheduler.Start()
+    defer purgeScheduler.Stop()
+
+    loginAuditScheduler := usecase.NewScheduler("清理登录记录", time.Hour, loginGuard.PurgeExpired)
+    loginAuditScheduler.Start()
+    defer loginAuditScheduler.Stop()
+
+    tokenPurgeScheduler := usecase.NewScheduler("清理过期令牌", time.Hour, userUseCase.PurgeExpiredTokens)
+    tokenPurgeScheduler.Start()
+    defer tokenPurgeScheduler.Stop()
+
+    apiTokenPurgeScheduler := usecase.NewScheduler("清理过期访问令牌", time.Hour, apiTokenUseCase.PurgeExpired)
+    apiTokenPurgeScheduler.Start()
+    defer apiTokenPurgeScheduler.Stop()
+
+    // 配置了身份提供方时启用单点登录
+    var oidcHandler *handler.OIDCHandler
+    if oc := cfg.OIDCConfig; oc.Enabled() {
+        provider, err := oidc.NewProvider(oidc.Config{
+            Issuer:       oc.Issuer,
+            ClientID:     oc.ClientID,
+            ClientSecret: oc.ClientSecret,
+            RedirectURL:  oc.RedirectURL,
+            Scopes:       oc.Scopes,
+        })
+        if err != nil {
+            logger.Error("无法初始化单点登录", err)
+            return
+        }
+        oidcUseCase := usecase.NewOIDCUseCase(provider, repos.userIdentityRepo, repos.oidcStateRepo, userRepo, repos.userTOTPRepo,
+            repos.transactor, jwtService, loginGuard, usecase.OIDCOptions{LinkByEmail: oc.LinkByEmail})
+        oidcHandler = handler.NewOIDCHandler(oidcUseCase)
+
+        oidcStateScheduler := usecase.NewScheduler("清理单点登录请求", time.Hour, oidcUseCase.PurgeExpiredStates)
+        oidcStateScheduler.Start()
+        defer oidcStateScheduler.Stop()
+    }
 
     // 初始化处理器
-    userHandler := handler.NewUserHandler(userUseCase)
+    userHandler := handler.NewUserHandler(userUseCase, exportUseCase)
     postHandler := handler.NewPostHandler(postUseCase)
     commentHandler := handler.NewCommentHandler(commentUseCase)
+    adminHandler := handler.NewAdminHandler(userUseCase, loginGuard)
+    tagHandler := handler.NewTagHandler(tagUseCase)
+    categoryHandler := handler.NewCategoryHandler(categoryUseCase)
+    uploadHandler := handler.NewUploadHandler(uploadUseCase, uploadMaxSize)
+    apiTokenHandler := handler.NewAPITokenHandler(apiTokenUseCase)
+    reactionHandler := handler.NewReactionHandler(reactionUseCase)
 
     // 设置路由
-    router := http.SetupRouter(userHandler, postHandler, commentHandler, jwtService)
+    rl := cfg.RateLimitConfig
+    rateLimits := middleware.RateLimitRules{
+        Login:    ratelimit.Rule{Limit: rl.Login.Requests, Period: rl.Login.Period},
+        Register: ratelimit.Rule{Limit: rl.Register.Requests, Period: rl.Register.Period},
+        Write:    ratelimit.Rule{Limit: rl.Write.Requests, Period: rl.Write.Period},
+        Email:    ratelimit.Rule{Limit: rl.Email.Requests, Period: rl.Email.Period},
+    }
+    router := http.SetupRouter(userHandler, postHandler, commentHandler, adminHandler, tagHandler, categoryHandler, uploadHandler, oidcHandler, apiTokenHandler, reactionHandler,
+        jwtService, apiTokenUseCase, ratelimit.NewMemoryStore(), rateLimits)
+
+    // 只信任配置的反向代理传入的X-Forwarded-For，否则客户端可以伪造IP绕过限流
+    if err := router.SetTrustedProxies(rl.TrustedProxies); err != nil {
+        logger.Error("无效的可信代理配置", err)
+        return
+    }
 
     // 启动服务器
     logger.Info("服务器启动在端口" + cfg.ServerPort)
     if err := router.Run(":" + cfg.ServerPort); err != nil {
         logger.Error("服务器启动失败", err)
     }
-}
\ No newline at end of file
+}
+
+// repositories 按数据库驱动创建的仓库集合
+type repositories struct {
+    userRepo         repository.UserRepository
+    postRepo         repository.PostRepository
+    commentRepo      repository.CommentRepository
+    tagRepo          repository.TagRepository
+    categoryRepo     repository.CategoryRepository
+    revisionRepo     repository.PostRevisionRepository
+    attachmentRepo   repository.AttachmentRepository
+    loginAttemptRepo repository.LoginAttemptRepository
+    loginLockoutRepo repository.LoginLockoutRepository
+    userTokenRepo    repository.UserTokenRepository
+    userTOTPRepo     repository.UserTOTPRepository
+    recoveryCodeRepo repository.RecoveryCodeRepository
+    userIdentityRepo repository.UserIdentityRepository
+    oidcStateRepo    repository.OIDCStateRepository
+    apiTokenRepo     repository.APITokenRepository
+    reactionRepo     repository.ReactionRepository
+    bookmarkRepo     repository.BookmarkRepository
+    transactor       repository.Transactor
+    revocationStore  auth.RevocationStore
+    searchIndex      repository.SearchIndex
+}
+
+// newRepositories 根据DB_DRIVER选择MySQL、SQLite或纯内存实现
+func newRepositories(cfg *config.Config) (*repositories, error) {
+    if cfg.DBConfig.Driver == config.DriverMemory {
+        logger.Warn("使用内存存储，服务重启后数据将丢失")
+        store := memory.NewStore()
+        return &repositories{
+            userRepo:         memory.NewUserRepository(store),
+            postRepo:         memory.NewPostRepository(store),
+            commentRepo:      memory.NewCommentRepository(store),
+            tagRepo:          memory.NewTagRepository(store),
+            categoryRepo:     memory.NewCategoryRepository(store),
+            revisionRepo:     memory.NewPostRevisionRepository(store),
+            attachmentRepo:   memory.NewAttachmentRepository(store),
+            loginAttemptRepo: memory.NewLoginAttemptRepository(store),
+            loginLockoutRepo: memory.NewLoginLockoutRepository(store),
+            userTokenRepo:    memory.NewUserTokenRepository(store),
+            userTOTPRepo:     memory.NewUserTOTPRepository(store),
+            recoveryCodeRepo: memory.NewRecoveryCodeRepository(store),
+            userIdentityRepo: memory.NewUserIdentityRepository(store),
+            oidcStateRepo:    memory.NewOIDCStateRepository(store),
+            apiTokenRepo:     memory.NewAPITokenRepository(store),
+            reactionRepo:     memory.NewReactionRepository(store),
+            bookmarkRepo:     memory.NewBookmarkRepository(store),
+            transactor:       memory.NewTransactor(store),
+            revocationStore:  auth.NewMemoryRevocationStore(),
+            searchIndex:      search.NewInvertedIndex(),
+        }, nil
+    }
+
+    db, err := persistence.NewDatabase(cfg)
+    if err != nil {
+        return nil, err
+    }
+    searchIndex := search.NewInvertedIndex()
+    if cfg.SearchBackend == config.SearchMySQL {
+        searchIndex = search.NewMySQLFullTextIndex(db)
+    }
+    return &repositories{
+        userRepo:         persistence.NewUserRepository(db),
+        postRepo:         persistence.NewPostRepository(db),
+        commentRepo:      persistence.NewCommentRepository(db),
+        tagRepo:          persistence.NewTagRepository(db),
+        categoryRepo:     persistence.NewCategoryRepository(db),
+        revisionRepo:     persistence.NewPostRevisionRepository(db),
+        attachmentRepo:   persistence.NewAttachmentRepository(db),
+        loginAttemptRepo: persistence.NewLoginAttemptRepository(db),
+        loginLockoutRepo: persistence.NewLoginLockoutRepository(db),
+        userTokenRepo:    persistence.NewUserTokenRepository(db),
+        userTOTPRepo:     persistence.NewUserTOTPRepository(db),
+        recoveryCodeRepo: persistence.NewRecoveryCodeRepository(db),
+        userIdentityRepo: persistence.NewUserIdentityRepository(db),
+        oidcStateRepo:    persistence.NewOIDCStateRepository(db),
+        apiTokenRepo:     persistence.NewAPITokenRepository(db),
+        reactionRepo:     persistence.NewReactionRepository(db),
+        bookmarkRepo:     persistence.NewBookmarkRepository(db),
+        transactor:       persistence.NewTransactor(db),
+        revocationStore:  auth.NewGormRevocationStore(db),
+        searchIndex:      searchIndex,
+    }, nil
+}
+
+// newFileStorage 根据STORAGE_BACKEND选择本地目录或S3兼容对象存储
+func newFileStorage(cfg *config.Config) (repository.FileStorage, error) {
+    sc := cfg.StorageConfig
+    if sc.Backend == config.StorageS3 {
+        return storage.NewS3Storage(storage.S3Config{
+            Endpoint:  sc.S3Endpoint,
+            Region:    sc.S3Region,
+            Bucket:    sc.S3Bucket,
+            AccessKey: sc.S3AccessKey,
+            SecretKey: sc.S3SecretKey,
+            PathStyle: sc.S3PathStyle,
+            PublicURL: sc.S3PublicURL,
+        })
+    }
+    return storage.NewLocalStorage(sc.LocalDir, sc.BaseURL)
+}
+
+// newMailer 根据MAIL_BACKEND选择SMTP、文件或内存实现
+func newMailer(cfg *config.Config) (mail.Mailer, error) {
+    mc := cfg.MailConfig
+    switch mc.Backend {
+    case config.MailSMTP:
+        return mail.NewSMTPMailer(mail.SMTPConfig{
+            Host:     mc.SMTPHost,
+            Port:     mc.SMTPPort,
+            Username: mc.SMTPUsername,
+            Password: mc.SMTPPassword,
+            From:     mc.From,
+        })
+    case config.MailMemory:
+        logger.Warn("邮件只保存在内存中，不会实际发送")
+        return mail.NewMemoryMailer(), nil
+    }
+    return mail.NewFileMailer(mc.Dir, mc.From)
+}
+
+// newPasswordSettings 根据配置创建密码哈希服务和密码规则，配置了泄露密码列表时从文件加载
+func newPasswordSettings(cfg *config.Config) (auth.PasswordHasher, usecase.PasswordPolicy, error) {
+    pc := cfg.PasswordConfig
+    policy := usecase.PasswordPolicy{MinLength: pc.MinLength, MaxLength: pc.MaxLength}
+
+    hasher, err := auth.NewPasswordHasher(pc.Algorithm, auth.Argon2Params{
+        Memory:      uint32(pc.Argon2MemoryKB),
+        Iterations:  uint32(pc.Argon2Iterations),
+        Parallelism: uint8(pc.Argon2Parallelism),
+    }, pc.BcryptCost)
+    if err != nil {
+        return nil, policy, err
+    }
+
+    if pc.BreachedList != "" {
+        policy.Breached, err = auth.LoadBreachedPasswords(pc.BreachedList)
+        if err != nil {
+            return nil, policy, fmt.Errorf("加载泄露密码列表失败: %w", err)
+        }
+        logger.Info(fmt.Sprintf("已加载 %d 条泄露密码", policy.Breached.Len()))
+    }
+    return hasher, policy, nil
+}
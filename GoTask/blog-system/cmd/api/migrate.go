@@ -0,0 +1,66 @@
+package main
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/config"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/persistence"
+    "errors"
+    "fmt"
+    "strconv"
+)
+
+const migrateUsage = "用法: blog-system migrate up|down [N]|status"
+
+// runMigrate 执行 migrate 子命令
+func runMigrate(cfg *config.Config, args []string) error {
+    if len(args) == 0 {
+        return errors.New(migrateUsage)
+    }
+
+    if cfg.DBConfig.Driver == config.DriverMemory {
+        return errors.New("内存存储不需要执行数据库迁移")
+    }
+
+    db, err := persistence.OpenDatabase(cfg)
+    if err != nil {
+        return err
+    }
+
+    migrator, err := persistence.NewMigrator(db)
+    if err != nil {
+        return err
+    }
+
+    switch args[0] {
+    case "up":
+        count, err := migrator.Up()
+        fmt.Printf("已执行 %d 个迁移\n", count)
+        return err
+    case "down":
+        // 默认只回滚最近一个迁移
+        steps := 1
+        if len(args) > 1 {
+            steps, err = strconv.Atoi(args[1])
+            if err != nil || steps < 1 {
+                return errors.New("回滚数量必须是正整数")
+            }
+        }
+        count, err := migrator.Down(steps)
+        fmt.Printf("已回滚 %d 个迁移\n", count)
+        return err
+    case "status":
+        statuses, err := migrator.Status()
+        if err != nil {
+            return err
+        }
+        for _, status := range statuses {
+            state := "未执行"
+            if status.Applied {
+                state = "已执行 " + status.AppliedAt.Format("2006-01-02 15:04:05")
+            }
+            fmt.Printf("%04d  %-30s %s\n", status.Version, status.Name, state)
+        }
+        return nil
+    default:
+        return errors.New(migrateUsage)
+    }
+}
@@ -0,0 +1,248 @@
+// mockoidc 本地开发和联调用的OpenID Connect身份提供方，不做任何认证，不能用于生产环境
+//
+// 授权请求直接以 login_hint 指定的邮箱（默认 alice@example.com）登录并回调，
+// 支持授权码流程和PKCE（S256），ID令牌使用启动时随机生成的RSA密钥签名。
+package main
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "crypto/subtle"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/json"
+    "flag"
+    "log"
+    "math/big"
+    "net/http"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/golang-jwt/jwt/v4"
+)
+
+// authCodeTTL 授权码有效期
+const authCodeTTL = time.Minute
+
+// authRequest 授权码对应的授权请求
+type authRequest struct {
+    clientID      string
+    redirectURI   string
+    nonce         string
+    codeChallenge string
+    email         string
+    expiresAt     time.Time
+}
+
+type server struct {
+    issuer        string
+    clientID      string
+    clientSecret  string
+    emailVerified bool
+    key           *rsa.PrivateKey
+    keyID         string
+
+    mu    sync.Mutex
+    codes map[string]*authRequest
+}
+
+func main() {
+    addr := flag.String("addr", ":9000", "监听地址")
+    issuer := flag.String("issuer", "http://localhost:9000", "issuer，需要与博客系统的 OIDC_ISSUER 一致")
+    clientID := flag.String("client-id", "blog", "客户端ID")
+    clientSecret := flag.String("client-secret", "", "客户端密钥，为空时按公开客户端处理")
+    emailVerified := flag.Bool("email-verified", true, "ID令牌中的email_verified")
+    flag.Parse()
+
+    key, err := rsa.GenerateKey(rand.Reader, 2048)
+    if err != nil {
+        log.Fatalf("生成签名密钥失败: %v", err)
+    }
+    s := &server{
+        issuer:        strings.TrimRight(*issuer, "/"),
+        clientID:      *clientID,
+        clientSecret:  *clientSecret,
+        emailVerified: *emailVerified,
+        key:           key,
+        keyID:         randomString(8),
+        codes:         make(map[string]*authRequest),
+    }
+
+    mux := http.NewServeMux()
+    mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+    mux.HandleFunc("/jwks", s.jwks)
+    mux.HandleFunc("/authorize", s.authorize)
+    mux.HandleFunc("/token", s.token)
+
+    log.Printf("模拟OIDC身份提供方启动在 %s，issuer=%s", *addr, s.issuer)
+    log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// discovery 身份提供方元数据
+func (s *server) discovery(w http.ResponseWriter, r *http.Request) {
+    writeJSON(w, http.StatusOK, map[string]any{
+        "issuer":                                s.issuer,
+        "authorization_endpoint":                s.issuer + "/authorize",
+        "token_endpoint":                        s.issuer + "/token",
+        "jwks_uri":                              s.issuer + "/jwks",
+        "response_types_supported":              []string{"code"},
+        "subject_types_supported":               []string{"public"},
+        "id_token_signing_alg_values_supported": []string{"RS256"},
+        "code_challenge_methods_supported":      []string{"S256"},
+    })
+}
+
+// jwks 签名公钥
+func (s *server) jwks(w http.ResponseWriter, r *http.Request) {
+    pub := s.key.PublicKey
+    writeJSON(w, http.StatusOK, map[string]any{
+        "keys": []map[string]string{{
+            "kty": "RSA",
+            "use": "sig",
+            "alg": "RS256",
+            "kid": s.keyID,
+            "n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+            "e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+        }},
+    })
+}
+
+// authorize 不做认证，直接以login_hint指定的邮箱登录并携带授权码回调
+func (s *server) authorize(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    if q.Get("client_id") != s.clientID {
+        http.Error(w, "unknown client_id", http.StatusBadRequest)
+        return
+    }
+    redirectURI, err := url.Parse(q.Get("redirect_uri"))
+    if err != nil || redirectURI.Scheme == "" {
+        http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+        return
+    }
+    if q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+        http.Error(w, "only response_type=code with PKCE S256 is supported", http.StatusBadRequest)
+        return
+    }
+
+    email := q.Get("login_hint")
+    if email == "" {
+        email = "alice@example.com"
+    }
+    code := randomString(16)
+    s.mu.Lock()
+    s.codes[code] = &authRequest{
+        clientID:      s.clientID,
+        redirectURI:   redirectURI.String(),
+        nonce:         q.Get("nonce"),
+        codeChallenge: q.Get("code_challenge"),
+        email:         email,
+        expiresAt:     time.Now().Add(authCodeTTL),
+    }
+    s.mu.Unlock()
+
+    query := redirectURI.Query()
+    query.Set("code", code)
+    query.Set("state", q.Get("state"))
+    redirectURI.RawQuery = query.Encode()
+    http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// token 使用授权码和code_verifier换取ID令牌，授权码只能使用一次
+func (s *server) token(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    if err := r.ParseForm(); err != nil {
+        tokenError(w, "invalid_request", err.Error())
+        return
+    }
+
+    clientID, clientSecret, ok := r.BasicAuth()
+    if ok {
+        clientID, _ = url.QueryUnescape(clientID)
+        clientSecret, _ = url.QueryUnescape(clientSecret)
+    } else {
+        clientID = r.PostForm.Get("client_id")
+        clientSecret = r.PostForm.Get("client_secret")
+    }
+    if clientID != s.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.clientSecret)) != 1 {
+        tokenError(w, "invalid_client", "client authentication failed")
+        return
+    }
+
+    code := r.PostForm.Get("code")
+    s.mu.Lock()
+    req := s.codes[code]
+    delete(s.codes, code)
+    s.mu.Unlock()
+
+    switch {
+    case r.PostForm.Get("grant_type") != "authorization_code":
+        tokenError(w, "unsupported_grant_type", "")
+        return
+    case req == nil || time.Now().After(req.expiresAt):
+        tokenError(w, "invalid_grant", "unknown or expired code")
+        return
+    case r.PostForm.Get("redirect_uri") != req.redirectURI:
+        tokenError(w, "invalid_grant", "redirect_uri mismatch")
+        return
+    }
+    sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+    if base64.RawURLEncoding.EncodeToString(sum[:]) != req.codeChallenge {
+        tokenError(w, "invalid_grant", "code_verifier mismatch")
+        return
+    }
+
+    now := time.Now()
+    username := strings.Split(req.email, "@")[0]
+    claims := jwt.MapClaims{
+        "iss":                s.issuer,
+        "sub":                "mock-" + username,
+        "aud":                s.clientID,
+        "exp":                now.Add(time.Hour).Unix(),
+        "iat":                now.Unix(),
+        "nonce":              req.nonce,
+        "email":              req.email,
+        "email_verified":     s.emailVerified,
+        "name":               username,
+        "preferred_username": username,
+    }
+    idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+    idToken.Header["kid"] = s.keyID
+    signed, err := idToken.SignedString(s.key)
+    if err != nil {
+        tokenError(w, "server_error", err.Error())
+        return
+    }
+
+    writeJSON(w, http.StatusOK, map[string]any{
+        "access_token": randomString(16),
+        "token_type":   "Bearer",
+        "expires_in":   3600,
+        "id_token":     signed,
+    })
+}
+
+// tokenError 令牌接口的错误响应
+func tokenError(w http.ResponseWriter, code, description string) {
+    writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    _ = json.NewEncoder(w).Encode(v)
+}
+
+// randomString 生成n字节随机数的十六进制字符串
+func randomString(n int) string {
+    b := make([]byte, n)
+    if _, err := rand.Read(b); err != nil {
+        panic(err)
+    }
+    return hex.EncodeToString(b)
+}
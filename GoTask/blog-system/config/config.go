@@ -1,26 +1,190 @@
 package config
 
 import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
     "fmt"
+    "strconv"
+    "strings"
+    "time"
+
     "github.com/spf13/viper"
 )
 
+// 数据库驱动
+const (
+    DriverMySQL  = "mysql"
+    DriverSQLite = "sqlite"
+    DriverMemory = "memory"
+)
+
+// 启动时的数据库迁移方式
+const (
+    MigrationManual      = "manual"      // 仅检查是否有未执行的迁移，需手动执行 migrate up
+    MigrationStartup     = "startup"     // 启动时自动执行未执行的版本化迁移
+    MigrationAutoMigrate = "automigrate" // 开发模式，使用GORM AutoMigrate同步表结构
+)
+
+// 搜索后端
+const (
+    SearchMemory = "memory" // 纯Go内存倒排索引，启动时从数据库重建
+    SearchMySQL  = "mysql"  // MySQL FULLTEXT索引，需要DB_DRIVER=mysql
+)
+
+// 文件存储后端
+const (
+    StorageLocal = "local" // 本地文件系统
+    StorageS3    = "s3"    // S3兼容对象存储
+)
+
+// 邮件发送方式
+const (
+    MailSMTP   = "smtp"   // 通过SMTP服务器发送
+    MailFile   = "file"   // 写入MAIL_DIR目录下的.eml文件，用于本地开发
+    MailMemory = "memory" // 保存在进程内存中，用于测试
+)
+
+// 密码哈希算法
+const (
+    PasswordArgon2id = "argon2id" // 默认值
+    PasswordBcrypt   = "bcrypt"
+)
+
+// JWT签名算法
+const (
+    JWTHS256 = "HS256" // 默认值，使用JWT_SECRET共享密钥
+    JWTRS256 = "RS256"
+    JWTEdDSA = "EdDSA" // Ed25519
+)
+
+// defaultJWTSecret JWT_SECRET的默认值，只能用于本地开发
+const defaultJWTSecret = "your-secret-key"
+
+// 表情回应的默认集合和数量限制，单个表情的长度限制见 model.MaxReactionEmojiBytes
+const (
+    defaultReactionEmojis = "👍,👎,😄,🎉,😕,❤️,🚀,👀"
+    maxReactionEmojis     = 20
+)
+
 // DB 数据库配置
 type DB struct {
-    Host     string `mapstructure:"DB_HOST"`
-    Port     string `mapstructure:"DB_PORT"`
-    User     string `mapstructure:"DB_USER"`
-    Password string `mapstructure:"DB_PASSWORD"`
-    Name     string `mapstructure:"DB_NAME"`
+    Driver    string `mapstructure:"DB_DRIVER"` // mysql、sqlite 或 memory
+    Host      string `mapstructure:"DB_HOST"`
+    Port      string `mapstructure:"DB_PORT"`
+    User      string `mapstructure:"DB_USER"`
+    Password  string `mapstructure:"DB_PASSWORD"`
+    Name      string `mapstructure:"DB_NAME"`
+    Path      string `mapstructure:"DB_PATH"`           // SQLite数据库文件路径
+    Migration string `mapstructure:"DB_MIGRATION_MODE"` // manual、startup 或 automigrate
+}
+
+// Storage 上传文件存储配置
+type Storage struct {
+    Backend     string `mapstructure:"STORAGE_BACKEND"`    // local 或 s3
+    MaxSizeMB   int    `mapstructure:"UPLOAD_MAX_SIZE_MB"` // 单个文件大小上限（MB）
+    LocalDir    string `mapstructure:"UPLOAD_DIR"`         // 本地存储目录
+    BaseURL     string `mapstructure:"UPLOAD_BASE_URL"`    // 本地存储文件的访问地址前缀
+    S3Endpoint  string `mapstructure:"S3_ENDPOINT"`
+    S3Region    string `mapstructure:"S3_REGION"`
+    S3Bucket    string `mapstructure:"S3_BUCKET"`
+    S3AccessKey string `mapstructure:"S3_ACCESS_KEY"`
+    S3SecretKey string `mapstructure:"S3_SECRET_KEY"`
+    S3PathStyle bool   `mapstructure:"S3_PATH_STYLE"` // 使用路径形式的地址（MinIO等）
+    S3PublicURL string `mapstructure:"S3_PUBLIC_URL"` // 文件对外访问地址前缀，为空时使用对象地址
+}
+
+// RateRule 限流规则：每个Period内最多Requests次请求，Requests为0表示不限流
+type RateRule struct {
+    Requests int
+    Period   time.Duration
+}
+
+// RateLimit 各路由组的限流配置，格式为 次数/周期（如 10/m），周期为 s、m 或 h
+type RateLimit struct {
+    Login          RateRule `mapstructure:"RATE_LIMIT_LOGIN"`    // 登录和刷新令牌，按IP
+    Register       RateRule `mapstructure:"RATE_LIMIT_REGISTER"` // 注册，按IP
+    Write          RateRule `mapstructure:"RATE_LIMIT_WRITE"`    // 需要认证的写操作，按用户
+    Email          RateRule `mapstructure:"RATE_LIMIT_EMAIL"`    // 找回密码和重发验证邮件，按IP或用户
+    TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`     // 可信反向代理，只有来自这些地址的X-Forwarded-For才会被采用
+}
+
+// Lockout 登录失败锁定配置
+type Lockout struct {
+    UsernameThreshold    int `mapstructure:"LOGIN_LOCKOUT_THRESHOLD"`      // 同一用户名连续失败多少次后锁定，0表示不锁定
+    IPThreshold          int `mapstructure:"LOGIN_LOCKOUT_IP_THRESHOLD"`   // 同一IP连续失败多少次后锁定，0表示不锁定
+    BaseMinutes          int `mapstructure:"LOGIN_LOCKOUT_BASE_MINUTES"`   // 首次锁定时长（分钟），之后每多失败一次翻倍
+    MaxMinutes           int `mapstructure:"LOGIN_LOCKOUT_MAX_MINUTES"`    // 锁定时长上限（分钟）
+    ResetHours           int `mapstructure:"LOGIN_LOCKOUT_RESET_HOURS"`    // 超过多少小时没有失败则重新计数
+    AttemptRetentionDays int `mapstructure:"LOGIN_ATTEMPT_RETENTION_DAYS"` // 登录记录保留天数
+}
+
+// Mail 邮件配置
+type Mail struct {
+    Backend      string `mapstructure:"MAIL_BACKEND"`  // smtp、file 或 memory
+    From         string `mapstructure:"MAIL_FROM"`     // 发件人地址
+    Dir          string `mapstructure:"MAIL_DIR"`      // file方式保存邮件的目录
+    SMTPHost     string `mapstructure:"SMTP_HOST"`
+    SMTPPort     int    `mapstructure:"SMTP_PORT"`     // 465使用TLS直连，其他端口在服务器支持时使用STARTTLS
+    SMTPUsername string `mapstructure:"SMTP_USERNAME"` // 为空时不认证
+    SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+}
+
+// Account 邮箱验证、找回密码和两步验证配置
+type Account struct {
+    BaseURL         string `mapstructure:"APP_BASE_URL"`               // 邮件中链接的前缀（前端地址）
+    VerifyTTLHours  int    `mapstructure:"EMAIL_VERIFY_TTL_HOURS"`     // 邮箱验证链接有效期（小时）
+    ResetTTLMinutes int    `mapstructure:"PASSWORD_RESET_TTL_MINUTES"` // 找回密码链接有效期（分钟）
+    TOTPIssuer      string `mapstructure:"TOTP_ISSUER"`                // 验证器应用中显示的服务名称
+}
+
+// Password 密码规则和哈希配置
+type Password struct {
+    Algorithm         string `mapstructure:"PASSWORD_HASH_ALGORITHM"` // argon2id 或 bcrypt，已有的其他算法哈希在登录时自动重新哈希
+    MinLength         int    `mapstructure:"PASSWORD_MIN_LENGTH"`     // 最少字符数
+    MaxLength         int    `mapstructure:"PASSWORD_MAX_LENGTH"`     // 最多字符数
+    BreachedList      string `mapstructure:"PASSWORD_BREACHED_LIST"`  // 泄露密码列表文件，为空时不检查
+    Argon2MemoryKB    int    `mapstructure:"ARGON2_MEMORY_KB"`
+    Argon2Iterations  int    `mapstructure:"ARGON2_ITERATIONS"`
+    Argon2Parallelism int    `mapstructure:"ARGON2_PARALLELISM"`
+    BcryptCost        int    `mapstructure:"BCRYPT_COST"`
+}
+
+// OIDC OpenID Connect单点登录配置，OIDC_ISSUER为空时不启用
+type OIDC struct {
+    Issuer       string   `mapstructure:"OIDC_ISSUER"`        // 身份提供方地址
+    ClientID     string   `mapstructure:"OIDC_CLIENT_ID"`
+    ClientSecret string   `mapstructure:"OIDC_CLIENT_SECRET"` // 为空时作为公开客户端，只依靠PKCE
+    RedirectURL  string   `mapstructure:"OIDC_REDIRECT_URL"`  // 授权完成后的回调地址（前端页面），需要在身份提供方登记
+    Scopes       []string `mapstructure:"OIDC_SCOPES"`        // 空格或逗号分隔
+    LinkByEmail  bool     `mapstructure:"OIDC_LINK_BY_EMAIL"` // 首次登录时按已确认的邮箱自动绑定已有账号
+}
+
+// Enabled 是否启用单点登录
+func (o OIDC) Enabled() bool {
+    return o.Issuer != ""
 }
 
 // Config 应用配置
 type Config struct {
-    ServerPort         string `mapstructure:"SERVER_PORT"`
-    LogLevel           string `mapstructure:"LOG_LEVEL"`
-    JWTSecret          string `mapstructure:"JWT_SECRET"`
-    JWTExpirationHours int    `mapstructure:"JWT_EXPIRATION_HOURS"`
+    ServerPort         string   `mapstructure:"SERVER_PORT"`
+    LogLevel           string   `mapstructure:"LOG_LEVEL"`
+    JWTAlgorithm       string   `mapstructure:"JWT_ALGORITHM"` // HS256、RS256 或 EdDSA
+    JWTSecret          string   `mapstructure:"JWT_SECRET"` // HS256的共享密钥
+    JWTKeyFiles        []string `mapstructure:"JWT_KEY_FILES"` // RS256和EdDSA的PEM密钥文件，第一个用于签名，其余只用于验证
+    JWTExpirationHours int      `mapstructure:"JWT_EXPIRATION_HOURS"` // 刷新令牌有效期（小时）
+    JWTAccessMinutes   int      `mapstructure:"JWT_ACCESS_EXPIRATION_MINUTES"` // 访问令牌有效期（分钟）
+    SearchBackend      string   `mapstructure:"SEARCH_BACKEND"` // memory 或 mysql
+    SchedulerInterval  int      `mapstructure:"POST_SCHEDULER_INTERVAL_SECONDS"` // 定时发布检查间隔（秒）
+    TrashRetentionDays int      `mapstructure:"TRASH_RETENTION_DAYS"` // 回收站保留天数
+    TrashPurgeInterval int      `mapstructure:"TRASH_PURGE_INTERVAL_MINUTES"` // 回收站清理间隔（分钟）
+    ReactionEmojis     []string `mapstructure:"REACTION_EMOJIS"` // 可用的表情回应，逗号分隔
     DBConfig           DB
+    StorageConfig      Storage
+    RateLimitConfig    RateLimit
+    LockoutConfig      Lockout
+    MailConfig         Mail
+    AccountConfig      Account
+    PasswordConfig     Password
+    OIDCConfig         OIDC
 }
 
 // LoadConfig 从环境变量或配置文件加载配置
@@ -35,8 +199,51 @@ func LoadConfig() (*Config, error) {
     // 设置默认值
     viper.SetDefault("SERVER_PORT", "8080")
     viper.SetDefault("LOG_LEVEL", "info")
-    viper.SetDefault("JWT_SECRET", "your-secret-key")
+    viper.SetDefault("JWT_ALGORITHM", JWTHS256)
+    viper.SetDefault("JWT_SECRET", defaultJWTSecret)
     viper.SetDefault("JWT_EXPIRATION_HOURS", 24)
+    viper.SetDefault("JWT_ACCESS_EXPIRATION_MINUTES", 15)
+    viper.SetDefault("SEARCH_BACKEND", SearchMemory)
+    viper.SetDefault("POST_SCHEDULER_INTERVAL_SECONDS", 30)
+    viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+    viper.SetDefault("TRASH_PURGE_INTERVAL_MINUTES", 60)
+    viper.SetDefault("REACTION_EMOJIS", defaultReactionEmojis)
+    viper.SetDefault("STORAGE_BACKEND", StorageLocal)
+    viper.SetDefault("UPLOAD_MAX_SIZE_MB", 10)
+    viper.SetDefault("UPLOAD_DIR", "uploads")
+    viper.SetDefault("UPLOAD_BASE_URL", "/uploads")
+    viper.SetDefault("S3_REGION", "us-east-1")
+    viper.SetDefault("S3_PATH_STYLE", true)
+    viper.SetDefault("RATE_LIMIT_LOGIN", "10/m")
+    viper.SetDefault("RATE_LIMIT_REGISTER", "5/h")
+    viper.SetDefault("RATE_LIMIT_WRITE", "60/m")
+    viper.SetDefault("RATE_LIMIT_EMAIL", "5/h")
+    viper.SetDefault("LOGIN_LOCKOUT_THRESHOLD", 5)
+    viper.SetDefault("LOGIN_LOCKOUT_IP_THRESHOLD", 20)
+    viper.SetDefault("LOGIN_LOCKOUT_BASE_MINUTES", 1)
+    viper.SetDefault("LOGIN_LOCKOUT_MAX_MINUTES", 1440)
+    viper.SetDefault("LOGIN_LOCKOUT_RESET_HOURS", 24)
+    viper.SetDefault("LOGIN_ATTEMPT_RETENTION_DAYS", 90)
+    viper.SetDefault("MAIL_BACKEND", MailFile)
+    viper.SetDefault("MAIL_FROM", "noreply@localhost")
+    viper.SetDefault("MAIL_DIR", "mail")
+    viper.SetDefault("SMTP_PORT", 587)
+    viper.SetDefault("APP_BASE_URL", "http://localhost:8080")
+    viper.SetDefault("EMAIL_VERIFY_TTL_HOURS", 48)
+    viper.SetDefault("PASSWORD_RESET_TTL_MINUTES", 30)
+    viper.SetDefault("TOTP_ISSUER", "Blog System")
+    viper.SetDefault("PASSWORD_HASH_ALGORITHM", PasswordArgon2id)
+    viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+    viper.SetDefault("PASSWORD_MAX_LENGTH", 128)
+    viper.SetDefault("ARGON2_MEMORY_KB", 19456)
+    viper.SetDefault("ARGON2_ITERATIONS", 2)
+    viper.SetDefault("ARGON2_PARALLELISM", 1)
+    viper.SetDefault("BCRYPT_COST", 10)
+    viper.SetDefault("OIDC_SCOPES", "openid email profile")
+    viper.SetDefault("OIDC_LINK_BY_EMAIL", false)
+    viper.SetDefault("DB_DRIVER", DriverMySQL)
+    viper.SetDefault("DB_PATH", "blog.db")
+    viper.SetDefault("DB_MIGRATION_MODE", MigrationManual)
     viper.SetDefault("DB_HOST", "localhost")
     viper.SetDefault("DB_PORT", "3306")
     viper.SetDefault("DB_USER", "root")
@@ -56,17 +263,293 @@ func LoadConfig() (*Config, error) {
 
     var config Config
     config.DBConfig = DB{
-        Host:     viper.GetString("DB_HOST"),
-        Port:     viper.GetString("DB_PORT"),
-        User:     viper.GetString("DB_USER"),
-        Password: viper.GetString("DB_PASSWORD"),
-        Name:     viper.GetString("DB_NAME"),
+        Driver:    strings.ToLower(viper.GetString("DB_DRIVER")),
+        Host:      viper.GetString("DB_HOST"),
+        Port:      viper.GetString("DB_PORT"),
+        User:      viper.GetString("DB_USER"),
+        Password:  viper.GetString("DB_PASSWORD"),
+        Name:      viper.GetString("DB_NAME"),
+        Path:      viper.GetString("DB_PATH"),
+        Migration: strings.ToLower(viper.GetString("DB_MIGRATION_MODE")),
+    }
+
+    switch config.DBConfig.Driver {
+    case DriverMySQL, DriverSQLite, DriverMemory:
+    default:
+        return nil, fmt.Errorf("不支持的数据库驱动: %s", config.DBConfig.Driver)
+    }
+
+    switch config.DBConfig.Migration {
+    case MigrationManual, MigrationStartup, MigrationAutoMigrate:
+    default:
+        return nil, fmt.Errorf("不支持的迁移方式: %s", config.DBConfig.Migration)
     }
 
     config.ServerPort = viper.GetString("SERVER_PORT")
     config.LogLevel = viper.GetString("LOG_LEVEL")
+    config.JWTAlgorithm = viper.GetString("JWT_ALGORITHM")
     config.JWTSecret = viper.GetString("JWT_SECRET")
+    for _, file := range strings.Split(viper.GetString("JWT_KEY_FILES"), ",") {
+        if file = strings.TrimSpace(file); file != "" {
+            config.JWTKeyFiles = append(config.JWTKeyFiles, file)
+        }
+    }
+    switch {
+    case strings.EqualFold(config.JWTAlgorithm, JWTHS256):
+        config.JWTAlgorithm = JWTHS256
+        if config.JWTSecret == "" {
+            return nil, fmt.Errorf("JWT_ALGORITHM=HS256 时 JWT_SECRET 不能为空")
+        }
+        if config.JWTSecret == defaultJWTSecret {
+            fmt.Println("警告: JWT_SECRET 使用的是默认值，请在生产环境中修改或改用 RS256/EdDSA 签名")
+        }
+    case strings.EqualFold(config.JWTAlgorithm, JWTRS256):
+        config.JWTAlgorithm = JWTRS256
+    case strings.EqualFold(config.JWTAlgorithm, JWTEdDSA):
+        config.JWTAlgorithm = JWTEdDSA
+    default:
+        return nil, fmt.Errorf("不支持的JWT签名算法: %s", config.JWTAlgorithm)
+    }
+    if config.JWTAlgorithm != JWTHS256 && len(config.JWTKeyFiles) == 0 {
+        return nil, fmt.Errorf("JWT_ALGORITHM=%s 需要配置 JWT_KEY_FILES", config.JWTAlgorithm)
+    }
     config.JWTExpirationHours = viper.GetInt("JWT_EXPIRATION_HOURS")
+    config.JWTAccessMinutes = viper.GetInt("JWT_ACCESS_EXPIRATION_MINUTES")
+    config.SearchBackend = strings.ToLower(viper.GetString("SEARCH_BACKEND"))
+    config.SchedulerInterval = viper.GetInt("POST_SCHEDULER_INTERVAL_SECONDS")
+    if config.SchedulerInterval <= 0 {
+        return nil, fmt.Errorf("POST_SCHEDULER_INTERVAL_SECONDS 必须大于0")
+    }
+    config.TrashRetentionDays = viper.GetInt("TRASH_RETENTION_DAYS")
+    if config.TrashRetentionDays <= 0 {
+        return nil, fmt.Errorf("TRASH_RETENTION_DAYS 必须大于0")
+    }
+    config.TrashPurgeInterval = viper.GetInt("TRASH_PURGE_INTERVAL_MINUTES")
+    if config.TrashPurgeInterval <= 0 {
+        return nil, fmt.Errorf("TRASH_PURGE_INTERVAL_MINUTES 必须大于0")
+    }
+    reactionEmojis, err := parseReactionEmojis(viper.GetString("REACTION_EMOJIS"))
+    if err != nil {
+        return nil, fmt.Errorf("REACTION_EMOJIS 无效: %w", err)
+    }
+    config.ReactionEmojis = reactionEmojis
+
+    config.StorageConfig = Storage{
+        Backend:     strings.ToLower(viper.GetString("STORAGE_BACKEND")),
+        MaxSizeMB:   viper.GetInt("UPLOAD_MAX_SIZE_MB"),
+        LocalDir:    viper.GetString("UPLOAD_DIR"),
+        BaseURL:     viper.GetString("UPLOAD_BASE_URL"),
+        S3Endpoint:  viper.GetString("S3_ENDPOINT"),
+        S3Region:    viper.GetString("S3_REGION"),
+        S3Bucket:    viper.GetString("S3_BUCKET"),
+        S3AccessKey: viper.GetString("S3_ACCESS_KEY"),
+        S3SecretKey: viper.GetString("S3_SECRET_KEY"),
+        S3PathStyle: viper.GetBool("S3_PATH_STYLE"),
+        S3PublicURL: viper.GetString("S3_PUBLIC_URL"),
+    }
+    if config.StorageConfig.MaxSizeMB <= 0 {
+        return nil, fmt.Errorf("UPLOAD_MAX_SIZE_MB 必须大于0")
+    }
+
+    switch config.StorageConfig.Backend {
+    case StorageLocal:
+    case StorageS3:
+        if config.StorageConfig.S3Endpoint == "" || config.StorageConfig.S3Bucket == "" {
+            return nil, fmt.Errorf("STORAGE_BACKEND=s3 需要配置 S3_ENDPOINT 和 S3_BUCKET")
+        }
+    default:
+        return nil, fmt.Errorf("不支持的存储后端: %s", config.StorageConfig.Backend)
+    }
+
+    for _, item := range []struct {
+        key  string
+        rule *RateRule
+    }{
+        {"RATE_LIMIT_LOGIN", &config.RateLimitConfig.Login},
+        {"RATE_LIMIT_REGISTER", &config.RateLimitConfig.Register},
+        {"RATE_LIMIT_WRITE", &config.RateLimitConfig.Write},
+        {"RATE_LIMIT_EMAIL", &config.RateLimitConfig.Email},
+    } {
+        rule, err := parseRateRule(viper.GetString(item.key))
+        if err != nil {
+            return nil, fmt.Errorf("%s 格式无效: %w", item.key, err)
+        }
+        *item.rule = rule
+    }
+    for _, proxy := range strings.Split(viper.GetString("TRUSTED_PROXIES"), ",") {
+        if proxy = strings.TrimSpace(proxy); proxy != "" {
+            config.RateLimitConfig.TrustedProxies = append(config.RateLimitConfig.TrustedProxies, proxy)
+        }
+    }
+
+    config.LockoutConfig = Lockout{
+        UsernameThreshold:    viper.GetInt("LOGIN_LOCKOUT_THRESHOLD"),
+        IPThreshold:          viper.GetInt("LOGIN_LOCKOUT_IP_THRESHOLD"),
+        BaseMinutes:          viper.GetInt("LOGIN_LOCKOUT_BASE_MINUTES"),
+        MaxMinutes:           viper.GetInt("LOGIN_LOCKOUT_MAX_MINUTES"),
+        ResetHours:           viper.GetInt("LOGIN_LOCKOUT_RESET_HOURS"),
+        AttemptRetentionDays: viper.GetInt("LOGIN_ATTEMPT_RETENTION_DAYS"),
+    }
+    if config.LockoutConfig.UsernameThreshold < 0 || config.LockoutConfig.IPThreshold < 0 {
+        return nil, fmt.Errorf("LOGIN_LOCKOUT_THRESHOLD 和 LOGIN_LOCKOUT_IP_THRESHOLD 不能小于0")
+    }
+    if config.LockoutConfig.BaseMinutes <= 0 || config.LockoutConfig.MaxMinutes < config.LockoutConfig.BaseMinutes {
+        return nil, fmt.Errorf("LOGIN_LOCKOUT_BASE_MINUTES 必须大于0且不超过 LOGIN_LOCKOUT_MAX_MINUTES")
+    }
+    if config.LockoutConfig.ResetHours <= 0 {
+        return nil, fmt.Errorf("LOGIN_LOCKOUT_RESET_HOURS 必须大于0")
+    }
+    if config.LockoutConfig.AttemptRetentionDays <= 0 {
+        return nil, fmt.Errorf("LOGIN_ATTEMPT_RETENTION_DAYS 必须大于0")
+    }
+
+    config.MailConfig = Mail{
+        Backend:      strings.ToLower(viper.GetString("MAIL_BACKEND")),
+        From:         viper.GetString("MAIL_FROM"),
+        Dir:          viper.GetString("MAIL_DIR"),
+        SMTPHost:     viper.GetString("SMTP_HOST"),
+        SMTPPort:     viper.GetInt("SMTP_PORT"),
+        SMTPUsername: viper.GetString("SMTP_USERNAME"),
+        SMTPPassword: viper.GetString("SMTP_PASSWORD"),
+    }
+    switch config.MailConfig.Backend {
+    case MailFile, MailMemory:
+    case MailSMTP:
+        if config.MailConfig.SMTPHost == "" || config.MailConfig.SMTPPort <= 0 {
+            return nil, fmt.Errorf("MAIL_BACKEND=smtp 需要配置 SMTP_HOST 和 SMTP_PORT")
+        }
+    default:
+        return nil, fmt.Errorf("不支持的邮件发送方式: %s", config.MailConfig.Backend)
+    }
+
+    config.AccountConfig = Account{
+        BaseURL:         strings.TrimRight(viper.GetString("APP_BASE_URL"), "/"),
+        VerifyTTLHours:  viper.GetInt("EMAIL_VERIFY_TTL_HOURS"),
+        ResetTTLMinutes: viper.GetInt("PASSWORD_RESET_TTL_MINUTES"),
+        TOTPIssuer:      strings.TrimSpace(viper.GetString("TOTP_ISSUER")),
+    }
+    if config.AccountConfig.BaseURL == "" {
+        return nil, fmt.Errorf("APP_BASE_URL 不能为空")
+    }
+    // 冒号用于分隔otpauth地址中的服务名称和账号
+    if config.AccountConfig.TOTPIssuer == "" || strings.Contains(config.AccountConfig.TOTPIssuer, ":") {
+        return nil, fmt.Errorf("TOTP_ISSUER 不能为空，且不能包含冒号")
+    }
+    if config.AccountConfig.VerifyTTLHours <= 0 || config.AccountConfig.ResetTTLMinutes <= 0 {
+        return nil, fmt.Errorf("EMAIL_VERIFY_TTL_HOURS 和 PASSWORD_RESET_TTL_MINUTES 必须大于0")
+    }
+
+    config.PasswordConfig = Password{
+        Algorithm:         strings.ToLower(viper.GetString("PASSWORD_HASH_ALGORITHM")),
+        MinLength:         viper.GetInt("PASSWORD_MIN_LENGTH"),
+        MaxLength:         viper.GetInt("PASSWORD_MAX_LENGTH"),
+        BreachedList:      viper.GetString("PASSWORD_BREACHED_LIST"),
+        Argon2MemoryKB:    viper.GetInt("ARGON2_MEMORY_KB"),
+        Argon2Iterations:  viper.GetInt("ARGON2_ITERATIONS"),
+        Argon2Parallelism: viper.GetInt("ARGON2_PARALLELISM"),
+        BcryptCost:        viper.GetInt("BCRYPT_COST"),
+    }
+    if config.PasswordConfig.MinLength <= 0 || config.PasswordConfig.MaxLength < config.PasswordConfig.MinLength {
+        return nil, fmt.Errorf("PASSWORD_MIN_LENGTH 必须大于0且不超过 PASSWORD_MAX_LENGTH")
+    }
+    switch config.PasswordConfig.Algorithm {
+    case PasswordArgon2id:
+        if config.PasswordConfig.Argon2MemoryKB <= 0 || config.PasswordConfig.Argon2Iterations <= 0 ||
+            config.PasswordConfig.Argon2Parallelism <= 0 || config.PasswordConfig.Argon2Parallelism > 255 {
+            return nil, fmt.Errorf("ARGON2_MEMORY_KB 和 ARGON2_ITERATIONS 必须大于0，ARGON2_PARALLELISM 必须在1到255之间")
+        }
+    case PasswordBcrypt:
+        // bcrypt只处理前72字节，更长的密码无法哈希
+        if config.PasswordConfig.MaxLength > 72 {
+            return nil, fmt.Errorf("PASSWORD_HASH_ALGORITHM=bcrypt 时 PASSWORD_MAX_LENGTH 不能超过72")
+        }
+    default:
+        return nil, fmt.Errorf("不支持的密码哈希算法: %s", config.PasswordConfig.Algorithm)
+    }
+
+    config.OIDCConfig = OIDC{
+        Issuer:       strings.TrimSpace(viper.GetString("OIDC_ISSUER")),
+        ClientID:     viper.GetString("OIDC_CLIENT_ID"),
+        ClientSecret: viper.GetString("OIDC_CLIENT_SECRET"),
+        RedirectURL:  viper.GetString("OIDC_REDIRECT_URL"),
+        Scopes:       strings.FieldsFunc(viper.GetString("OIDC_SCOPES"), func(r rune) bool { return r == ' ' || r == ',' }),
+        LinkByEmail:  viper.GetBool("OIDC_LINK_BY_EMAIL"),
+    }
+    if config.OIDCConfig.Enabled() {
+        if config.OIDCConfig.ClientID == "" {
+            return nil, fmt.Errorf("启用单点登录（OIDC_ISSUER）时需要配置 OIDC_CLIENT_ID")
+        }
+        if config.OIDCConfig.RedirectURL == "" {
+            config.OIDCConfig.RedirectURL = config.AccountConfig.BaseURL + "/oidc/callback"
+        }
+    }
+
+    switch config.SearchBackend {
+    case SearchMemory:
+    case SearchMySQL:
+        if config.DBConfig.Driver != DriverMySQL {
+            return nil, fmt.Errorf("SEARCH_BACKEND=mysql 需要 DB_DRIVER=mysql")
+        }
+    default:
+        return nil, fmt.Errorf("不支持的搜索后端: %s", config.SearchBackend)
+    }
 
     return &config, nil
 }
+
+// parseReactionEmojis 解析逗号分隔的表情列表，表情不能重复、不能包含空白
+func parseReactionEmojis(value string) ([]string, error) {
+    var emojis []string
+    seen := make(map[string]bool)
+    for _, emoji := range strings.Split(value, ",") {
+        emoji = strings.TrimSpace(emoji)
+        if emoji == "" {
+            continue
+        }
+        if len(emoji) > model.MaxReactionEmojiBytes || strings.ContainsAny(emoji, " \t\r\n/") {
+            return nil, fmt.Errorf("%q 不是有效的表情", emoji)
+        }
+        if seen[emoji] {
+            return nil, fmt.Errorf("%s 重复", emoji)
+        }
+        seen[emoji] = true
+        emojis = append(emojis, emoji)
+    }
+    if len(emojis) == 0 {
+        return nil, fmt.Errorf("至少需要一个表情")
+    }
+    if len(emojis) > maxReactionEmojis {
+        return nil, fmt.Errorf("最多%d个表情", maxReactionEmojis)
+    }
+    return emojis, nil
+}
+
+// parseRateRule 解析 次数/周期 格式的限流规则，周期为 s、m 或 h，空字符串或0表示不限流
+func parseRateRule(value string) (RateRule, error) {
+    value = strings.TrimSpace(value)
+    if value == "" || value == "0" {
+        return RateRule{}, nil
+    }
+
+    count, unit, ok := strings.Cut(value, "/")
+    if !ok {
+        return RateRule{}, fmt.Errorf("应为 次数/周期，如 10/m")
+    }
+    requests, err := strconv.Atoi(strings.TrimSpace(count))
+    if err != nil || requests < 0 {
+        return RateRule{}, fmt.Errorf("无效的次数: %s", count)
+    }
+
+    var period time.Duration
+    switch strings.ToLower(strings.TrimSpace(unit)) {
+    case "s":
+        period = time.Second
+    case "m":
+        period = time.Minute
+    case "h":
+        period = time.Hour
+    default:
+        return RateRule{}, fmt.Errorf("无效的周期: %s，应为 s、m 或 h", unit)
+    }
+    return RateRule{Requests: requests, Period: period}, nil
+}
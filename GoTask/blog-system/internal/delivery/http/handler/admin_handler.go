@@ -0,0 +1,239 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "strconv"
+)
+
+// AdminHandler 管理员处理器
+type AdminHandler struct {
+    userUsecase usecase.UserUseCase
+    loginGuard  usecase.LoginGuard
+}
+
+// NewAdminHandler 创建管理员处理器
+func NewAdminHandler(userUsecase usecase.UserUseCase, loginGuard usecase.LoginGuard) *AdminHandler {
+    return &AdminHandler{userUsecase: userUsecase, loginGuard: loginGuard}
+}
+
+// ListUsers 获取用户列表
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    users, total, err := h.userUsecase.ListUsers(userID.(uint), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "users": users,
+        "total": total,
+        "page":  page,
+        "limit": limit,
+    })
+}
+
+// ListDeletedUsers 获取回收站中的用户列表
+func (h *AdminHandler) ListDeletedUsers(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    users, total, err := h.userUsecase.ListDeletedUsers(userID.(uint), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "users": users,
+        "total": total,
+        "page":  page,
+        "limit": limit,
+    })
+}
+
+// RestoreUser 从回收站恢复用户
+func (h *AdminHandler) RestoreUser(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    err = h.userUsecase.RestoreUser(userID.(uint), uint(id))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "用户已恢复")
+}
+
+// ChangeRole 修改用户角色
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    var req struct {
+        Role string `json:"role" binding:"required,oneof=user moderator admin"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    err = h.userUsecase.ChangeRole(userID.(uint), uint(id), req.Role)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "角色已更新")
+}
+
+// Ban 封禁用户
+func (h *AdminHandler) Ban(c *gin.Context) {
+    h.setBanned(c, true, "用户已封禁")
+}
+
+// Unban 解封用户
+func (h *AdminHandler) Unban(c *gin.Context) {
+    h.setBanned(c, false, "用户已解封")
+}
+
+func (h *AdminHandler) setBanned(c *gin.Context, banned bool, message string) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    err = h.userUsecase.SetBanned(userID.(uint), uint(id), banned)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, message)
+}
+
+// ListLoginAttempts 获取登录记录，可按username和ip筛选
+func (h *AdminHandler) ListLoginAttempts(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    attempts, total, err := h.loginGuard.ListAttempts(userID.(uint), c.Query("username"), c.Query("ip"), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "attempts": attempts,
+        "total":    total,
+        "page":     page,
+        "limit":    limit,
+    })
+}
+
+// ListLockouts 获取当前被锁定的用户名和IP，all=true时包含尚未锁定的失败计数
+func (h *AdminHandler) ListLockouts(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+    all := c.Query("all") == "true"
+
+    lockouts, total, err := h.loginGuard.ListLockouts(userID.(uint), all, page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "lockouts": lockouts,
+        "total":    total,
+        "page":     page,
+        "limit":    limit,
+    })
+}
+
+// ClearLockout 解除锁定
+func (h *AdminHandler) ClearLockout(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    err = h.loginGuard.ClearLockout(userID.(uint), uint(id))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "已解除锁定")
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "errors"
+    "net/http"
+    "strconv"
+)
+
+// APITokenHandler 个人访问令牌处理器
+type APITokenHandler struct {
+    apiTokenUsecase usecase.APITokenUseCase
+}
+
+// NewAPITokenHandler 创建个人访问令牌处理器
+func NewAPITokenHandler(apiTokenUsecase usecase.APITokenUseCase) *APITokenHandler {
+    return &APITokenHandler{apiTokenUsecase: apiTokenUsecase}
+}
+
+// Create 创建个人访问令牌，明文令牌只在响应中返回一次
+func (h *APITokenHandler) Create(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    var req struct {
+        Name          string   `json:"name" binding:"required"`
+        Scopes        []string `json:"scopes" binding:"required"`
+        ExpiresInDays int      `json:"expires_in_days"` // 0表示永不过期
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    mfa := false
+    if claims, exists := c.Get("claims"); exists {
+        mfa = claims.(*auth.JWTClaims).MFA
+    }
+
+    token, plain, err := h.apiTokenUsecase.Create(userID.(uint), mfa, req.Name, req.Scopes, req.ExpiresInDays)
+    if err != nil {
+        if errors.Is(err, usecase.ErrMFANotSatisfied) {
+            utils.RespondWithError(c, http.StatusForbidden, err.Error())
+            return
+        }
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusCreated, gin.H{"token": plain, "api_token": token})
+}
+
+// List 获取当前用户的个人访问令牌
+func (h *APITokenHandler) List(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    tokens, err := h.apiTokenUsecase.List(userID.(uint))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, tokens)
+}
+
+// Revoke 删除个人访问令牌
+func (h *APITokenHandler) Revoke(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    if err := h.apiTokenUsecase.Revoke(userID.(uint), uint(id)); err != nil {
+        utils.RespondWithError(c, http.StatusNotFound, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "令牌已删除")
+}
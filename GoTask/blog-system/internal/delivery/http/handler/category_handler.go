@@ -0,0 +1,113 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "strconv"
+)
+
+// CategoryHandler 分类处理器
+type CategoryHandler struct {
+    categoryUsecase usecase.CategoryUseCase
+}
+
+// NewCategoryHandler 创建分类处理器
+func NewCategoryHandler(categoryUsecase usecase.CategoryUseCase) *CategoryHandler {
+    return &CategoryHandler{categoryUsecase: categoryUsecase}
+}
+
+// GetAll 获取所有分类及文章数
+func (h *CategoryHandler) GetAll(c *gin.Context) {
+    categories, err := h.categoryUsecase.GetAll()
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, categories)
+}
+
+// Create 创建分类（管理员）
+func (h *CategoryHandler) Create(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    var req struct {
+        Name        string `json:"name" binding:"required,max=64"`
+        Description string `json:"description" binding:"max=255"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    category, err := h.categoryUsecase.Create(userID.(uint), req.Name, req.Description)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusCreated, category)
+}
+
+// Update 更新分类（管理员）
+func (h *CategoryHandler) Update(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    var req struct {
+        Name        string `json:"name" binding:"required,max=64"`
+        Description string `json:"description" binding:"max=255"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    err = h.categoryUsecase.Update(userID.(uint), uint(id), req.Name, req.Description)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "更新成功")
+}
+
+// Delete 删除分类（管理员）
+func (h *CategoryHandler) Delete(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    err = h.categoryUsecase.Delete(userID.(uint), uint(id))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "删除成功")
+}
@@ -33,7 +33,8 @@ func (h *CommentHandler) Create(c *gin.Context) {
     }
 
     var req struct {
-        Content string `json:"content" binding:"required"`
+        Content  string `json:"content" binding:"required"`
+        ParentID *uint  `json:"parent_id"`
     }
 
     if err := c.ShouldBindJSON(&req); err != nil {
@@ -41,7 +42,7 @@ func (h *CommentHandler) Create(c *gin.Context) {
         return
     }
 
-    err = h.commentUsecase.Create(req.Content, userID.(uint), uint(postID))
+    err = h.commentUsecase.Create(req.Content, userID.(uint), uint(postID), req.ParentID)
     if err != nil {
         utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
         return
@@ -50,7 +51,7 @@ func (h *CommentHandler) Create(c *gin.Context) {
     utils.RespondWithSuccess(c, http.StatusCreated, "评论成功")
 }
 
-// GetByPostID 获取指定文章的所有评论
+// GetByPostID 获取指定文章的所有评论，带after参数时按游标分页，否则按page分页
 func (h *CommentHandler) GetByPostID(c *gin.Context) {
     postID, err := strconv.ParseUint(c.Param("post_id"), 10, 32)
     if err != nil {
@@ -58,8 +59,34 @@ func (h *CommentHandler) GetByPostID(c *gin.Context) {
         return
     }
 
-    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+    if cursorQuery(c) {
+        limit, ok := parseLimit(c, maxPageLimit)
+        if !ok {
+            return
+        }
+        after, ok := parseCursor(c)
+        if !ok {
+            return
+        }
+
+        comments, next, err := h.commentUsecase.GetByPostIDAfter(uint(postID), after, limit)
+        if err != nil {
+            utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+            return
+        }
+
+        utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+            "comments":    comments,
+            "limit":       limit,
+            "next_cursor": encodeCursor(next),
+        })
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
 
     comments, total, err := h.commentUsecase.GetByPostID(uint(postID), page, limit)
     if err != nil {
@@ -75,6 +102,33 @@ func (h *CommentHandler) GetByPostID(c *gin.Context) {
     })
 }
 
+// GetTree 获取指定文章的评论树（按顶层评论分页）
+func (h *CommentHandler) GetTree(c *gin.Context) {
+    postID, err := strconv.ParseUint(c.Param("post_id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的文章ID")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxTreeLimit)
+    if !ok {
+        return
+    }
+
+    comments, total, err := h.commentUsecase.GetTree(uint(postID), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "comments": comments,
+        "total":    total,
+        "page":     page,
+        "limit":    limit,
+    })
+}
+
 // Delete 删除评论
 func (h *CommentHandler) Delete(c *gin.Context) {
     userID, exists := c.Get("userID")
@@ -96,4 +150,54 @@ func (h *CommentHandler) Delete(c *gin.Context) {
     }
 
     utils.RespondWithSuccess(c, http.StatusOK, "删除成功")
-}
\ No newline at end of file
+}
+
+// GetTrash 获取当前用户已删除的评论（分页）
+func (h *CommentHandler) GetTrash(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    comments, total, err := h.commentUsecase.GetTrash(userID.(uint), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "comments": comments,
+        "total":    total,
+        "page":     page,
+        "limit":    limit,
+    })
+}
+
+// Restore 从回收站恢复评论
+func (h *CommentHandler) Restore(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    err = h.commentUsecase.Restore(uint(id), userID.(uint))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "恢复成功")
+}
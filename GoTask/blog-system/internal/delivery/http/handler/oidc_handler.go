@@ -0,0 +1,149 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "crypto/subtle"
+    "net/http"
+    "strconv"
+)
+
+// oidcStateCookie 保存登录state的Cookie，回调时与提交的state比对，确保回调来自发起登录的浏览器
+const oidcStateCookie = "oidc_state"
+
+// oidcCookiePath oidcStateCookie只在单点登录接口下发送
+const oidcCookiePath = "/api/users/oidc"
+
+// OIDCHandler 单点登录处理器
+type OIDCHandler struct {
+    oidcUsecase usecase.OIDCUseCase
+}
+
+// NewOIDCHandler 创建单点登录处理器
+func NewOIDCHandler(oidcUsecase usecase.OIDCUseCase) *OIDCHandler {
+    return &OIDCHandler{oidcUsecase: oidcUsecase}
+}
+
+// callbackRequest 身份提供方回调到前端页面后，前端提交地址中的code和state
+type callbackRequest struct {
+    Code  string `json:"code" binding:"required"`
+    State string `json:"state" binding:"required"`
+}
+
+// Authorize 获取登录用的授权地址，前端跳转到该地址
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+    authURL, state, err := h.oidcUsecase.AuthorizationURL(0)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusServiceUnavailable, err.Error())
+        return
+    }
+
+    setStateCookie(c, state, int(usecase.OIDCStateTTL.Seconds()))
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{"authorization_url": authURL})
+}
+
+// Callback 使用授权码完成登录，响应与密码登录相同；state必须与发起登录时写入的Cookie一致
+func (h *OIDCHandler) Callback(c *gin.Context) {
+    var req callbackRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    cookie, err := c.Cookie(oidcStateCookie)
+    setStateCookie(c, "", -1)
+    if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(req.State)) != 1 {
+        utils.RespondWithError(c, http.StatusUnauthorized, "登录请求无效或已过期，请重新登录")
+        return
+    }
+
+    tokens, err := h.oidcUsecase.Login(req.Code, req.State, c.ClientIP(), c.Request.UserAgent())
+    respondLogin(c, tokens, err)
+}
+
+// setStateCookie 写入或清除（maxAge为-1）登录state的Cookie
+func setStateCookie(c *gin.Context, state string, maxAge int) {
+    c.SetSameSite(http.SameSiteLaxMode)
+    c.SetCookie(oidcStateCookie, state, maxAge, oidcCookiePath, "", c.Request.TLS != nil, true)
+}
+
+// AuthorizeLink 获取绑定外部身份用的授权地址
+func (h *OIDCHandler) AuthorizeLink(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    authURL, _, err := h.oidcUsecase.AuthorizationURL(userID.(uint))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusServiceUnavailable, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{"authorization_url": authURL})
+}
+
+// LinkCallback 使用授权码将外部身份绑定到当前用户
+func (h *OIDCHandler) LinkCallback(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    var req callbackRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    identity, err := h.oidcUsecase.Link(userID.(uint), req.Code, req.State)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, identity)
+}
+
+// ListIdentities 获取当前用户绑定的外部身份
+func (h *OIDCHandler) ListIdentities(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    identities, err := h.oidcUsecase.ListIdentities(userID.(uint))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, identities)
+}
+
+// Unlink 解除绑定外部身份
+func (h *OIDCHandler) Unlink(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    if err := h.oidcUsecase.Unlink(userID.(uint), uint(id)); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "已解除绑定")
+}
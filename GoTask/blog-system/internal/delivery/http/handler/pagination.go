@@ -0,0 +1,106 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// 分页参数
+const (
+    defaultPageLimit = 10
+    maxPageLimit     = 100
+    maxTreeLimit     = 50 // 评论树每个顶层评论还会带上全部回复
+    maxSearchLimit   = 50
+)
+
+// parsePagination 解析page和limit查询参数，不是整数时返回验证错误，
+// page小于1时按1处理，limit超出 1..maxLimit 时截断到边界
+func parsePagination(c *gin.Context, maxLimit int) (page, limit int, ok bool) {
+    page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+    if err != nil {
+        utils.RespondWithValidationError(c, "page", "page必须是整数")
+        return 0, 0, false
+    }
+    limit, ok = parseLimit(c, maxLimit)
+    if !ok {
+        return 0, 0, false
+    }
+    if page < 1 {
+        page = 1
+    }
+    return page, limit, true
+}
+
+// parseLimit 解析limit查询参数，规则同parsePagination
+func parseLimit(c *gin.Context, maxLimit int) (int, bool) {
+    limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageLimit)))
+    if err != nil {
+        utils.RespondWithValidationError(c, "limit", "limit必须是整数")
+        return 0, false
+    }
+    if limit < 1 {
+        limit = 1
+    }
+    if limit > maxLimit {
+        limit = maxLimit
+    }
+    return limit, true
+}
+
+// cursorQuery 请求是否使用游标分页，即带有after参数（值为空表示第一页）
+func cursorQuery(c *gin.Context) bool {
+    _, ok := c.GetQuery("after")
+    return ok
+}
+
+// parseCursor 解析after参数中的游标，为空时返回nil表示从第一条开始
+func parseCursor(c *gin.Context) (*repository.Cursor, bool) {
+    raw := c.Query("after")
+    if raw == "" {
+        return nil, true
+    }
+    cursor, err := decodeCursor(raw)
+    if err != nil {
+        utils.RespondWithValidationError(c, "after", "无效的游标")
+        return nil, false
+    }
+    return cursor, true
+}
+
+// encodeCursor 把游标编码为不透明的字符串，客户端只需原样传回；nil编码为null表示没有下一页
+func encodeCursor(cursor *repository.Cursor) *string {
+    if cursor == nil {
+        return nil
+    }
+    raw := fmt.Sprintf("%d:%d", cursor.CreatedAt.UnixNano(), cursor.ID)
+    encoded := base64.RawURLEncoding.EncodeToString([]byte(raw))
+    return &encoded
+}
+
+// decodeCursor 解析encodeCursor生成的字符串
+func decodeCursor(s string) (*repository.Cursor, error) {
+    raw, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return nil, err
+    }
+    nanos, id, found := strings.Cut(string(raw), ":")
+    if !found {
+        return nil, errors.New("游标格式错误")
+    }
+    n, err := strconv.ParseInt(nanos, 10, 64)
+    if err != nil || n <= 0 {
+        return nil, errors.New("游标时间错误")
+    }
+    i, err := strconv.ParseUint(id, 10, 32)
+    if err != nil || i == 0 {
+        return nil, errors.New("游标ID错误")
+    }
+    return &repository.Cursor{CreatedAt: time.Unix(0, n), ID: uint(i)}, nil
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
     "github.com/gin-gonic/gin"
     "net/http"
     "strconv"
+    "strings"
+    "time"
 )
 
 // PostHandler 文章处理器
@@ -27,8 +30,12 @@ func (h *PostHandler) Create(c *gin.Context) {
     }
 
     var req struct {
-        Title   string `json:"title" binding:"required"`
-        Content string `json:"content" binding:"required"`
+        Title       string     `json:"title" binding:"required"`
+        Content     string     `json:"content" binding:"required,max=100000"` // 最多100000个字符
+        Tags        []string   `json:"tags"`
+        CategoryIDs []uint     `json:"category_ids"`
+        Status      string     `json:"status" binding:"omitempty,oneof=draft published scheduled archived"`
+        PublishAt   *time.Time `json:"publish_at"`
     }
 
     if err := c.ShouldBindJSON(&req); err != nil {
@@ -36,7 +43,14 @@ func (h *PostHandler) Create(c *gin.Context) {
         return
     }
 
-    err := h.postUsecase.Create(req.Title, req.Content, userID.(uint))
+    err := h.postUsecase.Create(userID.(uint), usecase.PostInput{
+        Title:       req.Title,
+        Content:     req.Content,
+        Tags:        req.Tags,
+        CategoryIDs: req.CategoryIDs,
+        Status:      req.Status,
+        PublishAt:   req.PublishAt,
+    })
     if err != nil {
         utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
         return
@@ -53,7 +67,13 @@ func (h *PostHandler) GetByID(c *gin.Context) {
         return
     }
 
-    post, err := h.postUsecase.GetByID(uint(id))
+    // 登录用户可以查看自己未发布的文章
+    var viewerID uint
+    if userID, exists := c.Get("userID"); exists {
+        viewerID = userID.(uint)
+    }
+
+    post, err := h.postUsecase.GetByID(uint(id), viewerID)
     if err != nil {
         utils.RespondWithError(c, http.StatusNotFound, err.Error())
         return
@@ -62,12 +82,47 @@ func (h *PostHandler) GetByID(c *gin.Context) {
     utils.RespondWithSuccess(c, http.StatusOK, post)
 }
 
-// GetAll 获取所有文章
+// GetAll 获取所有文章，筛选和排序参数见 parsePostFilter；带after参数时按游标分页，否则按page分页
 func (h *PostHandler) GetAll(c *gin.Context) {
-    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+    filter, ok := parsePostFilter(c)
+    if !ok {
+        return
+    }
+
+    if cursorQuery(c) {
+        if !isDefaultPostSort(filter.Sort) {
+            utils.RespondWithValidationError(c, "sort", "游标分页只支持按创建时间倒序，请去掉sort或改用page分页")
+            return
+        }
+        limit, ok := parseLimit(c, maxPageLimit)
+        if !ok {
+            return
+        }
+        after, ok := parseCursor(c)
+        if !ok {
+            return
+        }
+
+        posts, next, err := h.postUsecase.GetAllAfter(filter, after, limit)
+        if err != nil {
+            utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+            return
+        }
 
-    posts, total, err := h.postUsecase.GetAll(page, limit)
+        utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+            "posts":       posts,
+            "limit":       limit,
+            "next_cursor": encodeCursor(next),
+        })
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    posts, total, err := h.postUsecase.GetAll(filter, page, limit)
     if err != nil {
         utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
         return
@@ -81,7 +136,7 @@ func (h *PostHandler) GetAll(c *gin.Context) {
     })
 }
 
-// GetByUserID 获取指定用户的所有文章
+// GetByUserID 获取指定用户的所有文章，分页方式同GetAll
 func (h *PostHandler) GetByUserID(c *gin.Context) {
     userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
     if err != nil {
@@ -89,8 +144,34 @@ func (h *PostHandler) GetByUserID(c *gin.Context) {
         return
     }
 
-    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+    if cursorQuery(c) {
+        limit, ok := parseLimit(c, maxPageLimit)
+        if !ok {
+            return
+        }
+        after, ok := parseCursor(c)
+        if !ok {
+            return
+        }
+
+        posts, next, err := h.postUsecase.GetByUserIDAfter(uint(userID), after, limit)
+        if err != nil {
+            utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+            return
+        }
+
+        utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+            "posts":       posts,
+            "limit":       limit,
+            "next_cursor": encodeCursor(next),
+        })
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
 
     posts, total, err := h.postUsecase.GetByUserID(uint(userID), page, limit)
     if err != nil {
@@ -106,6 +187,76 @@ func (h *PostHandler) GetByUserID(c *gin.Context) {
     })
 }
 
+// GetMine 获取当前用户自己的文章，支持 status 筛选
+func (h *PostHandler) GetMine(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    status := c.Query("status")
+    if status != "" && !model.IsValidPostStatus(status) {
+        utils.RespondWithValidationError(c, "status", "status只能是draft、published、scheduled或archived")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    posts, total, err := h.postUsecase.GetMine(userID.(uint), status, page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "posts": posts,
+        "total": total,
+        "page":  page,
+        "limit": limit,
+    })
+}
+
+// Search 全文搜索文章和评论
+func (h *PostHandler) Search(c *gin.Context) {
+    query := strings.TrimSpace(c.Query("q"))
+    if query == "" {
+        utils.RespondWithValidationError(c, "q", "搜索关键词不能为空")
+        return
+    }
+    if len([]rune(query)) > 100 {
+        utils.RespondWithValidationError(c, "q", "搜索关键词不能超过100个字符")
+        return
+    }
+
+    docType := c.Query("type")
+    if docType != "" && docType != model.SearchTypePost && docType != model.SearchTypeComment {
+        utils.RespondWithValidationError(c, "type", "type只能是post或comment")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxSearchLimit)
+    if !ok {
+        return
+    }
+
+    hits, total, err := h.postUsecase.Search(query, docType, page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "results": hits,
+        "total":   total,
+        "page":    page,
+        "limit":   limit,
+    })
+}
+
 // Update 更新文章
 func (h *PostHandler) Update(c *gin.Context) {
     userID, exists := c.Get("userID")
@@ -121,8 +272,12 @@ func (h *PostHandler) Update(c *gin.Context) {
     }
 
     var req struct {
-        Title   string `json:"title" binding:"required"`
-        Content string `json:"content" binding:"required"`
+        Title       string     `json:"title" binding:"required"`
+        Content     string     `json:"content" binding:"required,max=100000"` // 最多100000个字符
+        Tags        []string   `json:"tags"`
+        CategoryIDs []uint     `json:"category_ids"`
+        Status      string     `json:"status" binding:"omitempty,oneof=draft published scheduled archived"`
+        PublishAt   *time.Time `json:"publish_at"`
     }
 
     if err := c.ShouldBindJSON(&req); err != nil {
@@ -130,7 +285,14 @@ func (h *PostHandler) Update(c *gin.Context) {
         return
     }
 
-    err = h.postUsecase.Update(uint(id), userID.(uint), req.Title, req.Content)
+    err = h.postUsecase.Update(uint(id), userID.(uint), usecase.PostInput{
+        Title:       req.Title,
+        Content:     req.Content,
+        Tags:        req.Tags,
+        CategoryIDs: req.CategoryIDs,
+        Status:      req.Status,
+        PublishAt:   req.PublishAt,
+    })
     if err != nil {
         utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
         return
@@ -161,3 +323,210 @@ func (h *PostHandler) Delete(c *gin.Context) {
 
     utils.RespondWithSuccess(c, http.StatusOK, "删除成功")
 }
+
+// GetTrash 获取当前用户已删除的文章（分页）
+func (h *PostHandler) GetTrash(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    posts, total, err := h.postUsecase.GetTrash(userID.(uint), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "posts": posts,
+        "total": total,
+        "page":  page,
+        "limit": limit,
+    })
+}
+
+// Restore 从回收站恢复文章
+func (h *PostHandler) Restore(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    err = h.postUsecase.Restore(uint(id), userID.(uint))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "恢复成功")
+}
+
+// Hide 隐藏文章（版主）
+func (h *PostHandler) Hide(c *gin.Context) {
+    h.setHidden(c, true, "文章已隐藏")
+}
+
+// Unhide 取消隐藏文章（版主）
+func (h *PostHandler) Unhide(c *gin.Context) {
+    h.setHidden(c, false, "文章已恢复显示")
+}
+
+func (h *PostHandler) setHidden(c *gin.Context, hidden bool, message string) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    err = h.postUsecase.SetHidden(uint(id), userID.(uint), hidden)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, message)
+}
+
+// ListRevisions 获取文章的修订记录
+func (h *PostHandler) ListRevisions(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    revisions, total, err := h.postUsecase.ListRevisions(uint(id), userID.(uint), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "revisions": revisions,
+        "total":     total,
+        "page":      page,
+        "limit":     limit,
+    })
+}
+
+// GetRevision 获取指定版本的修订记录
+func (h *PostHandler) GetRevision(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    rev, err := strconv.Atoi(c.Param("rev"))
+    if err != nil || rev < 1 {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的版本号")
+        return
+    }
+
+    revision, err := h.postUsecase.GetRevision(uint(id), userID.(uint), rev)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, revision)
+}
+
+// DiffRevisions 比较两个修订版本
+func (h *PostHandler) DiffRevisions(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    from, err := strconv.Atoi(c.Query("from"))
+    if err != nil || from < 1 {
+        utils.RespondWithValidationError(c, "from", "from必须是有效的版本号")
+        return
+    }
+    to, err := strconv.Atoi(c.Query("to"))
+    if err != nil || to < 1 {
+        utils.RespondWithValidationError(c, "to", "to必须是有效的版本号")
+        return
+    }
+
+    result, err := h.postUsecase.DiffRevisions(uint(id), userID.(uint), from, to)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, result)
+}
+
+// RestoreRevision 将文章恢复到指定版本（仅作者）
+func (h *PostHandler) RestoreRevision(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    rev, err := strconv.Atoi(c.Param("rev"))
+    if err != nil || rev < 1 {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的版本号")
+        return
+    }
+
+    err = h.postUsecase.RestoreRevision(uint(id), userID.(uint), rev)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "已恢复到指定版本")
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "strings"
+    "time"
+)
+
+// 文章列表查询参数的限制
+const (
+    maxSortFields       = 3
+    maxTitleContainsLen = 100
+)
+
+// parsePostFilter 解析文章列表的筛选和排序参数，参数无效时返回验证错误
+//
+//   - tag、category：按标签名、分类名筛选
+//   - author：按作者用户名筛选
+//   - created_after、created_before：创建时间范围，RFC 3339 时间或 YYYY-MM-DD 日期（服务器时区的零点）
+//   - title_contains：标题包含的文字
+//   - sort：逗号分隔的排序字段，前缀 - 表示倒序，如 -created_at,title
+func parsePostFilter(c *gin.Context) (repository.PostFilter, bool) {
+    filter := repository.PostFilter{
+        Tag:           c.Query("tag"),
+        Category:      strings.TrimSpace(c.Query("category")),
+        Author:        strings.TrimSpace(c.Query("author")),
+        TitleContains: strings.TrimSpace(c.Query("title_contains")),
+    }
+
+    if len([]rune(filter.TitleContains)) > maxTitleContainsLen {
+        utils.RespondWithValidationError(c, "title_contains", "title_contains不能超过100个字符")
+        return filter, false
+    }
+
+    var ok bool
+    if filter.CreatedAfter, ok = parseTimeQuery(c, "created_after"); !ok {
+        return filter, false
+    }
+    if filter.CreatedBefore, ok = parseTimeQuery(c, "created_before"); !ok {
+        return filter, false
+    }
+    if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+        utils.RespondWithValidationError(c, "created_before", "created_before必须晚于created_after")
+        return filter, false
+    }
+
+    if filter.Sort, ok = parsePostSort(c); !ok {
+        return filter, false
+    }
+    return filter, true
+}
+
+// parseTimeQuery 解析时间查询参数，为空时返回nil
+func parseTimeQuery(c *gin.Context, field string) (*time.Time, bool) {
+    raw := strings.TrimSpace(c.Query(field))
+    if raw == "" {
+        return nil, true
+    }
+    if t, err := time.Parse(time.RFC3339, raw); err == nil {
+        // 转换为服务器时区，SQLite按字符串比较时间，时区必须与写入时一致
+        t = t.In(time.Local)
+        return &t, true
+    }
+    if t, err := time.ParseInLocation("2006-01-02", raw, time.Local); err == nil {
+        return &t, true
+    }
+    utils.RespondWithValidationError(c, field, field+"必须是RFC 3339时间或YYYY-MM-DD格式的日期")
+    return nil, false
+}
+
+// parsePostSort 解析sort参数，字段必须在 repository.PostSortFields 中且不能重复
+func parsePostSort(c *gin.Context) ([]repository.PostSort, bool) {
+    raw := strings.TrimSpace(c.Query("sort"))
+    if raw == "" {
+        return nil, true
+    }
+
+    parts := strings.Split(raw, ",")
+    if len(parts) > maxSortFields {
+        utils.RespondWithValidationError(c, "sort", "最多按3个字段排序")
+        return nil, false
+    }
+    sorts := make([]repository.PostSort, 0, len(parts))
+    seen := make(map[string]bool, len(parts))
+    for _, part := range parts {
+        part = strings.TrimSpace(part)
+        sort := repository.PostSort{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+        if !repository.IsValidPostSortField(sort.Field) {
+            utils.RespondWithValidationError(c, "sort", "不支持按 "+part+" 排序，可选字段："+strings.Join(repository.PostSortFields, "、"))
+            return nil, false
+        }
+        if seen[sort.Field] {
+            utils.RespondWithValidationError(c, "sort", sort.Field+" 重复")
+            return nil, false
+        }
+        seen[sort.Field] = true
+        sorts = append(sorts, sort)
+    }
+    return sorts, true
+}
+
+// isDefaultPostSort 排序是否与游标分页的顺序（创建时间倒序）一致
+func isDefaultPostSort(sorts []repository.PostSort) bool {
+    return len(sorts) == 0 || (len(sorts) == 1 && sorts[0] == repository.PostSort{Field: repository.PostSortCreatedAt, Desc: true})
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+)
+
+// ReactionHandler 表情回应和收藏处理器
+type ReactionHandler struct {
+    reactionUsecase usecase.ReactionUseCase
+}
+
+// NewReactionHandler 创建表情回应和收藏处理器
+func NewReactionHandler(reactionUsecase usecase.ReactionUseCase) *ReactionHandler {
+    return &ReactionHandler{reactionUsecase: reactionUsecase}
+}
+
+// ReactPost 回应文章，重复回应同一表情不会报错
+func (h *ReactionHandler) ReactPost(c *gin.Context) {
+    h.react(c, model.ReactionTargetPost, true)
+}
+
+// UnreactPost 取消对文章的回应，没有回应时不会报错
+func (h *ReactionHandler) UnreactPost(c *gin.Context) {
+    h.react(c, model.ReactionTargetPost, false)
+}
+
+// PostReactions 获取文章的回应统计，登录时附带本人的回应
+func (h *ReactionHandler) PostReactions(c *gin.Context) {
+    h.summary(c, model.ReactionTargetPost)
+}
+
+// ReactComment 回应评论，重复回应同一表情不会报错
+func (h *ReactionHandler) ReactComment(c *gin.Context) {
+    h.react(c, model.ReactionTargetComment, true)
+}
+
+// UnreactComment 取消对评论的回应，没有回应时不会报错
+func (h *ReactionHandler) UnreactComment(c *gin.Context) {
+    h.react(c, model.ReactionTargetComment, false)
+}
+
+// CommentReactions 获取评论的回应统计，登录时附带本人的回应
+func (h *ReactionHandler) CommentReactions(c *gin.Context) {
+    h.summary(c, model.ReactionTargetComment)
+}
+
+// Bookmark 收藏文章，重复收藏不会报错
+func (h *ReactionHandler) Bookmark(c *gin.Context) {
+    h.bookmark(c, true)
+}
+
+// Unbookmark 取消收藏，没有收藏时不会报错
+func (h *ReactionHandler) Unbookmark(c *gin.Context) {
+    h.bookmark(c, false)
+}
+
+// ListBookmarks 获取当前用户收藏的文章（分页），按收藏时间倒序
+func (h *ReactionHandler) ListBookmarks(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    posts, total, err := h.reactionUsecase.ListBookmarks(userID.(uint), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "posts": posts,
+        "total": total,
+        "page":  page,
+        "limit": limit,
+    })
+}
+
+// react 添加或取消回应，返回回应后的统计
+func (h *ReactionHandler) react(c *gin.Context, targetType string, add bool) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    var summary *model.ReactionSummary
+    if add {
+        summary, err = h.reactionUsecase.React(userID.(uint), targetType, uint(id), c.Param("emoji"))
+    } else {
+        summary, err = h.reactionUsecase.Unreact(userID.(uint), targetType, uint(id), c.Param("emoji"))
+    }
+    if err != nil {
+        h.respondWithError(c, err)
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, summary)
+}
+
+// summary 获取回应统计
+func (h *ReactionHandler) summary(c *gin.Context, targetType string) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    var viewerID uint
+    if userID, exists := c.Get("userID"); exists {
+        viewerID = userID.(uint)
+    }
+
+    summary, err := h.reactionUsecase.Summary(targetType, uint(id), viewerID)
+    if err != nil {
+        h.respondWithError(c, err)
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "counts": summary.Counts,
+        "total":  summary.Total,
+        "mine":   summary.Mine,
+        "emojis": h.reactionUsecase.Emojis(),
+    })
+}
+
+// bookmark 收藏或取消收藏，返回收藏状态和文章的收藏数
+func (h *ReactionHandler) bookmark(c *gin.Context, add bool) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    var count int64
+    if add {
+        count, err = h.reactionUsecase.Bookmark(userID.(uint), uint(id))
+    } else {
+        count, err = h.reactionUsecase.Unbookmark(userID.(uint), uint(id))
+    }
+    if err != nil {
+        h.respondWithError(c, err)
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "bookmarked":     add,
+        "bookmark_count": count,
+    })
+}
+
+// respondWithError 按错误类型选择状态码
+func (h *ReactionHandler) respondWithError(c *gin.Context, err error) {
+    switch {
+    case errors.Is(err, usecase.ErrInvalidReaction):
+        utils.RespondWithValidationError(c, "emoji", "不支持的表情，可用表情："+strings.Join(h.reactionUsecase.Emojis(), " "))
+    case errors.Is(err, usecase.ErrPostNotFound), errors.Is(err, usecase.ErrCommentNotFound):
+        utils.RespondWithError(c, http.StatusNotFound, err.Error())
+    default:
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+    }
+}
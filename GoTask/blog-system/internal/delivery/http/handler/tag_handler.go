@@ -0,0 +1,127 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "strconv"
+)
+
+// TagHandler 标签处理器
+type TagHandler struct {
+    tagUsecase usecase.TagUseCase
+}
+
+// NewTagHandler 创建标签处理器
+func NewTagHandler(tagUsecase usecase.TagUseCase) *TagHandler {
+    return &TagHandler{tagUsecase: tagUsecase}
+}
+
+// GetAll 获取所有标签
+func (h *TagHandler) GetAll(c *gin.Context) {
+    tags, err := h.tagUsecase.GetAll()
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, tags)
+}
+
+// Cloud 获取标签云（按文章数降序）
+func (h *TagHandler) Cloud(c *gin.Context) {
+    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+    if limit < 1 || limit > 200 {
+        limit = 50
+    }
+
+    tags, err := h.tagUsecase.Cloud(limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, tags)
+}
+
+// Create 创建标签
+func (h *TagHandler) Create(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    var req struct {
+        Name string `json:"name" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    tag, err := h.tagUsecase.Create(userID.(uint), req.Name)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusCreated, tag)
+}
+
+// Update 修改标签（版主）
+func (h *TagHandler) Update(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    var req struct {
+        Name string `json:"name" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    err = h.tagUsecase.Update(userID.(uint), uint(id), req.Name)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "更新成功")
+}
+
+// Delete 删除标签（版主）
+func (h *TagHandler) Delete(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    err = h.tagUsecase.Delete(userID.(uint), uint(id))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "删除成功")
+}
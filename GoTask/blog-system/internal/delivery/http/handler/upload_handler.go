@@ -0,0 +1,147 @@
+package handler
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "errors"
+    "io"
+    "net/http"
+    "strconv"
+    "strings"
+)
+
+// UploadHandler 文件上传处理器
+type UploadHandler struct {
+    uploadUsecase usecase.UploadUseCase
+    maxSize       int64
+}
+
+// NewUploadHandler 创建文件上传处理器，maxSize为单个文件的最大字节数
+func NewUploadHandler(uploadUsecase usecase.UploadUseCase, maxSize int64) *UploadHandler {
+    return &UploadHandler{uploadUsecase: uploadUsecase, maxSize: maxSize}
+}
+
+// Upload 上传文件，multipart表单字段file为文件，post_id可选
+func (h *UploadHandler) Upload(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    // 多留1MB给表单其他字段和multipart边界
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+1<<20)
+    file, err := c.FormFile("file")
+    if err != nil {
+        var tooLarge *http.MaxBytesError
+        if errors.As(err, &tooLarge) {
+            utils.RespondWithError(c, http.StatusRequestEntityTooLarge, "文件大小超过限制")
+            return
+        }
+        utils.RespondWithValidationError(c, "file", "请选择要上传的文件")
+        return
+    }
+    if file.Size > h.maxSize {
+        utils.RespondWithError(c, http.StatusRequestEntityTooLarge, "文件大小超过限制")
+        return
+    }
+
+    var postID *uint
+    if value := c.PostForm("post_id"); value != "" {
+        id, err := strconv.ParseUint(value, 10, 32)
+        if err != nil {
+            utils.RespondWithValidationError(c, "post_id", "无效的文章ID")
+            return
+        }
+        pid := uint(id)
+        postID = &pid
+    }
+
+    f, err := file.Open()
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+    defer f.Close()
+
+    attachment, err := h.uploadUsecase.Upload(userID.(uint), usecase.UploadInput{
+        Filename: file.Filename,
+        Reader:   f,
+        PostID:   postID,
+    })
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusCreated, attachment)
+}
+
+// GetMine 获取当前用户上传的附件（分页）
+func (h *UploadHandler) GetMine(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    page, limit, ok := parsePagination(c, maxPageLimit)
+    if !ok {
+        return
+    }
+
+    attachments, total, err := h.uploadUsecase.GetMine(userID.(uint), page, limit)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+        "attachments": attachments,
+        "total":       total,
+        "page":        page,
+        "limit":       limit,
+    })
+}
+
+// Delete 删除附件
+func (h *UploadHandler) Delete(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, "无效的ID")
+        return
+    }
+
+    if err := h.uploadUsecase.Delete(uint(id), userID.(uint)); err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "删除成功")
+}
+
+// Serve 读取已上传的文件
+func (h *UploadHandler) Serve(c *gin.Context) {
+    key := strings.TrimPrefix(c.Param("key"), "/")
+    f, contentType, err := h.uploadUsecase.Open(key)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusNotFound, "文件不存在")
+        return
+    }
+    defer f.Close()
+
+    // 禁止浏览器猜测类型，避免上传的文本被当作HTML执行
+    c.Header("Content-Type", contentType)
+    c.Header("X-Content-Type-Options", "nosniff")
+    c.Header("Content-Security-Policy", "sandbox")
+    c.Header("Cache-Control", "public, max-age=31536000, immutable")
+    c.Status(http.StatusOK)
+    io.Copy(c.Writer, f)
+}
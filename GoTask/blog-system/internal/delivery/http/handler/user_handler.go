@@ -1,21 +1,29 @@
 package handler
 
 import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/usecase"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
     "github.com/gin-gonic/gin"
+    "errors"
+    "fmt"
+    "math"
     "net/http"
     "strconv"
+    "time"
 )
 
 // UserHandler 用户处理器
 type UserHandler struct {
-    userUsecase usecase.UserUseCase
+    userUsecase   usecase.UserUseCase
+    exportUsecase usecase.ExportUseCase
 }
 
 // NewUserHandler 创建用户处理器
-func NewUserHandler(userUsecase usecase.UserUseCase) *UserHandler {
-    return &UserHandler{userUsecase: userUsecase}
+func NewUserHandler(userUsecase usecase.UserUseCase, exportUsecase usecase.ExportUseCase) *UserHandler {
+    return &UserHandler{userUsecase: userUsecase, exportUsecase: exportUsecase}
 }
 
 // Register 用户注册
@@ -33,6 +41,11 @@ func (h *UserHandler) Register(c *gin.Context) {
 
     err := h.userUsecase.Register(req.Username, req.Password, req.Email)
     if err != nil {
+        var weak *usecase.WeakPasswordError
+        if errors.As(err, &weak) {
+            utils.RespondWithValidationError(c, "password", weak.Reason)
+            return
+        }
         utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
         return
     }
@@ -52,13 +65,166 @@ func (h *UserHandler) Login(c *gin.Context) {
         return
     }
 
-    token, err := h.userUsecase.Login(req.Username, req.Password)
+    tokens, err := h.userUsecase.Login(req.Username, req.Password, c.ClientIP(), c.Request.UserAgent())
+    respondLogin(c, tokens, err)
+}
+
+// VerifyTwoFactor 提交两步验证码或恢复码完成登录
+func (h *UserHandler) VerifyTwoFactor(c *gin.Context) {
+    var req struct {
+        MFAToken string `json:"mfa_token" binding:"required"`
+        Code     string `json:"code" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    tokens, err := h.userUsecase.VerifyTwoFactor(req.MFAToken, req.Code, c.ClientIP(), c.Request.UserAgent())
+    if err != nil {
+        respondLoginError(c, err)
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, tokens)
+}
+
+// respondLogin 登录的响应，需要两步验证时返回mfa_token，客户端使用它提交验证码
+func respondLogin(c *gin.Context, tokens *auth.TokenPair, err error) {
+    var mfa *usecase.MFARequiredError
+    if errors.As(err, &mfa) {
+        utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+            "mfa_required": true,
+            "mfa_token":    mfa.Token,
+            "expires_in":   mfa.ExpiresIn,
+        })
+        return
+    }
+    if err != nil {
+        respondLoginError(c, err)
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, tokens)
+}
+
+// respondLoginError 登录失败的响应，被锁定时返回429和Retry-After，其他错误返回401
+func respondLoginError(c *gin.Context, err error) {
+    var locked *usecase.LoginLockedError
+    if errors.As(err, &locked) {
+        retryAfter := int(math.Ceil(time.Until(locked.Until).Seconds()))
+        c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+        utils.RespondWithError(c, http.StatusTooManyRequests, err.Error())
+        return
+    }
+    utils.RespondWithError(c, http.StatusUnauthorized, err.Error())
+}
+
+// Refresh 刷新令牌
+func (h *UserHandler) Refresh(c *gin.Context) {
+    var req struct {
+        RefreshToken string `json:"refresh_token" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    tokens, err := h.userUsecase.Refresh(req.RefreshToken)
     if err != nil {
         utils.RespondWithError(c, http.StatusUnauthorized, err.Error())
         return
     }
 
-    utils.RespondWithSuccess(c, http.StatusOK, gin.H{"token": token})
+    utils.RespondWithSuccess(c, http.StatusOK, tokens)
+}
+
+// VerifyEmail 使用邮件中的令牌验证邮箱
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+    var req struct {
+        Token string `json:"token" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    if err := h.userUsecase.VerifyEmail(req.Token); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "邮箱验证成功")
+}
+
+// ForgotPassword 发送找回密码邮件，无论邮箱是否注册都返回成功
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+    var req struct {
+        Email string `json:"email" binding:"required,email"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    if err := h.userUsecase.ForgotPassword(req.Email); err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "如果该邮箱已注册，重置密码的链接已发送到该邮箱")
+}
+
+// ResetPassword 使用找回密码邮件中的令牌设置新密码
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+    var req struct {
+        Token       string `json:"token" binding:"required"`
+        NewPassword string `json:"new_password" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    if err := h.userUsecase.ResetPassword(req.Token, req.NewPassword); err != nil {
+        var weak *usecase.WeakPasswordError
+        if errors.As(err, &weak) {
+            utils.RespondWithValidationError(c, "new_password", weak.Reason)
+            return
+        }
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "密码已重置，请重新登录")
+}
+
+// Logout 用户登出
+func (h *UserHandler) Logout(c *gin.Context) {
+    claims, exists := c.Get("claims")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    // 请求体可选，携带refresh_token时一并吊销
+    var req struct {
+        RefreshToken string `json:"refresh_token"`
+    }
+    _ = c.ShouldBindJSON(&req)
+
+    err := h.userUsecase.Logout(claims.(*auth.JWTClaims), req.RefreshToken)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "已登出")
 }
 
 // GetProfile 获取用户资料
@@ -78,6 +244,31 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
     utils.RespondWithSuccess(c, http.StatusOK, user)
 }
 
+// Export 以ZIP归档下载当前用户的全部数据
+func (h *UserHandler) Export(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    filename := fmt.Sprintf("blog-export-%d-%s.zip", userID.(uint), time.Now().Format("20060102"))
+    c.Header("Content-Type", "application/zip")
+    c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+    if err := h.exportUsecase.Export(userID.(uint), c.Writer); err != nil {
+        // 已经开始写入归档时无法再返回JSON错误，只能中断响应
+        if c.Writer.Written() {
+            logger.Error("导出用户数据失败", err)
+            c.Abort()
+            return
+        }
+        c.Writer.Header().Del("Content-Type")
+        c.Writer.Header().Del("Content-Disposition")
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+    }
+}
+
 // UpdateProfile 更新用户资料
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
     userID, exists := c.Get("userID")
@@ -96,7 +287,17 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
         return
     }
 
-    err := h.userUsecase.UpdateProfile(userID.(uint), req.Username, req.Email)
+    // 修改邮箱后可以通过邮件重置密码，启用了两步验证时需要令牌通过两步验证
+    user, err := h.userUsecase.GetProfile(userID.(uint))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+    if req.Email != user.Email && !h.requireMFA(c, userID.(uint)) {
+        return
+    }
+
+    err = h.userUsecase.UpdateProfile(userID.(uint), req.Username, req.Email)
     if err != nil {
         utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
         return
@@ -105,6 +306,53 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
     utils.RespondWithSuccess(c, http.StatusOK, "更新成功")
 }
 
+// ResendVerification 重新发送邮箱验证邮件
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    if err := h.userUsecase.ResendVerification(userID.(uint)); err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "验证邮件已发送")
+}
+
+// ChangePassword 修改密码，修改后需要重新登录
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    var req struct {
+        OldPassword string `json:"old_password" binding:"required"`
+        NewPassword string `json:"new_password" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    if err := h.userUsecase.ChangePassword(userID.(uint), req.OldPassword, req.NewPassword); err != nil {
+        var weak *usecase.WeakPasswordError
+        if errors.As(err, &weak) {
+            utils.RespondWithValidationError(c, "new_password", weak.Reason)
+            return
+        }
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "密码已修改，请重新登录")
+}
+
 // DeleteUser 删除用户账号
 func (h *UserHandler) DeleteUser(c *gin.Context) {
     userID, exists := c.Get("userID")
@@ -125,11 +373,156 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
         return
     }
     
-    err = h.userUsecase.DeleteUser(uint(id))
+    // mode决定名下文章和评论的处理方式，默认匿名化保留
+    mode := c.DefaultQuery("mode", model.AccountDeletionAnonymize)
+    if !model.IsValidDeletionMode(mode) {
+        utils.RespondWithValidationError(c, "mode", "mode只能是anonymize或cascade")
+        return
+    }
+    export, _ := strconv.ParseBool(c.DefaultQuery("export", "false"))
+    if !h.requireMFA(c, userID.(uint)) {
+        return
+    }
+    
+    data, err := h.userUsecase.DeleteUser(uint(id), mode, export)
     if err != nil {
         utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
         return
     }
     
+    if data != nil {
+        utils.RespondWithSuccess(c, http.StatusOK, gin.H{"export": data})
+        return
+    }
     utils.RespondWithSuccess(c, http.StatusOK, "账号已删除")
-}
\ No newline at end of file
+}
+
+// requireMFA 敏感操作检查当前令牌是否通过了两步验证，未通过时返回403并返回false
+func (h *UserHandler) requireMFA(c *gin.Context, userID uint) bool {
+    satisfied := false
+    if claims, exists := c.Get("claims"); exists {
+        satisfied = claims.(*auth.JWTClaims).MFA
+    }
+
+    if err := h.userUsecase.RequireMFA(userID, satisfied); err != nil {
+        if errors.Is(err, usecase.ErrMFANotSatisfied) {
+            utils.RespondWithError(c, http.StatusForbidden, err.Error())
+            return false
+        }
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return false
+    }
+    return true
+}
+
+// GetTwoFactor 获取两步验证状态
+func (h *UserHandler) GetTwoFactor(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    status, err := h.userUsecase.TwoFactorStatus(userID.(uint))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, status)
+}
+
+// SetupTwoFactor 生成TOTP密钥和otpauth地址
+func (h *UserHandler) SetupTwoFactor(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    setup, err := h.userUsecase.SetupTwoFactor(userID.(uint))
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, setup)
+}
+
+// EnableTwoFactor 使用验证码确认并启用两步验证，返回恢复码和新的令牌
+func (h *UserHandler) EnableTwoFactor(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    var req struct {
+        Code string `json:"code" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    result, err := h.userUsecase.EnableTwoFactor(userID.(uint), req.Code)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, result)
+}
+
+// DisableTwoFactor 关闭两步验证
+func (h *UserHandler) DisableTwoFactor(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    var req struct {
+        Password string `json:"password" binding:"required"`
+        Code     string `json:"code" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    if err := h.userUsecase.DisableTwoFactor(userID.(uint), req.Password, req.Code); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, "两步验证已关闭")
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (h *UserHandler) RegenerateRecoveryCodes(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+        return
+    }
+
+    var req struct {
+        Code string `json:"code" binding:"required"`
+    }
+
+    if err := c.ShouldBindJSON(&req); err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    codes, err := h.userUsecase.RegenerateRecoveryCodes(userID.(uint), req.Code)
+    if err != nil {
+        utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    utils.RespondWithSuccess(c, http.StatusOK, gin.H{"recovery_codes": codes})
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
     "github.com/gin-gonic/gin"
@@ -8,8 +9,14 @@ import (
     "strings"
 )
 
-// AuthMiddleware 认证中间件
-func AuthMiddleware(jwtService auth.JWTService) gin.HandlerFunc {
+// APITokenAuthenticator 个人访问令牌验证，返回令牌和所属用户
+type APITokenAuthenticator interface {
+    Authenticate(token string) (*model.APIToken, *model.User, error)
+}
+
+// AuthMiddleware 认证中间件，接受JWT访问令牌和个人访问令牌；
+// 使用个人访问令牌时在上下文中设置apiToken，由RequireScope检查权限范围
+func AuthMiddleware(jwtService auth.JWTService, apiTokens APITokenAuthenticator) gin.HandlerFunc {
     return func(c *gin.Context) {
         authHeader := c.GetHeader("Authorization")
         if authHeader == "" {
@@ -26,7 +33,23 @@ func AuthMiddleware(jwtService auth.JWTService) gin.HandlerFunc {
             return
         }
 
-        // 验证令牌
+        // 个人访问令牌带有固定前缀，角色以用户当前的角色为准
+        if strings.HasPrefix(parts[1], model.APITokenPrefix) {
+            token, user, err := apiTokens.Authenticate(parts[1])
+            if err != nil {
+                utils.RespondWithError(c, http.StatusUnauthorized, "无效的令牌")
+                c.Abort()
+                return
+            }
+
+            c.Set("userID", user.ID)
+            c.Set("role", user.Role)
+            c.Set("apiToken", token)
+            c.Next()
+            return
+        }
+
+        // 验证令牌（包括吊销检查）
         claims, err := jwtService.ValidateToken(parts[1])
         if err != nil {
             utils.RespondWithError(c, http.StatusUnauthorized, "无效的令牌")
@@ -36,6 +59,23 @@ func AuthMiddleware(jwtService auth.JWTService) gin.HandlerFunc {
 
         // 将用户ID存储在上下文中
         c.Set("userID", claims.UserID)
+        c.Set("role", claims.Role)
+        c.Set("claims", claims)
+        c.Next()
+    }
+}
+
+// OptionalAuthMiddleware 可选认证中间件，携带有效的JWT访问令牌时设置用户信息，否则按匿名访问继续处理
+func OptionalAuthMiddleware(jwtService auth.JWTService) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        parts := strings.Split(c.GetHeader("Authorization"), " ")
+        if len(parts) == 2 && parts[0] == "Bearer" {
+            if claims, err := jwtService.ValidateToken(parts[1]); err == nil {
+                c.Set("userID", claims.UserID)
+                c.Set("role", claims.Role)
+                c.Set("claims", claims)
+            }
+        }
         c.Next()
     }
-}
\ No newline at end of file
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "net/http"
+)
+
+// RequireRole 角色校验中间件，需放在AuthMiddleware之后
+func RequireRole(roles ...string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        role, exists := c.Get("role")
+        if !exists {
+            utils.RespondWithError(c, http.StatusUnauthorized, "未授权")
+            c.Abort()
+            return
+        }
+
+        for _, r := range roles {
+            if role.(string) == r {
+                c.Next()
+                return
+            }
+        }
+
+        utils.RespondWithError(c, http.StatusForbidden, "没有权限访问")
+        c.Abort()
+    }
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "net/http"
+)
+
+// RequireScope 个人访问令牌的权限范围校验，需放在AuthMiddleware之后
+// GET和HEAD请求需要 resource:read 或 resource:write，其他请求需要 resource:write；JWT访问令牌不受限制
+func RequireScope(resource string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        value, exists := c.Get("apiToken")
+        if !exists {
+            c.Next()
+            return
+        }
+
+        write := c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead
+        if !value.(*model.APIToken).Allows(resource, write) {
+            action := "read"
+            if write {
+                action = "write"
+            }
+            utils.RespondWithError(c, http.StatusForbidden, "令牌缺少权限范围 "+resource+":"+action)
+            c.Abort()
+            return
+        }
+        c.Next()
+    }
+}
+
+// RequireJWT 只接受登录签发的JWT访问令牌，用于修改密码、管理令牌等账号操作，需放在AuthMiddleware之后
+func RequireJWT() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if _, exists := c.Get("apiToken"); exists {
+            utils.RespondWithError(c, http.StatusForbidden, "个人访问令牌不能用于该操作，请使用登录令牌")
+            c.Abort()
+            return
+        }
+        c.Next()
+    }
+}
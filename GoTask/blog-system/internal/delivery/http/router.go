@@ -3,7 +3,9 @@ package http
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/delivery/http/handler"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/delivery/http/middleware"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/ratelimit"
     "github.com/gin-gonic/gin"
 )
 
@@ -12,10 +14,29 @@ func SetupRouter(
     userHandler *handler.UserHandler,
     postHandler *handler.PostHandler,
     commentHandler *handler.CommentHandler,
+    adminHandler *handler.AdminHandler,
+    tagHandler *handler.TagHandler,
+    categoryHandler *handler.CategoryHandler,
+    uploadHandler *handler.UploadHandler,
+    oidcHandler *handler.OIDCHandler, // 未启用单点登录时为nil
+    apiTokenHandler *handler.APITokenHandler,
+    reactionHandler *handler.ReactionHandler,
     jwtService auth.JWTService,
+    apiTokens middleware.APITokenAuthenticator,
+    rateLimitStore ratelimit.Store,
+    rateLimits middleware.RateLimitRules,
 ) *gin.Engine {
     router := gin.Default()
 
+    // 认证中间件同时接受JWT和个人访问令牌，个人访问令牌只能访问设置了权限范围的路由组
+    authenticate := middleware.AuthMiddleware(jwtService, apiTokens)
+
+    // 限流中间件：登录和注册按IP计数，写操作按用户计数，发送邮件未登录时按IP、登录后按用户计数
+    loginLimit := middleware.RateLimit(rateLimitStore, "login", rateLimits.Login)
+    registerLimit := middleware.RateLimit(rateLimitStore, "register", rateLimits.Register)
+    writeLimit := middleware.RateLimitWrites(rateLimitStore, "write", rateLimits.Write)
+    emailLimit := middleware.RateLimit(rateLimitStore, "email", rateLimits.Email)
+
     // 健康检查
     router.GET("/health", func(c *gin.Context) {
         c.JSON(200, gin.H{
@@ -23,36 +44,98 @@ func SetupRouter(
         })
     })
 
+    // 验证令牌用的公钥，供其他服务使用；HS256时为空集合
+    router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+        c.Header("Cache-Control", "public, max-age=300")
+        c.JSON(200, jwtService.JWKS())
+    })
+
     // 用户相关路由
     userRoutes := router.Group("/api/users")
     {
-        userRoutes.POST("/register", userHandler.Register)
-        userRoutes.POST("/login", userHandler.Login)
+        userRoutes.POST("/register", registerLimit, userHandler.Register)
+        userRoutes.POST("/login", loginLimit, userHandler.Login)
+        userRoutes.POST("/refresh", loginLimit, userHandler.Refresh)
+        userRoutes.POST("/2fa/verify", loginLimit, userHandler.VerifyTwoFactor)
+        userRoutes.POST("/verify-email", loginLimit, userHandler.VerifyEmail)
+        userRoutes.POST("/forgot-password", emailLimit, userHandler.ForgotPassword)
+        userRoutes.POST("/reset-password", loginLimit, userHandler.ResetPassword)
         
-        // 需要认证的路由
+        // 需要认证的路由，个人访问令牌需要profile权限范围
+        profileRoutes := userRoutes.Group("/")
+        profileRoutes.Use(authenticate, middleware.RequireScope(model.ScopeProfile))
+        {
+            profileRoutes.GET("/profile", userHandler.GetProfile)
+            profileRoutes.GET("/export", userHandler.Export)
+        }
+
+        // 账号管理路由，只接受登录签发的JWT
         authUserRoutes := userRoutes.Group("/")
-        authUserRoutes.Use(middleware.AuthMiddleware(jwtService))
+        authUserRoutes.Use(authenticate, middleware.RequireJWT(), writeLimit)
         {
-            authUserRoutes.GET("/profile", userHandler.GetProfile)
+            authUserRoutes.POST("/logout", userHandler.Logout)
             authUserRoutes.PUT("/profile", userHandler.UpdateProfile)
+            authUserRoutes.PUT("/password", userHandler.ChangePassword)
+            authUserRoutes.POST("/verify-email/resend", emailLimit, userHandler.ResendVerification)
+            authUserRoutes.GET("/2fa", userHandler.GetTwoFactor)
+            authUserRoutes.POST("/2fa/setup", userHandler.SetupTwoFactor)
+            authUserRoutes.POST("/2fa/enable", loginLimit, userHandler.EnableTwoFactor)
+            authUserRoutes.POST("/2fa/disable", loginLimit, userHandler.DisableTwoFactor)
+            authUserRoutes.POST("/2fa/recovery-codes", loginLimit, userHandler.RegenerateRecoveryCodes)
+            authUserRoutes.GET("/tokens", apiTokenHandler.List)
+            authUserRoutes.POST("/tokens", apiTokenHandler.Create)
+            authUserRoutes.DELETE("/tokens/:id", apiTokenHandler.Revoke)
             authUserRoutes.DELETE("/:id", userHandler.DeleteUser)
         }
+
+        // 单点登录路由
+        if oidcHandler != nil {
+            userRoutes.GET("/oidc/authorize", loginLimit, oidcHandler.Authorize)
+            userRoutes.POST("/oidc/callback", loginLimit, oidcHandler.Callback)
+
+            authUserRoutes.GET("/oidc/link/authorize", oidcHandler.AuthorizeLink)
+            authUserRoutes.POST("/oidc/link/callback", loginLimit, oidcHandler.LinkCallback)
+            authUserRoutes.GET("/identities", oidcHandler.ListIdentities)
+            authUserRoutes.DELETE("/identities/:id", oidcHandler.Unlink)
+        }
     }
 
     // 文章相关路由
     postRoutes := router.Group("/api/posts")
     {
         postRoutes.GET("", postHandler.GetAll)
-        postRoutes.GET("/:id", postHandler.GetByID)
+        postRoutes.GET("/search", postHandler.Search)
+        postRoutes.GET("/:id", middleware.OptionalAuthMiddleware(jwtService), postHandler.GetByID)
         postRoutes.GET("/user/:user_id", postHandler.GetByUserID)
+        postRoutes.GET("/:id/reactions", middleware.OptionalAuthMiddleware(jwtService), reactionHandler.PostReactions)
         
         // 需要认证的路由
         authPostRoutes := postRoutes.Group("/")
-        authPostRoutes.Use(middleware.AuthMiddleware(jwtService))
+        authPostRoutes.Use(authenticate, middleware.RequireScope(model.ScopePosts), writeLimit)
         {
+            authPostRoutes.GET("/mine", postHandler.GetMine)
+            authPostRoutes.GET("/trash", postHandler.GetTrash)
             authPostRoutes.POST("", postHandler.Create)
             authPostRoutes.PUT("/:id", postHandler.Update)
             authPostRoutes.DELETE("/:id", postHandler.Delete)
+            authPostRoutes.POST("/:id/restore", postHandler.Restore)
+            authPostRoutes.GET("/:id/revisions", postHandler.ListRevisions)
+            authPostRoutes.GET("/:id/revisions/diff", postHandler.DiffRevisions)
+            authPostRoutes.GET("/:id/revisions/:rev", postHandler.GetRevision)
+            authPostRoutes.POST("/:id/revisions/:rev/restore", postHandler.RestoreRevision)
+            authPostRoutes.PUT("/:id/reactions/:emoji", reactionHandler.ReactPost)
+            authPostRoutes.DELETE("/:id/reactions/:emoji", reactionHandler.UnreactPost)
+            authPostRoutes.GET("/bookmarks", reactionHandler.ListBookmarks)
+            authPostRoutes.PUT("/:id/bookmark", reactionHandler.Bookmark)
+            authPostRoutes.DELETE("/:id/bookmark", reactionHandler.Unbookmark)
+        }
+
+        // 版主路由
+        modPostRoutes := postRoutes.Group("/")
+        modPostRoutes.Use(authenticate, middleware.RequireScope(model.ScopeAdmin), middleware.RequireRole(model.RoleModerator, model.RoleAdmin))
+        {
+            modPostRoutes.POST("/:id/hide", postHandler.Hide)
+            modPostRoutes.POST("/:id/unhide", postHandler.Unhide)
         }
     }
 
@@ -60,15 +143,85 @@ func SetupRouter(
     commentRoutes := router.Group("/api/comments")
     {
         commentRoutes.GET("/post/:post_id", commentHandler.GetByPostID)
+        commentRoutes.GET("/post/:post_id/tree", commentHandler.GetTree)
+        commentRoutes.GET("/:id/reactions", middleware.OptionalAuthMiddleware(jwtService), reactionHandler.CommentReactions)
         
         // 需要认证的路由
         authCommentRoutes := commentRoutes.Group("/")
-        authCommentRoutes.Use(middleware.AuthMiddleware(jwtService))
+        authCommentRoutes.Use(authenticate, middleware.RequireScope(model.ScopeComments), writeLimit)
         {
             authCommentRoutes.POST("/post/:post_id", commentHandler.Create)
             authCommentRoutes.DELETE("/:id", commentHandler.Delete)
+            authCommentRoutes.GET("/trash", commentHandler.GetTrash)
+            authCommentRoutes.POST("/:id/restore", commentHandler.Restore)
+            authCommentRoutes.PUT("/:id/reactions/:emoji", reactionHandler.ReactComment)
+            authCommentRoutes.DELETE("/:id/reactions/:emoji", reactionHandler.UnreactComment)
         }
     }
 
+    // 标签相关路由
+    tagRoutes := router.Group("/api/tags")
+    {
+        tagRoutes.GET("", tagHandler.GetAll)
+        tagRoutes.GET("/cloud", tagHandler.Cloud)
+
+        // 需要认证的路由
+        authTagRoutes := tagRoutes.Group("/")
+        authTagRoutes.Use(authenticate, middleware.RequireScope(model.ScopeTags), writeLimit)
+        {
+            authTagRoutes.POST("", tagHandler.Create)
+        }
+
+        // 版主路由
+        modTagRoutes := tagRoutes.Group("/")
+        modTagRoutes.Use(authenticate, middleware.RequireScope(model.ScopeAdmin), middleware.RequireRole(model.RoleModerator, model.RoleAdmin))
+        {
+            modTagRoutes.PUT("/:id", tagHandler.Update)
+            modTagRoutes.DELETE("/:id", tagHandler.Delete)
+        }
+    }
+
+    // 分类相关路由
+    categoryRoutes := router.Group("/api/categories")
+    {
+        categoryRoutes.GET("", categoryHandler.GetAll)
+
+        // 管理员路由
+        adminCategoryRoutes := categoryRoutes.Group("/")
+        adminCategoryRoutes.Use(authenticate, middleware.RequireScope(model.ScopeAdmin), middleware.RequireRole(model.RoleAdmin))
+        {
+            adminCategoryRoutes.POST("", categoryHandler.Create)
+            adminCategoryRoutes.PUT("/:id", categoryHandler.Update)
+            adminCategoryRoutes.DELETE("/:id", categoryHandler.Delete)
+        }
+    }
+
+    // 上传相关路由
+    uploadRoutes := router.Group("/api/uploads")
+    uploadRoutes.Use(authenticate, middleware.RequireScope(model.ScopeUploads), writeLimit)
+    {
+        uploadRoutes.POST("", uploadHandler.Upload)
+        uploadRoutes.GET("", uploadHandler.GetMine)
+        uploadRoutes.DELETE("/:id", uploadHandler.Delete)
+    }
+
+    // 已上传文件的访问地址
+    router.GET("/uploads/*key", uploadHandler.Serve)
+
+    // 管理员路由
+    adminRoutes := router.Group("/api/admin")
+    adminRoutes.Use(authenticate, middleware.RequireScope(model.ScopeAdmin), middleware.RequireRole(model.RoleAdmin))
+    {
+        adminRoutes.GET("/users", adminHandler.ListUsers)
+        adminRoutes.GET("/users/trash", adminHandler.ListDeletedUsers)
+        adminRoutes.POST("/users/:id/restore", adminHandler.RestoreUser)
+        adminRoutes.PUT("/users/:id/role", adminHandler.ChangeRole)
+        adminRoutes.POST("/users/:id/ban", adminHandler.Ban)
+        adminRoutes.POST("/users/:id/unban", adminHandler.Unban)
+        adminRoutes.GET("/login-attempts", adminHandler.ListLoginAttempts)
+        adminRoutes.GET("/lockouts", adminHandler.ListLockouts)
+        adminRoutes.DELETE("/lockouts/:id", adminHandler.ClearLockout)
+    }
+
     return router
 }
\ No newline at end of file
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+)
+
+// APITokenPrefix 个人访问令牌的前缀，用于区分JWT，也便于在代码和日志中识别泄露的令牌
+const APITokenPrefix = "blog_pat_"
+
+// 个人访问令牌可以访问的资源，权限范围的格式为 资源:read 或 资源:write，write包含read
+const (
+	ScopeProfile  = "profile"
+	ScopePosts    = "posts"
+	ScopeComments = "comments"
+	ScopeTags     = "tags"
+	ScopeUploads  = "uploads"
+	ScopeAdmin    = "admin" // 版主和管理员接口，仍然要求用户具有相应角色
+)
+
+// APITokenScopes 可以授予个人访问令牌的权限范围
+var APITokenScopes = []string{
+	"profile:read",
+	"posts:read", "posts:write",
+	"comments:read", "comments:write",
+	"tags:write",
+	"uploads:read", "uploads:write",
+	"admin:read", "admin:write",
+}
+
+// APIToken 个人访问令牌，用于脚本和CI等自动化场景，只保存令牌的SHA-256哈希
+type APIToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"size:100;not null"`
+	TokenHash  string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Hint       string     `json:"hint" gorm:"size:4;not null"` // 令牌的最后4个字符，便于用户辨认
+	Scopes     []string   `json:"scopes" gorm:"size:255;not null;serializer:json"`
+	ExpiresAt  *time.Time `json:"expires_at" gorm:"index"` // 为空时永不过期
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsValidScope 检查权限范围是否可以授予个人访问令牌
+func IsValidScope(scope string) bool {
+	for _, s := range APITokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows 令牌是否可以访问resource，write表示写操作，只读请求有 资源:read 或 资源:write 即可
+func (t *APIToken) Allows(resource string, write bool) bool {
+	for _, scope := range t.Scopes {
+		if scope == resource+":write" || (!write && scope == resource+":read") {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired 令牌在now时是否已过期
+func (t *APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(now)
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Attachment 上传的图片或附件，文件本身保存在文件存储中
+type Attachment struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"not null;index"`         // 上传者
+	PostID       *uint     `json:"post_id" gorm:"index"`                  // 所属文章，为空表示尚未关联文章
+	Filename     string    `json:"filename" gorm:"size:255;not null"`     // 上传时的原始文件名
+	ContentType  string    `json:"content_type" gorm:"size:100;not null"` // 根据文件内容识别的MIME类型
+	Size         int64     `json:"size" gorm:"not null"`
+	Width        int       `json:"width,omitempty"`  // 图片宽度，非图片为0
+	Height       int       `json:"height,omitempty"` // 图片高度，非图片为0
+	StorageKey   string    `json:"-" gorm:"size:255;not null;uniqueIndex"`
+	ThumbnailKey string    `json:"-" gorm:"size:255"`
+	URL          string    `json:"url" gorm:"size:1024;not null"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty" gorm:"size:1024"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// IsImage 附件是否为图片
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"time"
+)
+
+// Bookmark 用户收藏的文章
+type Bookmark struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_bookmarks_user_post,priority:1"`
+	PostID    uint      `json:"post_id" gorm:"not null;index;uniqueIndex:idx_bookmarks_user_post,priority:2"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -2,15 +2,24 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Comment 评论模型
 type Comment struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Content   string    `json:"content" gorm:"not null"`
-	UserID    uint      `json:"user_id"`
-	User      User      `json:"user" gorm:"foreignKey:UserID"`
-	PostID    uint      `json:"post_id"`
-	Post      Post      `json:"post" gorm:"foreignKey:PostID"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            uint           `json:"id" gorm:"primaryKey;index:idx_comments_post_id_created_at_id,priority:3"`
+	Content       string         `json:"content" gorm:"not null"`
+	UserID        uint           `json:"user_id"`
+	User          User           `json:"user" gorm:"foreignKey:UserID"`
+	PostID        uint           `json:"post_id" gorm:"index:idx_comments_post_id_created_at_id,priority:1"`
+	Post          Post           `json:"post" gorm:"foreignKey:PostID"`
+	ParentID      *uint          `json:"parent_id" gorm:"index"`                  // 父评论ID，顶层评论为空
+	RootID        uint           `json:"root_id" gorm:"index;not null;default:0"` // 所属顶层评论ID，顶层评论为0
+	Depth         int            `json:"depth" gorm:"not null;default:0"`         // 嵌套层级，顶层评论为0
+	Deleted       bool           `json:"deleted" gorm:"not null;default:false"`   // 已删除但仍有回复的评论保留为占位
+	Replies       []*Comment     `json:"replies,omitempty" gorm:"-"`
+	ReactionCount int64          `json:"reaction_count" gorm:"not null;default:0"` // 回应总数，添加和取消回应时在同一事务中更新
+	CreatedAt     time.Time      `json:"created_at" gorm:"index:idx_comments_post_id_created_at_id,priority:2"`
+	DeletedAt     gorm.DeletedAt `json:"deleted_at" gorm:"index"` // 软删除，进入回收站
 }
@@ -0,0 +1,15 @@
+package model
+
+import (
+    "time"
+)
+
+// UserExport 用户数据导出，包含账号资料以及名下所有文章、评论、修订记录和收藏
+type UserExport struct {
+    User       *User           `json:"user"`
+    Posts      []*Post         `json:"posts"`
+    Comments   []*Comment      `json:"comments"`
+    Revisions  []*PostRevision `json:"revisions"`
+    Bookmarks  []*Bookmark     `json:"bookmarks"`
+    ExportedAt time.Time       `json:"exported_at"`
+}
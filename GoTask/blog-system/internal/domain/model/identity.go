@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+)
+
+// UserIdentity 绑定到用户的外部身份（OpenID Connect），issuer和subject唯一确定一个外部账号
+type UserIdentity struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Issuer    string    `json:"issuer" gorm:"size:255;not null;uniqueIndex:idx_user_identities_issuer_subject"`
+	Subject   string    `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_user_identities_issuer_subject"`
+	Email     string    `json:"email" gorm:"size:255"` // 绑定时身份提供方返回的邮箱，仅用于展示
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"` // 最近一次使用该身份登录的时间
+}
+
+// OIDCState 进行中的OpenID Connect授权请求，回调时校验并删除
+type OIDCState struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	StateHash    string    `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Nonce        string    `json:"-" gorm:"size:64;not null"`
+	CodeVerifier string    `json:"-" gorm:"size:128;not null"`
+	UserID       *uint     `json:"user_id"` // 已登录用户绑定外部身份时为该用户，登录时为空
+	ExpiresAt    time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName OIDCState对应的表名
+func (OIDCState) TableName() string {
+	return "oidc_states"
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+)
+
+// 登录尝试结果
+const (
+	LoginResultSuccess     = "success"      // 登录成功
+	LoginResultFailed      = "failed"       // 用户名、密码或两步验证码错误
+	LoginResultLocked      = "locked"       // 用户名或IP已被锁定，未校验密码
+	LoginResultBanned      = "banned"       // 密码正确但账号已被封禁
+	LoginResultMFARequired = "mfa_required" // 密码正确，等待提交两步验证码
+)
+
+// LoginAttempt 登录尝试审计记录
+type LoginAttempt struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Username  string    `json:"username" gorm:"size:100;not null;index"` // 请求中提交的用户名，可能不存在
+	UserID    *uint     `json:"user_id" gorm:"index"`                    // 用户名对应的用户，不存在时为空
+	IP        string    `json:"ip" gorm:"size:64;not null;index"`
+	UserAgent string    `json:"user_agent" gorm:"size:255"`
+	Result    string    `json:"result" gorm:"size:20;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// 登录锁定的计数维度
+const (
+	LockoutScopeUsername = "username"
+	LockoutScopeIP       = "ip"
+)
+
+// LoginLockout 按用户名或IP统计的连续登录失败次数及锁定状态
+type LoginLockout struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	Scope        string     `json:"scope" gorm:"size:20;not null;uniqueIndex:idx_login_lockouts_subject"`
+	Subject      string     `json:"subject" gorm:"size:100;not null;uniqueIndex:idx_login_lockouts_subject"` // 用户名或IP
+	Failures     int        `json:"failures" gorm:"not null;default:0"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"` // 为空或早于当前时间表示未锁定
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IsLocked 在now时是否处于锁定状态
+func (l *LoginLockout) IsLocked(now time.Time) bool {
+	return l.LockedUntil != nil && l.LockedUntil.After(now)
+}
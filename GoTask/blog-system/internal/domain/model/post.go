@@ -2,15 +2,49 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// 文章状态
+const (
+	PostStatusDraft     = "draft"     // 草稿，仅作者可见
+	PostStatusPublished = "published" // 已发布
+	PostStatusScheduled = "scheduled" // 定时发布，到达publish_at后由调度器发布
+	PostStatusArchived  = "archived"  // 已归档，不再公开展示
 )
 
 // Post 博客文章模型
 type Post struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Title     string    `json:"title" gorm:"not null"`
-	Content   string    `json:"content" gorm:"not null"`
-	UserID    uint      `json:"user_id"`
-	User      User      `json:"user" gorm:"foreignKey:UserID"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            uint           `json:"id" gorm:"primaryKey;index:idx_posts_created_at_id,priority:2"`
+	Title         string         `json:"title" gorm:"not null"`
+	Content       string         `json:"content" gorm:"not null"`       // Markdown原文
+	ContentHTML   string         `json:"content_html" gorm:"type:text"` // 由Content渲染并清洗后的HTML，保存时生成
+	UserID        uint           `json:"user_id"`
+	User          User           `json:"user" gorm:"foreignKey:UserID"`
+	Hidden        bool           `json:"hidden" gorm:"not null;default:false"` // 被版主隐藏
+	Status        string         `json:"status" gorm:"size:20;not null;default:'published';index"`
+	PublishAt     *time.Time     `json:"publish_at" gorm:"index"` // 定时发布时间，发布后为实际发布时间
+	Tags          []Tag          `json:"tags" gorm:"many2many:post_tags;"`
+	Categories    []Category     `json:"categories" gorm:"many2many:post_categories;"`
+	Attachments   []Attachment   `json:"attachments" gorm:"foreignKey:PostID"`
+	ReactionCount int64          `json:"reaction_count" gorm:"not null;default:0"`                   // 回应总数，添加和取消回应时在同一事务中更新
+	BookmarkCount int64          `json:"bookmark_count" gorm:"not null;default:0"`                   // 收藏数，同上
+	CreatedAt     time.Time      `json:"created_at" gorm:"index:idx_posts_created_at_id,priority:1"` // 与ID组成游标分页的排序键
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"deleted_at" gorm:"index"` // 软删除，进入回收站
+}
+
+// IsValidPostStatus 检查文章状态是否合法
+func IsValidPostStatus(status string) bool {
+	switch status {
+	case PostStatusDraft, PostStatusPublished, PostStatusScheduled, PostStatusArchived:
+		return true
+	}
+	return false
+}
+
+// IsPublic 文章是否对所有人可见
+func (p *Post) IsPublic() bool {
+	return p.Status == PostStatusPublished && !p.Hidden
 }
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+// 表情回应的目标类型
+const (
+	ReactionTargetPost    = "post"
+	ReactionTargetComment = "comment"
+)
+
+// MaxReactionEmojiBytes 表情的最大字节数，与reactions.emoji列的长度一致
+const MaxReactionEmojiBytes = 32
+
+// Reaction 用户对文章或评论的表情回应（点赞即 👍 回应），同一用户对同一目标的同一表情只有一条
+type Reaction struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"not null;index;uniqueIndex:idx_reactions_target_user_emoji,priority:3"`
+	TargetType string    `json:"target_type" gorm:"size:20;not null;uniqueIndex:idx_reactions_target_user_emoji,priority:1"`
+	TargetID   uint      `json:"target_id" gorm:"not null;uniqueIndex:idx_reactions_target_user_emoji,priority:2"`
+	Emoji      string    `json:"emoji" gorm:"size:32;not null;uniqueIndex:idx_reactions_target_user_emoji,priority:4"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReactionSummary 文章或评论的回应统计
+type ReactionSummary struct {
+	Counts map[string]int64 `json:"counts"` // 各表情的回应数，没有回应的表情不出现
+	Total  int64            `json:"total"`
+	Mine   []string         `json:"mine"` // 当前用户的回应，未登录时为空
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/diff"
+    "time"
+)
+
+// PostRevision 文章修订记录，保存每次修改后的标题和内容
+type PostRevision struct {
+    ID        uint      `json:"id" gorm:"primaryKey"`
+    PostID    uint      `json:"post_id" gorm:"not null;uniqueIndex:idx_post_revisions_post_revision"`
+    Revision  int       `json:"revision" gorm:"not null;uniqueIndex:idx_post_revisions_post_revision"` // 文章内从1开始递增的版本号
+    Title     string    `json:"title" gorm:"not null"`
+    Content   string    `json:"content" gorm:"not null"`
+    UserID    uint      `json:"user_id" gorm:"index"` // 修改人
+    CreatedAt time.Time `json:"created_at"`
+}
+
+// RevisionDiff 两个修订版本之间的差异
+type RevisionDiff struct {
+    PostID    uint        `json:"post_id"`
+    From      int         `json:"from"`
+    To        int         `json:"to"`
+    FromTitle string      `json:"from_title"`
+    ToTitle   string      `json:"to_title"`
+    Added     int         `json:"added"`
+    Removed   int         `json:"removed"`
+    Replaced  bool        `json:"replaced"` // 差异过大时不逐行比较，变化的部分按整体替换返回
+    Lines     []diff.Line `json:"lines"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+    "time"
+)
+
+// 搜索文档类型
+const (
+    SearchTypePost    = "post"
+    SearchTypeComment = "comment"
+)
+
+// SearchDocument 写入搜索索引的文档
+type SearchDocument struct {
+    Type      string    // post 或 comment
+    ID        uint      // 文章ID或评论ID
+    PostID    uint      // 所属文章ID，文章文档与ID相同
+    Title     string    // 文章标题，评论文档为所属文章的标题（仅用于展示）
+    Content   string
+    CreatedAt time.Time
+}
+
+// SearchHit 搜索结果
+type SearchHit struct {
+    Type      string    `json:"type"`
+    ID        uint      `json:"id"`
+    PostID    uint      `json:"post_id"`
+    Title     string    `json:"title"`
+    Snippet   string    `json:"snippet"` // 高亮片段，匹配词以<mark>包裹，其余内容已做HTML转义
+    Score     float64   `json:"score"`
+    CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+    "time"
+)
+
+// Tag 文章标签
+type Tag struct {
+    ID        uint      `json:"id" gorm:"primaryKey"`
+    Name      string    `json:"name" gorm:"size:32;uniqueIndex;not null"`
+    CreatedAt time.Time `json:"created_at"`
+}
+
+// Category 文章分类
+type Category struct {
+    ID          uint      `json:"id" gorm:"primaryKey"`
+    Name        string    `json:"name" gorm:"size:64;uniqueIndex;not null"`
+    Description string    `json:"description" gorm:"size:255"`
+    CreatedAt   time.Time `json:"created_at"`
+}
+
+// TagCount 标签及其文章数（标签云）
+type TagCount struct {
+    ID        uint   `json:"id"`
+    Name      string `json:"name"`
+    PostCount int64  `json:"post_count"`
+}
+
+// CategoryCount 分类及其文章数
+type CategoryCount struct {
+    ID          uint   `json:"id"`
+    Name        string `json:"name"`
+    Description string `json:"description"`
+    PostCount   int64  `json:"post_count"`
+}
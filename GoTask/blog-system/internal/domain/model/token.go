@@ -0,0 +1,21 @@
+package model
+
+import (
+    "time"
+)
+
+// RevokedToken 已吊销的令牌（按jti记录）
+type RevokedToken struct {
+    ID        uint      `json:"id" gorm:"primaryKey"`
+    JTI       string    `json:"jti" gorm:"size:64;uniqueIndex;not null"`
+    UserID    uint      `json:"user_id" gorm:"index"`
+    ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+    CreatedAt time.Time `json:"created_at"`
+}
+
+// UserTokenRevocation 用户级令牌吊销记录，每次吊销时Generation加1，令牌中的代数小于Generation时失效
+type UserTokenRevocation struct {
+    UserID     uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+    Generation uint64    `json:"generation" gorm:"not null;default:0"`
+    RevokedAt  time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+// UserTOTP 用户的TOTP两步验证设置
+type UserTOTP struct {
+	ID        uint       `json:"-" gorm:"primaryKey"`
+	UserID    uint       `json:"-" gorm:"not null;uniqueIndex"`
+	Secret    string     `json:"-" gorm:"size:64;not null"`   // Base32编码的密钥
+	EnabledAt *time.Time `json:"enabled_at"`                  // 为空表示已生成密钥但尚未用验证码确认
+	LastStep  int64      `json:"-" gorm:"not null;default:0"` // 最近一次使用的时间步，同一验证码不能使用两次
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Enabled 两步验证是否已启用
+func (t *UserTOTP) Enabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// RecoveryCode 两步验证恢复码，只保存SHA-256哈希，每个只能使用一次
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -2,14 +2,63 @@ package model
 
 import (
     "time"
+
+    "gorm.io/gorm"
+)
+
+// 用户角色
+const (
+    RoleUser      = "user"
+    RoleModerator = "moderator"
+    RoleAdmin     = "admin"
 )
 
+// 账号删除方式
+const (
+    // AccountDeletionAnonymize 保留文章和评论，作者改为已注销用户占位账号
+    AccountDeletionAnonymize = "anonymize"
+    // AccountDeletionCascade 文章和评论随账号一起移入回收站，仍有他人回复的评论保留为匿名占位
+    AccountDeletionCascade = "cascade"
+)
+
+// 已注销用户占位账号，匿名化后的内容归属于该账号，不能登录，也不能被注册
+const (
+    DeletedUsername = "[deleted]"
+    DeletedEmail    = "deleted@users.invalid"
+)
+
+// UnusablePassword 不是有效的密码哈希，任何密码都无法登录；用于占位账号和通过外部身份创建的账号
+const UnusablePassword = "-"
+
 // User 用户模型
 type User struct {
-    ID        uint      `json:"id" gorm:"primaryKey"`
-    Username  string    `json:"username" gorm:"unique;not null"`
-    Password  string    `json:"-" gorm:"not null"` // 密码不返回给前端
-    Email     string    `json:"email" gorm:"unique;not null"`
-    CreatedAt time.Time `json:"created_at"`
-    UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+    ID        uint           `json:"id" gorm:"primaryKey"`
+    Username  string         `json:"username" gorm:"unique;not null"`
+    Password  string         `json:"-" gorm:"not null"` // 密码不返回给前端
+    Email     string         `json:"email" gorm:"unique;not null"`
+    Role      string         `json:"role" gorm:"size:20;not null;default:'user'"`
+    Banned    bool           `json:"banned" gorm:"not null;default:false"`
+    Verified  bool           `json:"verified" gorm:"not null;default:false"` // 邮箱是否已验证，未验证的用户不能发表内容
+    CreatedAt time.Time      `json:"created_at"`
+    UpdatedAt time.Time      `json:"updated_at"`
+    DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"` // 软删除，进入回收站
+}
+
+// IsValidDeletionMode 检查账号删除方式是否合法
+func IsValidDeletionMode(mode string) bool {
+    return mode == AccountDeletionAnonymize || mode == AccountDeletionCascade
+}
+
+// IsReservedUser 是否为系统保留的用户名或邮箱
+func IsReservedUser(username, email string) bool {
+    return username == DeletedUsername || email == DeletedEmail
+}
+
+// IsValidRole 检查角色是否合法
+func IsValidRole(role string) bool {
+    switch role {
+    case RoleUser, RoleModerator, RoleAdmin:
+        return true
+    }
+    return false
+}
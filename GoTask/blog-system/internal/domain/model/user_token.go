@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// 一次性令牌的用途
+const (
+	TokenPurposeVerifyEmail   = "verify_email"   // 邮箱验证
+	TokenPurposeResetPassword = "reset_password" // 找回密码
+)
+
+// UserToken 通过邮件发送的一次性令牌，只保存令牌的SHA-256哈希
+type UserToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	Purpose   string     `json:"purpose" gorm:"size:20;not null"`
+	TokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Email     string     `json:"email" gorm:"size:255;not null"` // 发送时的邮箱，验证邮箱时必须与用户当前邮箱一致
+	ExpiresAt time.Time  `json:"expires_at" gorm:"index"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
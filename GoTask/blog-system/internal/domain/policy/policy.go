@@ -0,0 +1,84 @@
+package policy
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+)
+
+// IsAdmin 是否为管理员
+func IsAdmin(user *model.User) bool {
+    return user != nil && user.Role == model.RoleAdmin
+}
+
+// IsModerator 是否具有版主权限（管理员同样具备）
+func IsModerator(user *model.User) bool {
+    return user != nil && (user.Role == model.RoleModerator || user.Role == model.RoleAdmin)
+}
+
+// CanUpdatePost 只有作者可以修改文章内容
+func CanUpdatePost(user *model.User, post *model.Post) bool {
+    return user != nil && !user.Banned && post.UserID == user.ID
+}
+
+// CanDeletePost 作者或管理员可以删除文章
+func CanDeletePost(user *model.User, post *model.Post) bool {
+    if user == nil || user.Banned {
+        return false
+    }
+    return post.UserID == user.ID || IsAdmin(user)
+}
+
+// CanRestorePost 作者或管理员可以从回收站恢复文章
+func CanRestorePost(user *model.User, post *model.Post) bool {
+    return CanDeletePost(user, post)
+}
+
+// CanHidePost 版主和管理员可以隐藏任意文章
+func CanHidePost(user *model.User, post *model.Post) bool {
+    return user != nil && !user.Banned && IsModerator(user)
+}
+
+// CanCreateContent 被封禁或邮箱未验证的用户不能发表文章、评论和上传文件
+func CanCreateContent(user *model.User) bool {
+    return user != nil && !user.Banned && user.Verified
+}
+
+// CanReact 未被封禁的用户可以回应文章和评论、收藏文章，不要求验证邮箱
+func CanReact(user *model.User) bool {
+    return user != nil && !user.Banned
+}
+
+// CanDeleteComment 评论作者、版主和管理员可以删除评论
+func CanDeleteComment(user *model.User, comment *model.Comment) bool {
+    if user == nil || user.Banned {
+        return false
+    }
+    return comment.UserID == user.ID || IsModerator(user)
+}
+
+// CanDeleteAttachment 上传者和管理员可以删除附件
+func CanDeleteAttachment(user *model.User, attachment *model.Attachment) bool {
+    if user == nil || user.Banned {
+        return false
+    }
+    return attachment.UserID == user.ID || IsAdmin(user)
+}
+
+// CanManageUsers 只有管理员可以管理用户角色和封禁状态
+func CanManageUsers(user *model.User) bool {
+    return user != nil && !user.Banned && IsAdmin(user)
+}
+
+// CanManageTags 版主和管理员可以修改和删除标签
+func CanManageTags(user *model.User) bool {
+    return user != nil && !user.Banned && IsModerator(user)
+}
+
+// CanManageCategories 只有管理员可以维护分类
+func CanManageCategories(user *model.User) bool {
+    return user != nil && !user.Banned && IsAdmin(user)
+}
+
+// CanViewRevisions 作者、版主和管理员可以查看文章的修订记录
+func CanViewRevisions(user *model.User, post *model.Post) bool {
+    return user != nil && (user.ID == post.UserID || IsModerator(user))
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "time"
+)
+
+// APITokenRepository 个人访问令牌仓储接口
+type APITokenRepository interface {
+    Create(token *model.APIToken) error
+    // GetByHash 按令牌哈希查找，不存在时返回错误
+    GetByHash(tokenHash string) (*model.APIToken, error)
+    ListByUser(userID uint) ([]*model.APIToken, error)
+    // Touch 更新最近使用时间
+    Touch(id uint, at time.Time) error
+    // Delete 删除用户的令牌，令牌不存在或不属于该用户时返回错误
+    Delete(userID, id uint) error
+    // DeleteByUser 删除用户的全部令牌
+    DeleteByUser(userID uint) error
+    // DeleteExpired 删除before之前过期的令牌，返回删除数量
+    DeleteExpired(before time.Time) (int64, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "time"
+)
+
+// AttachmentRepository 附件仓储接口
+type AttachmentRepository interface {
+    Create(attachment *model.Attachment) error
+    GetByID(id uint) (*model.Attachment, error)
+    // GetByUserID 获取用户上传的附件（分页，按上传时间倒序）
+    GetByUserID(userID uint, page, limit int) ([]*model.Attachment, int64, error)
+    Delete(id uint) error
+    // GetOrphaned 获取before之前被删除的用户留下的、未关联文章的附件
+    GetOrphaned(before time.Time, limit int) ([]*model.Attachment, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "time"
 )
 
 // CommentRepository 评论仓储接口
@@ -9,5 +10,25 @@ type CommentRepository interface {
     Create(comment *model.Comment) error
     GetByID(id uint) (*model.Comment, error)
     GetByPostID(postID uint, page, limit int) ([]*model.Comment, int64, error)
+    // GetByPostIDAfter 按游标获取指定文章的评论，after为nil时从最新的评论开始，最多返回limit条
+    GetByPostIDAfter(postID uint, after *Cursor, limit int) ([]*model.Comment, error)
+    GetRootsByPostID(postID uint, page, limit int) ([]*model.Comment, int64, error)
+    GetByRootIDs(rootIDs []uint) ([]*model.Comment, error)
+    CountReplies(id uint) (int64, error)
+    Update(comment *model.Comment) error
     Delete(id uint) error
-}
\ No newline at end of file
+    // GetByIDWithDeleted 根据ID获取评论，包括回收站中的评论
+    GetByIDWithDeleted(id uint) (*model.Comment, error)
+    // GetDeletedByUserID 获取用户已删除的评论（回收站中的评论和占位评论，分页）
+    GetDeletedByUserID(userID uint, page, limit int) ([]*model.Comment, int64, error)
+    // Restore 从回收站恢复评论
+    Restore(id uint) error
+    // Purge 永久删除before之前进入回收站的评论，返回删除数量
+    Purge(before time.Time) (int64, error)
+    // GetByUserID 获取用户的所有评论，包括占位评论和回收站中的评论（分页）
+    GetByUserID(userID uint, page, limit int) ([]*model.Comment, int64, error)
+    // ReassignUser 将用户的所有评论（包括回收站中的）转给另一个用户
+    ReassignUser(fromID, toID uint) error
+    // DeleteByUserID 删除用户的所有评论：仍有回复的评论保留为占位并转给placeholderID，其余移入回收站；返回受影响的评论ID
+    DeleteByUserID(userID, placeholderID uint) ([]uint, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+    "io"
+)
+
+// FileStorage 文件存储接口，key为以/分隔的相对路径
+type FileStorage interface {
+    // Put 保存文件，同名文件会被覆盖
+    Put(key string, r io.Reader, size int64, contentType string) error
+    // Open 读取文件
+    Open(key string) (io.ReadCloser, error)
+    // Delete 删除文件，文件不存在时不返回错误
+    Delete(key string) error
+    // URL 返回文件的访问地址
+    URL(key string) string
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "time"
+)
+
+// UserIdentityRepository 外部身份仓储接口
+type UserIdentityRepository interface {
+    Create(identity *model.UserIdentity) error
+    // GetBySubject 按issuer和subject查找外部身份，不存在时返回nil, nil
+    GetBySubject(issuer, subject string) (*model.UserIdentity, error)
+    ListByUser(userID uint) ([]*model.UserIdentity, error)
+    // Touch 更新最近一次使用该身份登录的时间和邮箱
+    Touch(id uint, email string) error
+    Delete(id uint) error
+}
+
+// OIDCStateRepository OpenID Connect授权请求状态仓储接口
+type OIDCStateRepository interface {
+    Create(state *model.OIDCState) error
+    // Consume 原子地删除未过期的状态并返回，状态不存在或已过期时返回错误，保证每个授权请求只能回调一次
+    Consume(stateHash string, now time.Time) (*model.OIDCState, error)
+    // DeleteExpired 删除before之前过期的状态，返回删除数量
+    DeleteExpired(before time.Time) (int64, error)
+}
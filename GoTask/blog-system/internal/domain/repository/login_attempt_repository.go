@@ -0,0 +1,33 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "time"
+)
+
+// LoginAttemptRepository 登录尝试审计记录仓储接口
+type LoginAttemptRepository interface {
+    Create(attempt *model.LoginAttempt) error
+    // List 查询登录记录（分页，按时间倒序），username和ip为空时不作为条件
+    List(username, ip string, page, limit int) ([]*model.LoginAttempt, int64, error)
+    // DeleteBefore 删除before之前的记录，返回删除数量
+    DeleteBefore(before time.Time) (int64, error)
+}
+
+// LoginLockoutRepository 登录失败计数和锁定状态仓储接口
+type LoginLockoutRepository interface {
+    // Get 获取计数记录，不存在时返回nil
+    Get(scope, subject string) (*model.LoginLockout, error)
+    GetByID(id uint) (*model.LoginLockout, error)
+    // AddFailure 原子地将失败次数加一，上次失败早于resetBefore时从1重新计数，返回更新后的记录
+    AddFailure(scope, subject string, now, resetBefore time.Time) (*model.LoginLockout, error)
+    // Lock 设置锁定截止时间
+    Lock(id uint, until time.Time) error
+    // Reset 清除计数（登录成功时）
+    Reset(scope, subject string) error
+    // List 获取计数记录（分页，按最近失败时间倒序），lockedOnly为true时只返回在now时仍处于锁定的记录
+    List(lockedOnly bool, now time.Time, page, limit int) ([]*model.LoginLockout, int64, error)
+    Delete(id uint) error
+    // DeleteStale 删除最近失败早于before且已解除锁定的记录，返回删除数量
+    DeleteStale(before, now time.Time) (int64, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+    "time"
+)
+
+// Cursor 键集分页的位置，列表按 (created_at, id) 倒序排列，After查询返回排在该位置之后的记录
+// 与OFFSET分页相比，翻页期间有新记录插入时不会出现重复，也不需要统计总数
+type Cursor struct {
+    CreatedAt time.Time
+    ID        uint
+}
+
+// After 记录是否排在游标之后，after为nil表示从第一条开始
+func (c *Cursor) After(createdAt time.Time, id uint) bool {
+    if c == nil {
+        return true
+    }
+    return createdAt.Before(c.CreatedAt) || (createdAt.Equal(c.CreatedAt) && id < c.ID)
+}
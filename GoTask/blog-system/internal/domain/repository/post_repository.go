@@ -2,14 +2,77 @@ package repository
 
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "time"
 )
 
+// 文章列表可排序的字段
+const (
+    PostSortCreatedAt = "created_at"
+    PostSortUpdatedAt = "updated_at"
+    PostSortPublishAt = "publish_at"
+    PostSortTitle     = "title"
+    PostSortReactions = "reaction_count"
+    PostSortBookmarks = "bookmark_count"
+)
+
+// PostSortFields 允许排序的字段，仓储只会把这些字段写进ORDER BY
+var PostSortFields = []string{PostSortCreatedAt, PostSortUpdatedAt, PostSortPublishAt, PostSortTitle, PostSortReactions, PostSortBookmarks}
+
+// IsValidPostSortField 是否为允许排序的字段
+func IsValidPostSortField(field string) bool {
+    for _, f := range PostSortFields {
+        if f == field {
+            return true
+        }
+    }
+    return false
+}
+
+// PostSort 排序条件
+type PostSort struct {
+    Field string // PostSortFields 之一
+    Desc  bool
+}
+
+// PostFilter 文章列表筛选条件，空值表示不筛选
+type PostFilter struct {
+    Tag           string     // 标签名
+    Category      string     // 分类名
+    Author        string     // 作者用户名
+    CreatedAfter  *time.Time // 创建时间不早于
+    CreatedBefore *time.Time // 创建时间早于
+    TitleContains string     // 标题包含的文字，不区分大小写
+    Sort          []PostSort // 依次按这些字段排序，最后按ID倒序；为空时按创建时间倒序，游标分页忽略此项
+}
+
 // PostRepository 文章仓储接口
 type PostRepository interface {
     Create(post *model.Post) error
     GetByID(id uint) (*model.Post, error)
-    GetAll(page, limit int) ([]*model.Post, int64, error)
+    GetAll(filter PostFilter, page, limit int) ([]*model.Post, int64, error)
     GetByUserID(userID uint, page, limit int) ([]*model.Post, int64, error)
+    // GetAllAfter 按游标获取公开的文章，after为nil时从最新的文章开始，最多返回limit条
+    GetAllAfter(filter PostFilter, after *Cursor, limit int) ([]*model.Post, error)
+    // GetByUserIDAfter 按游标获取指定用户的公开文章
+    GetByUserIDAfter(userID uint, after *Cursor, limit int) ([]*model.Post, error)
+    GetByAuthor(userID uint, status string, page, limit int) ([]*model.Post, int64, error)
+    GetDueScheduled(now time.Time, limit int) ([]*model.Post, error)
+    // GetWithoutContentHTML 获取尚未生成content_html的文章，包括回收站中的
+    GetWithoutContentHTML(limit int) ([]*model.Post, error)
+    // SetContentHTML 只更新文章的content_html，不修改更新时间
+    SetContentHTML(id uint, html string) error
     Update(post *model.Post) error
     Delete(id uint) error
+    // GetDeletedByID 获取回收站中的文章
+    GetDeletedByID(id uint) (*model.Post, error)
+    // GetDeletedByAuthor 获取作者回收站中的文章（分页）
+    GetDeletedByAuthor(userID uint, page, limit int) ([]*model.Post, int64, error)
+    // Restore 从回收站恢复文章
+    Restore(id uint) error
+    // Purge 永久删除before之前进入回收站的文章及其评论、修订记录和关联，附件保留给上传者，返回删除数量
+    Purge(before time.Time) (int64, error)
+    // ReassignUser 将用户的所有文章（包括回收站中的）、修订记录和文章中的附件转给另一个用户
+    ReassignUser(fromID, toID uint) error
+    // DeleteByUserID 将用户的所有文章移入回收站，返回被删除的文章ID
+    DeleteByUserID(userID uint) ([]uint, error)
 }
\ No newline at end of file
@@ -0,0 +1,34 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+)
+
+// ReactionRepository 表情回应仓储接口，添加和删除回应时在同一事务中维护文章或评论的reaction_count
+type ReactionRepository interface {
+    // Add 添加回应，已存在时不做修改，返回是否新增
+    Add(reaction *model.Reaction) (bool, error)
+    // Remove 删除回应，不存在时不做修改，返回是否删除
+    Remove(userID uint, targetType string, targetID uint, emoji string) (bool, error)
+    // CountByTarget 按表情统计目标的回应数
+    CountByTarget(targetType string, targetID uint) (map[string]int64, error)
+    // ListByUser 用户对目标的全部回应表情
+    ListByUser(userID uint, targetType string, targetID uint) ([]string, error)
+    // DeleteByUser 删除用户的全部回应并更新计数
+    DeleteByUser(userID uint) error
+}
+
+// BookmarkRepository 收藏仓储接口，添加和删除收藏时在同一事务中维护文章的bookmark_count
+type BookmarkRepository interface {
+    // Add 添加收藏，已存在时不做修改，返回是否新增
+    Add(bookmark *model.Bookmark) (bool, error)
+    // Remove 删除收藏，不存在时不做修改，返回是否删除
+    Remove(userID, postID uint) (bool, error)
+    Exists(userID, postID uint) (bool, error)
+    // ListPosts 获取用户收藏的公开文章（分页），按收藏时间倒序
+    ListPosts(userID uint, page, limit int) ([]*model.Post, int64, error)
+    // ListByUser 获取用户的全部收藏，包括已不公开的文章
+    ListByUser(userID uint) ([]*model.Bookmark, error)
+    // DeleteByUser 删除用户的全部收藏并更新计数
+    DeleteByUser(userID uint) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+)
+
+// PostRevisionRepository 文章修订记录仓储接口
+type PostRevisionRepository interface {
+    // Create 保存修订记录，Revision为0时自动分配下一个版本号
+    Create(revision *model.PostRevision) error
+    GetByPostID(postID uint, page, limit int) ([]*model.PostRevision, int64, error)
+    GetByRevision(postID uint, revision int) (*model.PostRevision, error)
+}
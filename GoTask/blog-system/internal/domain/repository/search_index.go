@@ -0,0 +1,17 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+)
+
+// SearchIndex 全文搜索索引接口
+type SearchIndex interface {
+    // Index 新增或更新文档
+    Index(doc *model.SearchDocument) error
+    // Remove 删除单个文档
+    Remove(docType string, id uint) error
+    // RemovePost 删除文章及其所有评论的文档
+    RemovePost(postID uint) error
+    // Search 按相关度排序搜索，docType为空时同时搜索文章和评论
+    Search(query, docType string, page, limit int) ([]*model.SearchHit, int64, error)
+}
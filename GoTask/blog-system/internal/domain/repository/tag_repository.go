@@ -0,0 +1,31 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+)
+
+// TagRepository 标签仓储接口
+type TagRepository interface {
+    Create(tag *model.Tag) error
+    GetByID(id uint) (*model.Tag, error)
+    GetByName(name string) (*model.Tag, error)
+    GetAll() ([]*model.Tag, error)
+    Update(tag *model.Tag) error
+    Delete(id uint) error
+    // SetPostTags 替换文章的全部标签
+    SetPostTags(postID uint, tagIDs []uint) error
+    // Cloud 获取标签云（按公开文章数降序）
+    Cloud(limit int) ([]*model.TagCount, error)
+}
+
+// CategoryRepository 分类仓储接口
+type CategoryRepository interface {
+    Create(category *model.Category) error
+    GetByID(id uint) (*model.Category, error)
+    GetByName(name string) (*model.Category, error)
+    GetAllWithCount() ([]*model.CategoryCount, error)
+    Update(category *model.Category) error
+    Delete(id uint) error
+    // SetPostCategories 替换文章的全部分类
+    SetPostCategories(postID uint, categoryIDs []uint) error
+}
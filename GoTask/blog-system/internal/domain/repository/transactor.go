@@ -0,0 +1,21 @@
+package repository
+
+// TxRepositories 事务内使用的仓储集合，所有操作在同一个事务中提交或回滚
+type TxRepositories struct {
+    Users      UserRepository
+    Posts      PostRepository
+    Comments   CommentRepository
+    Tags       TagRepository
+    Categories CategoryRepository
+    Revisions  PostRevisionRepository
+    Identities UserIdentityRepository
+    APITokens  APITokenRepository
+    Reactions  ReactionRepository
+    Bookmarks  BookmarkRepository
+}
+
+// Transactor 事务执行器，用于需要跨多个仓储保持一致的操作
+type Transactor interface {
+    // WithinTransaction 在事务中执行fn，fn返回错误时回滚
+    WithinTransaction(fn func(repos TxRepositories) error) error
+}
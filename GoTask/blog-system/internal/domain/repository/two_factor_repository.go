@@ -0,0 +1,28 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "time"
+)
+
+// UserTOTPRepository TOTP两步验证设置仓储接口
+type UserTOTPRepository interface {
+    // GetByUser 获取用户的TOTP设置，不存在时返回nil, nil
+    GetByUser(userID uint) (*model.UserTOTP, error)
+    // Save 保存TOTP设置，ID为0时创建
+    Save(totp *model.UserTOTP) error
+    // UseStep 原子地记录已使用的时间步，只有step大于上次记录的值时返回true，防止验证码重放
+    UseStep(userID uint, step int64) (bool, error)
+    DeleteByUser(userID uint) error
+}
+
+// RecoveryCodeRepository 两步验证恢复码仓储接口
+type RecoveryCodeRepository interface {
+    // Replace 删除用户原有的恢复码并保存新的恢复码哈希
+    Replace(userID uint, codeHashes []string) error
+    // Consume 原子地将未使用的恢复码标记为已使用，恢复码不存在或已使用时返回false
+    Consume(userID uint, codeHash string, now time.Time) (bool, error)
+    // CountUnused 统计用户未使用的恢复码数量
+    CountUnused(userID uint) (int64, error)
+    DeleteByUser(userID uint) error
+}
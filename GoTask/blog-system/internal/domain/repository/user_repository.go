@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+	"time"
 )
 
 // UserRepository 用户仓储接口
@@ -10,6 +11,13 @@ type UserRepository interface {
 	GetByID(id uint) (*model.User, error)
 	GetByUsername(username string) (*model.User, error)
 	GetByEmail(email string) (*model.User, error)
+	List(page, limit int) ([]*model.User, int64, error)
 	Update(user *model.User) error
 	Delete(id uint) error
+	// GetDeleted 获取回收站中的用户（分页）
+	GetDeleted(page, limit int) ([]*model.User, int64, error)
+	// Restore 从回收站恢复用户
+	Restore(id uint) error
+	// Purge 永久删除before之前进入回收站、且名下已没有文章、评论和附件的用户，返回删除数量
+	Purge(before time.Time) (int64, error)
 }
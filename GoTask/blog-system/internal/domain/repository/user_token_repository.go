@@ -0,0 +1,17 @@
+package repository
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "time"
+)
+
+// UserTokenRepository 一次性令牌仓储接口
+type UserTokenRepository interface {
+    Create(token *model.UserToken) error
+    // Consume 原子地将未使用且未过期的令牌标记为已使用并返回，令牌不存在、已使用或已过期时返回错误
+    Consume(purpose, tokenHash string, now time.Time) (*model.UserToken, error)
+    // DeleteByUser 删除用户指定用途的全部令牌，使之前发送的令牌失效
+    DeleteByUser(userID uint, purpose string) error
+    // DeleteExpired 删除before之前过期或已使用的令牌，返回删除数量
+    DeleteExpired(before time.Time) (int64, error)
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+    "bufio"
+    "crypto/sha1"
+    "encoding/hex"
+    "os"
+    "strings"
+)
+
+// BreachedPasswords 已泄露的密码列表，只在内存中保存SHA-1摘要
+type BreachedPasswords struct {
+    digests map[[sha1.Size]byte]struct{}
+}
+
+// LoadBreachedPasswords 从本地文件加载泄露密码列表，每行一个密码；
+// 也可以是40位十六进制的SHA-1摘要（兼容 HASH:次数 格式）。空行和#开头的行被忽略
+func LoadBreachedPasswords(path string) (*BreachedPasswords, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    list := &BreachedPasswords{digests: make(map[[sha1.Size]byte]struct{})}
+    scanner := bufio.NewScanner(f)
+    scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+    for scanner.Scan() {
+        line := strings.TrimRight(scanner.Text(), "\r")
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+
+        if digest, ok := parseSHA1Line(line); ok {
+            list.digests[digest] = struct{}{}
+            continue
+        }
+        list.digests[sha1.Sum([]byte(line))] = struct{}{}
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+    return list, nil
+}
+
+// Contains 密码是否在泄露列表中，列表为nil时返回false
+func (b *BreachedPasswords) Contains(password string) bool {
+    if b == nil {
+        return false
+    }
+    _, ok := b.digests[sha1.Sum([]byte(password))]
+    return ok
+}
+
+// Len 列表中的密码数量
+func (b *BreachedPasswords) Len() int {
+    if b == nil {
+        return 0
+    }
+    return len(b.digests)
+}
+
+// parseSHA1Line 解析 SHA-1摘要 或 SHA-1摘要:次数 格式的行
+func parseSHA1Line(line string) ([sha1.Size]byte, bool) {
+    var digest [sha1.Size]byte
+    value, _, _ := strings.Cut(line, ":")
+    if len(value) != hex.EncodedLen(sha1.Size) {
+        return digest, false
+    }
+    if _, err := hex.Decode(digest[:], []byte(value)); err != nil {
+        return digest, false
+    }
+    return digest, true
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "errors"
+    "time"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+// gormRevocationStore 基于数据库的吊销存储，适用于多实例部署
+type gormRevocationStore struct {
+    db *gorm.DB
+}
+
+// NewGormRevocationStore 创建数据库吊销存储
+func NewGormRevocationStore(db *gorm.DB) RevocationStore {
+    return &gormRevocationStore{db: db}
+}
+
+// Revoke 吊销单个令牌，依靠jti的唯一索引保证并发吊销时只有一次成功
+func (s *gormRevocationStore) Revoke(jti string, userID uint, expiresAt time.Time) (bool, error) {
+    // 顺带清理已过期的记录
+    if err := s.db.Where("expires_at < ?", time.Now()).Delete(&model.RevokedToken{}).Error; err != nil {
+        return false, err
+    }
+
+    token := &model.RevokedToken{
+        JTI:       jti,
+        UserID:    userID,
+        ExpiresAt: expiresAt,
+    }
+    result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token)
+    return result.RowsAffected > 0, result.Error
+}
+
+// IsRevoked 检查令牌是否已被吊销
+func (s *gormRevocationStore) IsRevoked(jti string) (bool, error) {
+    var count int64
+    if err := s.db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+        return false, err
+    }
+    return count > 0, nil
+}
+
+// RevokeUser 吊销用户此前签发的所有令牌，代数在数据库中原子地加1
+func (s *gormRevocationStore) RevokeUser(userID uint, at time.Time) error {
+    revocation := &model.UserTokenRevocation{
+        UserID:     userID,
+        Generation: 1,
+        RevokedAt:  at,
+    }
+    return s.db.Clauses(clause.OnConflict{
+        Columns: []clause.Column{{Name: "user_id"}},
+        DoUpdates: clause.Assignments(map[string]interface{}{
+            "generation": gorm.Expr("generation + 1"),
+            "revoked_at": at,
+        }),
+    }).Create(revocation).Error
+}
+
+// UserGeneration 获取用户当前的令牌代数
+func (s *gormRevocationStore) UserGeneration(userID uint) (uint64, error) {
+    var revocation model.UserTokenRevocation
+    if err := s.db.First(&revocation, "user_id = ?", userID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return 0, nil
+        }
+        return 0, err
+    }
+    return revocation.Generation, nil
+}
@@ -3,6 +3,8 @@ package auth
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/config"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "crypto/rand"
+    "encoding/hex"
     "errors"
     "fmt"
     "time"
@@ -10,75 +12,272 @@ import (
     "github.com/golang-jwt/jwt/v4"
 )
 
+// 令牌类型
+const (
+    TokenTypeAccess  = "access"
+    TokenTypeRefresh = "refresh"
+    TokenTypeMFA     = "mfa" // 密码正确但尚未完成两步验证，只能用于提交验证码
+)
+
+// mfaTokenExpire 两步验证令牌有效期
+const mfaTokenExpire = 5 * time.Minute
+
+// ErrTokenRevoked 令牌已被单独吊销（登出或刷新令牌已轮换）
+var ErrTokenRevoked = errors.New("令牌已失效")
+
 // JWTClaims 自定义JWT声明
 type JWTClaims struct {
-    UserID   uint   `json:"user_id"`
-    Username string `json:"username"`
+    UserID    uint   `json:"user_id"`
+    Username  string `json:"username"`
+    Role      string `json:"role"`
+    TokenType string `json:"token_type"`
+    MFA       bool   `json:"mfa,omitempty"` // 签发时已通过两步验证
+    Gen       uint64 `json:"gen,omitempty"` // 签发时用户的令牌代数，吊销用户令牌后旧代数的令牌失效
     jwt.RegisteredClaims
 }
 
+// TokenPair 访问令牌和刷新令牌
+type TokenPair struct {
+    AccessToken  string `json:"access_token"`
+    RefreshToken string `json:"refresh_token"`
+    TokenType    string `json:"token_type"`
+    ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+}
+
 // JWTService JWT服务接口
 type JWTService interface {
-    GenerateToken(user *model.User) (string, error)
+    // GenerateTokenPair 生成令牌对，mfa表示本次登录已通过两步验证
+    GenerateTokenPair(user *model.User, mfa bool) (*TokenPair, error)
+    // GenerateMFAToken 生成提交两步验证码用的短期令牌，返回令牌和有效期（秒）
+    GenerateMFAToken(user *model.User) (string, int64, error)
     ValidateToken(tokenString string) (*JWTClaims, error)
+    ValidateRefreshToken(tokenString string) (*JWTClaims, error)
+    ValidateMFAToken(tokenString string) (*JWTClaims, error)
+    RevokeToken(claims *JWTClaims) error
+    // ConsumeToken 吊销令牌并返回是否为首次吊销，用于只能使用一次的刷新令牌
+    ConsumeToken(claims *JWTClaims) (bool, error)
+    RevokeUserTokens(userID uint) error
+    // JWKS 验证令牌用的公钥集合，HS256时为空
+    JWKS() JWKSet
 }
 
 type jwtService struct {
-    secretKey string
-    expire    int // 过期时间（小时）
+    secretKey     string
+    signingKey    *SigningKey            // 非对称签名时用于签发新令牌的密钥，HS256时为nil
+    verifyKeys    map[string]*SigningKey // 按kid查找验证密钥，包括轮换前的旧密钥
+    jwks          JWKSet
+    accessExpire  time.Duration
+    refreshExpire time.Duration
+    store         RevocationStore
 }
 
-// NewJWTService 创建JWT服务
-func NewJWTService(cfg *config.Config) JWTService {
-    return &jwtService{
-        secretKey: cfg.JWTSecret,
-        expire:    cfg.JWTExpirationHours,
+// NewJWTService 创建JWT服务，非对称签名时使用 JWT_KEY_FILES 中的第一个密钥签发令牌，其余密钥只用于验证
+func NewJWTService(cfg *config.Config, store RevocationStore) (JWTService, error) {
+    s := &jwtService{
+        accessExpire:  time.Minute * time.Duration(cfg.JWTAccessMinutes),
+        refreshExpire: time.Hour * time.Duration(cfg.JWTExpirationHours),
+        store:         store,
+        jwks:          JWKSet{Keys: []JWK{}},
+    }
+    if cfg.JWTAlgorithm == config.JWTHS256 {
+        s.secretKey = cfg.JWTSecret
+        return s, nil
+    }
+
+    keys, err := LoadSigningKeys(cfg.JWTKeyFiles)
+    if err != nil {
+        return nil, fmt.Errorf("加载JWT密钥失败: %w", err)
+    }
+    if len(keys) == 0 {
+        return nil, fmt.Errorf("JWT_ALGORITHM=%s 需要配置 JWT_KEY_FILES", cfg.JWTAlgorithm)
     }
+    if keys[0].Private == nil || keys[0].Method.Alg() != cfg.JWTAlgorithm {
+        return nil, fmt.Errorf("JWT_KEY_FILES 的第一个文件必须是 %s 私钥", cfg.JWTAlgorithm)
+    }
+
+    s.signingKey = keys[0]
+    s.verifyKeys = make(map[string]*SigningKey, len(keys))
+    for _, key := range keys {
+        s.verifyKeys[key.ID] = key
+        s.jwks.Keys = append(s.jwks.Keys, key.JWK())
+    }
+    return s, nil
 }
 
-// GenerateToken 生成JWT令牌
-func (s *jwtService) GenerateToken(user *model.User) (string, error) {
+// GenerateTokenPair 生成访问令牌和刷新令牌
+func (s *jwtService) GenerateTokenPair(user *model.User, mfa bool) (*TokenPair, error) {
+    accessToken, err := s.generateToken(user, TokenTypeAccess, s.accessExpire, mfa)
+    if err != nil {
+        return nil, err
+    }
+
+    refreshToken, err := s.generateToken(user, TokenTypeRefresh, s.refreshExpire, mfa)
+    if err != nil {
+        return nil, err
+    }
+
+    return &TokenPair{
+        AccessToken:  accessToken,
+        RefreshToken: refreshToken,
+        TokenType:    "Bearer",
+        ExpiresIn:    int64(s.accessExpire.Seconds()),
+    }, nil
+}
+
+// GenerateMFAToken 生成两步验证令牌
+func (s *jwtService) GenerateMFAToken(user *model.User) (string, int64, error) {
+    token, err := s.generateToken(user, TokenTypeMFA, mfaTokenExpire, false)
+    if err != nil {
+        return "", 0, err
+    }
+    return token, int64(mfaTokenExpire.Seconds()), nil
+}
+
+// generateToken 生成指定类型的JWT令牌
+func (s *jwtService) generateToken(user *model.User, tokenType string, expire time.Duration, mfa bool) (string, error) {
+    jti, err := newJTI()
+    if err != nil {
+        return "", err
+    }
+    // 先读取代数再签发，与吊销并发时令牌只可能按旧代数签发而失效，不会逃过吊销
+    gen, err := s.store.UserGeneration(user.ID)
+    if err != nil {
+        return "", err
+    }
+
     // 设置JWT声明
+    now := time.Now()
     claims := JWTClaims{
-        UserID:   user.ID,
-        Username: user.Username,
+        UserID:    user.ID,
+        Username:  user.Username,
+        Role:      user.Role,
+        TokenType: tokenType,
+        MFA:       mfa,
+        Gen:       gen,
         RegisteredClaims: jwt.RegisteredClaims{
-            ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(s.expire))),
-            IssuedAt:  jwt.NewNumericDate(time.Now()),
+            ID:        jti,
+            ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+            IssuedAt:  jwt.NewNumericDate(now),
         },
     }
 
-    // 创建令牌
+    // 创建并签名令牌
+    if s.signingKey != nil {
+        token := jwt.NewWithClaims(s.signingKey.Method, claims)
+        token.Header["kid"] = s.signingKey.ID
+        return token.SignedString(s.signingKey.Private)
+    }
+
     token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+    return token.SignedString([]byte(s.secretKey))
+}
+
+// ValidateToken 验证访问令牌
+func (s *jwtService) ValidateToken(tokenString string) (*JWTClaims, error) {
+    return s.validate(tokenString, TokenTypeAccess)
+}
+
+// ValidateRefreshToken 验证刷新令牌
+func (s *jwtService) ValidateRefreshToken(tokenString string) (*JWTClaims, error) {
+    return s.validate(tokenString, TokenTypeRefresh)
+}
+
+// ValidateMFAToken 验证两步验证令牌
+func (s *jwtService) ValidateMFAToken(tokenString string) (*JWTClaims, error) {
+    return s.validate(tokenString, TokenTypeMFA)
+}
+
+// validate 解析令牌并检查类型和吊销状态
+func (s *jwtService) validate(tokenString, tokenType string) (*JWTClaims, error) {
+    // 解析令牌
+    token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, s.keyFunc)
 
-    // 签名令牌
-    tokenString, err := token.SignedString([]byte(s.secretKey))
     if err != nil {
-        return "", err
+        return nil, err
+    }
+
+    // 验证令牌有效性
+    claims, ok := token.Claims.(*JWTClaims)
+    if !ok || !token.Valid {
+        return nil, errors.New("无效的令牌")
     }
 
-    return tokenString, nil
+    if claims.TokenType != tokenType || claims.ID == "" {
+        return nil, errors.New("令牌类型错误")
+    }
+
+    // 检查吊销状态
+    revoked, err := s.store.IsRevoked(claims.ID)
+    if err != nil {
+        return nil, err
+    }
+    if revoked {
+        // 同时返回已验证签名的claims，调用方据此识别被重复使用的刷新令牌
+        return claims, ErrTokenRevoked
+    }
+
+    // 按代数而不是iat判断，iat只精确到秒，无法区分同一秒内吊销前后签发的令牌
+    gen, err := s.store.UserGeneration(claims.UserID)
+    if err != nil {
+        return nil, err
+    }
+    if claims.Gen < gen {
+        return nil, errors.New("令牌已失效")
+    }
+
+    return claims, nil
 }
 
-// ValidateToken 验证JWT令牌
-func (s *jwtService) ValidateToken(tokenString string) (*JWTClaims, error) {
-    // 解析令牌
-    token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-        // 验证签名方法
+// keyFunc 按签名算法和kid选择验证密钥，算法必须与密钥类型一致
+func (s *jwtService) keyFunc(token *jwt.Token) (interface{}, error) {
+    if s.signingKey == nil {
         if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
             return nil, fmt.Errorf("非法的签名方法: %v", token.Header["alg"])
         }
         return []byte(s.secretKey), nil
-    })
+    }
 
-    if err != nil {
-        return nil, err
+    kid, _ := token.Header["kid"].(string)
+    key, ok := s.verifyKeys[kid]
+    if !ok {
+        return nil, errors.New("未知的签名密钥")
+    }
+    if token.Method.Alg() != key.Method.Alg() {
+        return nil, fmt.Errorf("非法的签名方法: %v", token.Header["alg"])
     }
+    return key.Public, nil
+}
 
-    // 验证令牌有效性
-    if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-        return claims, nil
+// JWKS 验证令牌用的公钥集合
+func (s *jwtService) JWKS() JWKSet {
+    return s.jwks
+}
+
+// RevokeToken 吊销单个令牌
+func (s *jwtService) RevokeToken(claims *JWTClaims) error {
+    _, err := s.ConsumeToken(claims)
+    return err
+}
+
+// ConsumeToken 吊销令牌并返回是否为首次吊销
+func (s *jwtService) ConsumeToken(claims *JWTClaims) (bool, error) {
+    expiresAt := time.Now().Add(s.refreshExpire)
+    if claims.ExpiresAt != nil {
+        expiresAt = claims.ExpiresAt.Time
     }
+    return s.store.Revoke(claims.ID, claims.UserID, expiresAt)
+}
 
-    return nil, errors.New("无效的令牌")
-}
\ No newline at end of file
+// RevokeUserTokens 吊销用户已签发的所有令牌
+func (s *jwtService) RevokeUserTokens(userID uint) error {
+    return s.store.RevokeUser(userID, time.Now())
+}
+
+// newJTI 生成随机的令牌ID
+func newJTI() (string, error) {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+    "crypto/rand"
+    "crypto/subtle"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "strings"
+
+    "golang.org/x/crypto/argon2"
+    "golang.org/x/crypto/bcrypt"
+)
+
+// 密码哈希算法
+const (
+    HashArgon2id = "argon2id"
+    HashBcrypt   = "bcrypt"
+)
+
+// Argon2id生成的盐和哈希长度（字节）
+const (
+    argon2SaltLength = 16
+    argon2KeyLength  = 32
+)
+
+// Argon2Params Argon2id参数
+type Argon2Params struct {
+    Memory      uint32 // 内存（KiB）
+    Iterations  uint32 // 迭代次数
+    Parallelism uint8  // 并行度
+}
+
+// PasswordHasher 密码哈希接口，新密码使用配置的算法，校验时兼容所有支持的算法
+type PasswordHasher interface {
+    // Hash 使用当前配置的算法和参数生成哈希
+    Hash(password string) (string, error)
+    // Verify 校验密码与哈希是否匹配，哈希格式无法识别时返回false
+    Verify(password, hash string) bool
+    // NeedsRehash 哈希的算法或参数与当前配置不同时返回true，应在登录成功后重新哈希
+    NeedsRehash(hash string) bool
+}
+
+type passwordHasher struct {
+    algorithm  string
+    argon2     Argon2Params
+    bcryptCost int
+}
+
+// NewPasswordHasher 创建密码哈希服务，algorithm为argon2id或bcrypt
+func NewPasswordHasher(algorithm string, argon2Params Argon2Params, bcryptCost int) (PasswordHasher, error) {
+    switch algorithm {
+    case HashArgon2id:
+        if argon2Params.Memory == 0 || argon2Params.Iterations == 0 || argon2Params.Parallelism == 0 {
+            return nil, errors.New("argon2id 参数必须大于0")
+        }
+    case HashBcrypt:
+        if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+            return nil, fmt.Errorf("bcrypt cost 必须在 %d 到 %d 之间", bcrypt.MinCost, bcrypt.MaxCost)
+        }
+    default:
+        return nil, fmt.Errorf("不支持的密码哈希算法: %s", algorithm)
+    }
+
+    return &passwordHasher{algorithm: algorithm, argon2: argon2Params, bcryptCost: bcryptCost}, nil
+}
+
+// Hash 生成密码哈希，argon2id使用PHC字符串格式
+func (h *passwordHasher) Hash(password string) (string, error) {
+    if h.algorithm == HashBcrypt {
+        hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+        if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+            return "", errors.New("密码过长")
+        }
+        return string(hashed), err
+    }
+
+    salt := make([]byte, argon2SaltLength)
+    if _, err := rand.Read(salt); err != nil {
+        return "", err
+    }
+    p := h.argon2
+    key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, argon2KeyLength)
+    return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+        base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify 按哈希前缀识别算法并校验密码
+func (h *passwordHasher) Verify(password, hash string) bool {
+    if isBcryptHash(hash) {
+        return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+    }
+
+    params, salt, key, err := parseArgon2Hash(hash)
+    if err != nil {
+        return false
+    }
+    derived := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+    return subtle.ConstantTimeCompare(derived, key) == 1
+}
+
+// NeedsRehash 检查哈希的算法和参数是否与当前配置一致
+func (h *passwordHasher) NeedsRehash(hash string) bool {
+    if h.algorithm == HashBcrypt {
+        if !isBcryptHash(hash) {
+            return true
+        }
+        cost, err := bcrypt.Cost([]byte(hash))
+        return err != nil || cost != h.bcryptCost
+    }
+
+    params, _, key, err := parseArgon2Hash(hash)
+    return err != nil || params != h.argon2 || len(key) != argon2KeyLength
+}
+
+// isBcryptHash 是否为bcrypt哈希
+func isBcryptHash(hash string) bool {
+    return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// parseArgon2Hash 解析 $argon2id$v=19$m=65536,t=3,p=2$<盐>$<哈希> 格式的哈希
+func parseArgon2Hash(hash string) (Argon2Params, []byte, []byte, error) {
+    var params Argon2Params
+    parts := strings.Split(hash, "$")
+    if len(parts) != 6 || parts[0] != "" || parts[1] != HashArgon2id {
+        return params, nil, nil, errors.New("无法识别的密码哈希格式")
+    }
+
+    var version int
+    if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+        return params, nil, nil, errors.New("不支持的argon2版本")
+    }
+    if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+        return params, nil, nil, errors.New("无效的argon2参数")
+    }
+    if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+        return params, nil, nil, errors.New("无效的argon2参数")
+    }
+
+    salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+    if err != nil {
+        return params, nil, nil, err
+    }
+    key, err := base64.RawStdEncoding.DecodeString(parts[5])
+    if err != nil || len(key) == 0 {
+        return params, nil, nil, errors.New("无效的argon2哈希")
+    }
+    return params, salt, key, nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+    "sync"
+    "time"
+)
+
+// RevocationStore 令牌吊销存储接口
+type RevocationStore interface {
+    // Revoke 吊销单个令牌，expiresAt之后记录可被清理；令牌此前已被吊销时返回false
+    // 检查和写入是原子的，同一个令牌并发吊销时只有一次返回true
+    Revoke(jti string, userID uint, expiresAt time.Time) (bool, error)
+    // IsRevoked 检查令牌是否已被吊销
+    IsRevoked(jti string) (bool, error)
+    // RevokeUser 吊销用户此前签发的所有令牌（登出所有设备、修改密码等），将用户的令牌代数加1
+    RevokeUser(userID uint, at time.Time) error
+    // UserGeneration 获取用户当前的令牌代数，未吊销过时为0
+    UserGeneration(userID uint) (uint64, error)
+}
+
+// memoryRevocationStore 内存吊销存储，适用于单实例部署和测试
+type memoryRevocationStore struct {
+    mu     sync.RWMutex
+    tokens map[string]time.Time
+    users  map[uint]uint64
+}
+
+// NewMemoryRevocationStore 创建内存吊销存储
+func NewMemoryRevocationStore() RevocationStore {
+    return &memoryRevocationStore{
+        tokens: make(map[string]time.Time),
+        users:  make(map[uint]uint64),
+    }
+}
+
+// Revoke 吊销单个令牌
+func (s *memoryRevocationStore) Revoke(jti string, userID uint, expiresAt time.Time) (bool, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    // 顺带清理已过期的记录，避免无限增长
+    now := time.Now()
+    for id, exp := range s.tokens {
+        if exp.Before(now) {
+            delete(s.tokens, id)
+        }
+    }
+
+    if _, ok := s.tokens[jti]; ok {
+        return false, nil
+    }
+    s.tokens[jti] = expiresAt
+    return true, nil
+}
+
+// IsRevoked 检查令牌是否已被吊销
+func (s *memoryRevocationStore) IsRevoked(jti string) (bool, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    _, ok := s.tokens[jti]
+    return ok, nil
+}
+
+// RevokeUser 吊销用户此前签发的所有令牌
+func (s *memoryRevocationStore) RevokeUser(userID uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    s.users[userID]++
+    return nil
+}
+
+// UserGeneration 获取用户当前的令牌代数
+func (s *memoryRevocationStore) UserGeneration(userID uint) (uint64, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    return s.users[userID], nil
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+    "crypto"
+    "crypto/ed25519"
+    "crypto/rsa"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/base64"
+    "encoding/pem"
+    "errors"
+    "fmt"
+    "math/big"
+    "os"
+
+    "github.com/golang-jwt/jwt/v4"
+)
+
+// minRSAKeyBits RSA签名密钥的最小长度
+const minRSAKeyBits = 2048
+
+// SigningKey 非对称签名密钥，只有公钥时只能用于验证已签发的令牌
+type SigningKey struct {
+    ID      string            // kid，公钥的 RFC 7638 指纹
+    Method  jwt.SigningMethod // RS256 或 EdDSA，由密钥类型决定
+    Private crypto.Signer     // 只有公钥时为nil
+    Public  crypto.PublicKey
+}
+
+// JWK JSON Web Key 格式的公钥
+type JWK struct {
+    Kty string `json:"kty"`
+    Use string `json:"use"`
+    Alg string `json:"alg"`
+    Kid string `json:"kid"`
+    N   string `json:"n,omitempty"`   // RSA模数
+    E   string `json:"e,omitempty"`   // RSA指数
+    Crv string `json:"crv,omitempty"` // OKP曲线
+    X   string `json:"x,omitempty"`   // OKP公钥
+}
+
+// JWKSet 公钥集合，即 /.well-known/jwks.json 的响应
+type JWKSet struct {
+    Keys []JWK `json:"keys"`
+}
+
+// LoadSigningKeys 从PEM文件加载签名密钥，文件可以是私钥（PKCS#8 或 PKCS#1）或公钥（PKIX 或 PKCS#1）
+func LoadSigningKeys(paths []string) ([]*SigningKey, error) {
+    keys := make([]*SigningKey, 0, len(paths))
+    seen := make(map[string]string, len(paths))
+    for _, path := range paths {
+        data, err := os.ReadFile(path)
+        if err != nil {
+            return nil, err
+        }
+        key, err := ParseSigningKey(data)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", path, err)
+        }
+        if other, ok := seen[key.ID]; ok {
+            return nil, fmt.Errorf("%s 与 %s 是同一个密钥", path, other)
+        }
+        seen[key.ID] = path
+        keys = append(keys, key)
+    }
+    return keys, nil
+}
+
+// ParseSigningKey 解析PEM格式的RSA或Ed25519密钥
+func ParseSigningKey(data []byte) (*SigningKey, error) {
+    block, _ := pem.Decode(data)
+    if block == nil {
+        return nil, errors.New("不是PEM格式的密钥")
+    }
+
+    var parsed interface{}
+    var err error
+    switch block.Type {
+    case "PRIVATE KEY":
+        parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+    case "RSA PRIVATE KEY":
+        parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+    case "PUBLIC KEY":
+        parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+    case "RSA PUBLIC KEY":
+        parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+    default:
+        return nil, fmt.Errorf("不支持的PEM类型: %s", block.Type)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    key := &SigningKey{}
+    if signer, ok := parsed.(crypto.Signer); ok {
+        key.Private = signer
+        parsed = signer.Public()
+    }
+    switch pub := parsed.(type) {
+    case *rsa.PublicKey:
+        if pub.N.BitLen() < minRSAKeyBits {
+            return nil, fmt.Errorf("RSA密钥长度不能少于%d位", minRSAKeyBits)
+        }
+        key.Method = jwt.SigningMethodRS256
+    case ed25519.PublicKey:
+        key.Method = jwt.SigningMethodEdDSA
+    default:
+        return nil, fmt.Errorf("不支持的密钥类型 %T，只支持RSA和Ed25519", pub)
+    }
+    key.Public = parsed
+    key.ID = thumbprint(key.Public)
+    return key, nil
+}
+
+// JWK 公钥的JWK表示
+func (k *SigningKey) JWK() JWK {
+    jwk := JWK{Use: "sig", Alg: k.Method.Alg(), Kid: k.ID}
+    switch pub := k.Public.(type) {
+    case *rsa.PublicKey:
+        jwk.Kty = "RSA"
+        jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+        jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+    case ed25519.PublicKey:
+        jwk.Kty = "OKP"
+        jwk.Crv = "Ed25519"
+        jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+    }
+    return jwk
+}
+
+// thumbprint 计算公钥的 RFC 7638 指纹：按字典序排列必需成员的JSON的SHA-256
+func thumbprint(pub crypto.PublicKey) string {
+    var canonical string
+    switch pub := pub.(type) {
+    case *rsa.PublicKey:
+        canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+            base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+            base64.RawURLEncoding.EncodeToString(pub.N.Bytes()))
+    case ed25519.PublicKey:
+        canonical = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, base64.RawURLEncoding.EncodeToString(pub))
+    }
+    sum := sha256.Sum256([]byte(canonical))
+    return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,40 @@
+package mail
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "os"
+    "path/filepath"
+    "time"
+)
+
+// fileMailer 将邮件保存为目录中的.eml文件，适用于本地开发
+type fileMailer struct {
+    dir  string
+    from string
+}
+
+// NewFileMailer 创建写入目录的邮件发送器，目录不存在时自动创建
+func NewFileMailer(dir, from string) (Mailer, error) {
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        return nil, err
+    }
+    return &fileMailer{dir: dir, from: from}, nil
+}
+
+// Send 将邮件写入文件，文件名以发送时间开头便于按时间排序
+func (m *fileMailer) Send(msg Message) error {
+    now := time.Now()
+    _, data, err := buildMessage(m.from, msg, now)
+    if err != nil {
+        return err
+    }
+
+    suffix := make([]byte, 4)
+    if _, err := rand.Read(suffix); err != nil {
+        return err
+    }
+    name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000"), hex.EncodeToString(suffix))
+    return os.WriteFile(filepath.Join(m.dir, name), data, 0o600)
+}
@@ -0,0 +1,69 @@
+package mail
+
+import (
+    "bytes"
+    "crypto/rand"
+    "encoding/base64"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "mime"
+    netmail "net/mail"
+    "strings"
+    "time"
+)
+
+// Message 待发送的邮件，正文为纯文本
+type Message struct {
+    To      string
+    Subject string
+    Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+    Send(msg Message) error
+}
+
+// buildMessage 生成RFC 5322格式的邮件内容，返回收件人地址和邮件数据
+// 标题按RFC 2047编码，正文使用base64编码的UTF-8文本
+func buildMessage(from string, msg Message, now time.Time) (string, []byte, error) {
+    fromAddr, err := netmail.ParseAddress(from)
+    if err != nil {
+        return "", nil, fmt.Errorf("无效的发件人地址: %s", from)
+    }
+    toAddr, err := netmail.ParseAddress(msg.To)
+    if err != nil {
+        return "", nil, fmt.Errorf("无效的收件人地址: %s", msg.To)
+    }
+    if strings.ContainsAny(msg.Subject, "\r\n") {
+        return "", nil, errors.New("邮件标题不能包含换行")
+    }
+
+    id := make([]byte, 16)
+    if _, err := rand.Read(id); err != nil {
+        return "", nil, err
+    }
+    domain := fromAddr.Address[strings.LastIndex(fromAddr.Address, "@")+1:]
+
+    var buf bytes.Buffer
+    fmt.Fprintf(&buf, "From: %s\r\n", fromAddr.String())
+    fmt.Fprintf(&buf, "To: %s\r\n", toAddr.String())
+    fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+    fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+    fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+    buf.WriteString("MIME-Version: 1.0\r\n")
+    buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+    buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+    buf.WriteString("\r\n")
+
+    // base64正文每行不超过76个字符
+    body := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+    for len(body) > 76 {
+        buf.WriteString(body[:76] + "\r\n")
+        body = body[76:]
+    }
+    buf.WriteString(body + "\r\n")
+
+    return toAddr.Address, buf.Bytes(), nil
+}
@@ -0,0 +1,46 @@
+package mail
+
+import (
+    "sync"
+)
+
+// MemoryMailer 将邮件保存在内存中，适用于测试
+type MemoryMailer struct {
+    mu       sync.Mutex
+    messages []Message
+}
+
+// NewMemoryMailer 创建内存邮件发送器
+func NewMemoryMailer() *MemoryMailer {
+    return &MemoryMailer{}
+}
+
+// Send 保存邮件
+func (m *MemoryMailer) Send(msg Message) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.messages = append(m.messages, msg)
+    return nil
+}
+
+// Messages 返回已发送邮件的副本
+func (m *MemoryMailer) Messages() []Message {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return append([]Message(nil), m.messages...)
+}
+
+// Last 返回最后一封发给to的邮件，没有时第二个返回值为false
+func (m *MemoryMailer) Last(to string) (Message, bool) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for i := len(m.messages) - 1; i >= 0; i-- {
+        if m.messages[i].To == to {
+            return m.messages[i], true
+        }
+    }
+    return Message{}, false
+}
@@ -0,0 +1,115 @@
+package mail
+
+import (
+    "crypto/tls"
+    "errors"
+    "net"
+    netmail "net/mail"
+    "net/smtp"
+    "strconv"
+    "time"
+)
+
+// SMTPConfig SMTP服务器配置
+type SMTPConfig struct {
+    Host     string
+    Port     int    // 465使用隐式TLS，其他端口在服务器支持时使用STARTTLS
+    Username string // 为空时不进行认证
+    Password string
+    From     string // 发件人，如 "Blog <noreply@example.com>"
+}
+
+// smtpTimeout 单封邮件的发送时限，避免SMTP服务器无响应时阻塞请求
+const smtpTimeout = 30 * time.Second
+
+// smtpMailer 通过SMTP服务器发送邮件
+type smtpMailer struct {
+    cfg      SMTPConfig
+    envelope string // 信封发件人地址（MAIL FROM）
+}
+
+// NewSMTPMailer 创建SMTP邮件发送器
+func NewSMTPMailer(cfg SMTPConfig) (Mailer, error) {
+    if cfg.Host == "" || cfg.Port <= 0 {
+        return nil, errors.New("SMTP需要配置服务器地址和端口")
+    }
+    from, err := netmail.ParseAddress(cfg.From)
+    if err != nil {
+        return nil, errors.New("无效的发件人地址: " + cfg.From)
+    }
+    return &smtpMailer{cfg: cfg, envelope: from.Address}, nil
+}
+
+// Send 发送邮件
+func (m *smtpMailer) Send(msg Message) error {
+    to, data, err := buildMessage(m.cfg.From, msg, time.Now())
+    if err != nil {
+        return err
+    }
+
+    client, err := m.dial()
+    if err != nil {
+        return err
+    }
+    defer client.Close()
+
+    if m.cfg.Username != "" {
+        // PlainAuth只允许在TLS连接或本机上发送密码
+        if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+            return err
+        }
+    }
+    if err := client.Mail(m.envelope); err != nil {
+        return err
+    }
+    if err := client.Rcpt(to); err != nil {
+        return err
+    }
+    w, err := client.Data()
+    if err != nil {
+        return err
+    }
+    if _, err := w.Write(data); err != nil {
+        w.Close()
+        return err
+    }
+    if err := w.Close(); err != nil {
+        return err
+    }
+    return client.Quit()
+}
+
+// dial 连接SMTP服务器，465端口使用隐式TLS，其他端口在服务器支持时升级为STARTTLS
+func (m *smtpMailer) dial() (*smtp.Client, error) {
+    addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+    dialer := &net.Dialer{Timeout: 10 * time.Second}
+    tlsConfig := &tls.Config{ServerName: m.cfg.Host}
+
+    var conn net.Conn
+    var err error
+    if m.cfg.Port == 465 {
+        conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+    } else {
+        conn, err = dialer.Dial("tcp", addr)
+    }
+    if err != nil {
+        return nil, err
+    }
+    conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+    client, err := smtp.NewClient(conn, m.cfg.Host)
+    if err != nil {
+        conn.Close()
+        return nil, err
+    }
+    if _, implicitTLS := conn.(*tls.Conn); implicitTLS {
+        return client, nil
+    }
+    if ok, _ := client.Extension("STARTTLS"); ok {
+        if err := client.StartTLS(tlsConfig); err != nil {
+            client.Close()
+            return nil, err
+        }
+    }
+    return client, nil
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+)
+
+// apiTokenRepository 个人访问令牌内存仓储实现
+type apiTokenRepository struct {
+    store *Store
+}
+
+// NewAPITokenRepository 创建个人访问令牌内存仓储
+func NewAPITokenRepository(store *Store) repository.APITokenRepository {
+    return &apiTokenRepository{store: store}
+}
+
+// Create 保存令牌
+func (r *apiTokenRepository) Create(token *model.APIToken) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.nextAPITokenID++
+    token.ID = r.store.nextAPITokenID
+    token.CreatedAt = time.Now()
+
+    t := *token
+    t.Scopes = append([]string(nil), token.Scopes...)
+    r.store.apiTokens[t.ID] = &t
+    return nil
+}
+
+// GetByHash 按令牌哈希查找
+func (r *apiTokenRepository) GetByHash(tokenHash string) (*model.APIToken, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    for _, token := range r.store.apiTokens {
+        if token.TokenHash == tokenHash {
+            t := *token
+            return &t, nil
+        }
+    }
+    return nil, errors.New("令牌不存在")
+}
+
+// ListByUser 获取用户的全部令牌
+func (r *apiTokenRepository) ListByUser(userID uint) ([]*model.APIToken, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    tokens := []*model.APIToken{}
+    for _, token := range r.store.apiTokens {
+        if token.UserID == userID {
+            t := *token
+            tokens = append(tokens, &t)
+        }
+    }
+    sort.Slice(tokens, func(i, j int) bool { return tokens[i].ID < tokens[j].ID })
+    return tokens, nil
+}
+
+// Touch 更新最近使用时间
+func (r *apiTokenRepository) Touch(id uint, at time.Time) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if token, ok := r.store.apiTokens[id]; ok {
+        token.LastUsedAt = &at
+    }
+    return nil
+}
+
+// Delete 删除用户的令牌
+func (r *apiTokenRepository) Delete(userID, id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    token, ok := r.store.apiTokens[id]
+    if !ok || token.UserID != userID {
+        return errors.New("令牌不存在")
+    }
+    delete(r.store.apiTokens, id)
+    return nil
+}
+
+// DeleteByUser 删除用户的全部令牌
+func (r *apiTokenRepository) DeleteByUser(userID uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for id, token := range r.store.apiTokens {
+        if token.UserID == userID {
+            delete(r.store.apiTokens, id)
+        }
+    }
+    return nil
+}
+
+// DeleteExpired 删除before之前过期的令牌
+func (r *apiTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    var count int64
+    for id, token := range r.store.apiTokens {
+        if token.ExpiresAt != nil && token.ExpiresAt.Before(before) {
+            delete(r.store.apiTokens, id)
+            count++
+        }
+    }
+    return count, nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+)
+
+// attachmentRepository 附件内存仓储实现
+type attachmentRepository struct {
+    store *Store
+}
+
+// NewAttachmentRepository 创建附件内存仓储
+func NewAttachmentRepository(store *Store) repository.AttachmentRepository {
+    return &attachmentRepository{store: store}
+}
+
+// Create 保存附件记录
+func (r *attachmentRepository) Create(attachment *model.Attachment) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.nextAttachmentID++
+    attachment.ID = r.store.nextAttachmentID
+    attachment.CreatedAt = time.Now()
+
+    a := *attachment
+    r.store.attachments[a.ID] = &a
+    return nil
+}
+
+// GetByID 根据ID获取附件
+func (r *attachmentRepository) GetByID(id uint) (*model.Attachment, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    attachment, ok := r.store.attachments[id]
+    if !ok {
+        return nil, errors.New("附件不存在")
+    }
+    a := *attachment
+    return &a, nil
+}
+
+// GetByUserID 获取用户上传的附件（分页，按上传时间倒序）
+func (r *attachmentRepository) GetByUserID(userID uint, page, limit int) ([]*model.Attachment, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var attachments []*model.Attachment
+    for _, attachment := range r.store.attachments {
+        if attachment.UserID == userID {
+            a := *attachment
+            attachments = append(attachments, &a)
+        }
+    }
+    sort.Slice(attachments, func(i, j int) bool { return attachments[i].ID > attachments[j].ID })
+
+    return paginate(attachments, page, limit), int64(len(attachments)), nil
+}
+
+// Delete 删除附件记录
+func (r *attachmentRepository) Delete(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    delete(r.store.attachments, id)
+    return nil
+}
+
+// GetOrphaned 获取before之前被删除的用户留下的、未关联文章的附件
+func (r *attachmentRepository) GetOrphaned(before time.Time, limit int) ([]*model.Attachment, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var attachments []*model.Attachment
+    for _, attachment := range r.store.attachments {
+        owner, ok := r.store.deletedUsers[attachment.UserID]
+        if attachment.PostID == nil && ok && expired(owner.DeletedAt, before) {
+            a := *attachment
+            attachments = append(attachments, &a)
+        }
+    }
+    sort.Slice(attachments, func(i, j int) bool { return attachments[i].ID < attachments[j].ID })
+
+    return paginate(attachments, 1, limit), nil
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "sort"
+    "time"
+)
+
+// bookmarkRepository 收藏内存仓储实现
+type bookmarkRepository struct {
+    store *Store
+}
+
+// NewBookmarkRepository 创建收藏内存仓储
+func NewBookmarkRepository(store *Store) repository.BookmarkRepository {
+    return &bookmarkRepository{store: store}
+}
+
+// Add 添加收藏，已存在时不做修改
+func (r *bookmarkRepository) Add(bookmark *model.Bookmark) (bool, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if r.find(bookmark.UserID, bookmark.PostID) != nil {
+        return false, nil
+    }
+
+    r.store.nextBookmarkID++
+    bookmark.ID = r.store.nextBookmarkID
+    bookmark.CreatedAt = time.Now()
+
+    b := *bookmark
+    r.store.bookmarks[b.ID] = &b
+    if post := r.post(b.PostID); post != nil {
+        post.BookmarkCount++
+    }
+    return true, nil
+}
+
+// Remove 删除收藏，不存在时不做修改
+func (r *bookmarkRepository) Remove(userID, postID uint) (bool, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    bookmark := r.find(userID, postID)
+    if bookmark == nil {
+        return false, nil
+    }
+
+    delete(r.store.bookmarks, bookmark.ID)
+    if post := r.post(postID); post != nil {
+        post.BookmarkCount--
+    }
+    return true, nil
+}
+
+// Exists 用户是否收藏了文章
+func (r *bookmarkRepository) Exists(userID, postID uint) (bool, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return r.find(userID, postID) != nil, nil
+}
+
+// ListPosts 获取用户收藏的公开文章（分页），按收藏时间倒序
+func (r *bookmarkRepository) ListPosts(userID uint, page, limit int) ([]*model.Post, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var bookmarks []*model.Bookmark
+    for _, bookmark := range r.store.bookmarks {
+        if post, ok := r.store.posts[bookmark.PostID]; ok && bookmark.UserID == userID && post.IsPublic() {
+            bookmarks = append(bookmarks, bookmark)
+        }
+    }
+    sort.Slice(bookmarks, func(i, j int) bool {
+        if bookmarks[i].CreatedAt.Equal(bookmarks[j].CreatedAt) {
+            return bookmarks[i].ID > bookmarks[j].ID
+        }
+        return bookmarks[i].CreatedAt.After(bookmarks[j].CreatedAt)
+    })
+
+    paged := paginate(bookmarks, page, limit)
+    posts := make([]*model.Post, 0, len(paged))
+    for _, bookmark := range paged {
+        posts = append(posts, r.store.postWithAssociations(r.store.posts[bookmark.PostID]))
+    }
+    return posts, int64(len(bookmarks)), nil
+}
+
+// ListByUser 获取用户的全部收藏
+func (r *bookmarkRepository) ListByUser(userID uint) ([]*model.Bookmark, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var bookmarks []*model.Bookmark
+    for _, bookmark := range r.store.bookmarks {
+        if bookmark.UserID == userID {
+            b := *bookmark
+            bookmarks = append(bookmarks, &b)
+        }
+    }
+    sort.Slice(bookmarks, func(i, j int) bool { return bookmarks[i].ID < bookmarks[j].ID })
+    return bookmarks, nil
+}
+
+// DeleteByUser 删除用户的全部收藏并减少计数
+func (r *bookmarkRepository) DeleteByUser(userID uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for id, bookmark := range r.store.bookmarks {
+        if bookmark.UserID != userID {
+            continue
+        }
+        if post := r.post(bookmark.PostID); post != nil {
+            post.BookmarkCount--
+        }
+        delete(r.store.bookmarks, id)
+    }
+    return nil
+}
+
+// find 查找用户对文章的收藏（调用方需持有锁）
+func (r *bookmarkRepository) find(userID, postID uint) *model.Bookmark {
+    for _, bookmark := range r.store.bookmarks {
+        if bookmark.UserID == userID && bookmark.PostID == postID {
+            return bookmark
+        }
+    }
+    return nil
+}
+
+// post 查找文章，包括回收站中的（调用方需持有写锁）
+func (r *bookmarkRepository) post(id uint) *model.Post {
+    if post, ok := r.store.posts[id]; ok {
+        return post
+    }
+    return r.store.deletedPosts[id]
+}
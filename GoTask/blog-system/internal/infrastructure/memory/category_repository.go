@@ -0,0 +1,135 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+)
+
+// categoryRepository 分类内存仓储实现
+type categoryRepository struct {
+    store *Store
+}
+
+// NewCategoryRepository 创建分类内存仓储
+func NewCategoryRepository(store *Store) repository.CategoryRepository {
+    return &categoryRepository{store: store}
+}
+
+// Create 创建分类
+func (r *categoryRepository) Create(category *model.Category) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for _, existing := range r.store.categories {
+        if existing.Name == category.Name {
+            return errors.New("分类已存在")
+        }
+    }
+
+    r.store.nextCategoryID++
+    category.ID = r.store.nextCategoryID
+    category.CreatedAt = time.Now()
+
+    c := *category
+    r.store.categories[c.ID] = &c
+    return nil
+}
+
+// GetByID 根据ID获取分类
+func (r *categoryRepository) GetByID(id uint) (*model.Category, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    category, ok := r.store.categories[id]
+    if !ok {
+        return nil, errors.New("分类不存在")
+    }
+    c := *category
+    return &c, nil
+}
+
+// GetByName 根据名称获取分类
+func (r *categoryRepository) GetByName(name string) (*model.Category, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    for _, category := range r.store.categories {
+        if category.Name == name {
+            c := *category
+            return &c, nil
+        }
+    }
+    return nil, errors.New("分类不存在")
+}
+
+// GetAllWithCount 获取所有分类及其公开文章数
+func (r *categoryRepository) GetAllWithCount() ([]*model.CategoryCount, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    counts := make(map[uint]int64)
+    for postID, categoryIDs := range r.store.postCategories {
+        post, ok := r.store.posts[postID]
+        if !ok || !post.IsPublic() {
+            continue
+        }
+        for _, categoryID := range categoryIDs {
+            counts[categoryID]++
+        }
+    }
+
+    result := make([]*model.CategoryCount, 0, len(r.store.categories))
+    for _, category := range r.store.categories {
+        result = append(result, &model.CategoryCount{
+            ID:          category.ID,
+            Name:        category.Name,
+            Description: category.Description,
+            PostCount:   counts[category.ID],
+        })
+    }
+    sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+    return result, nil
+}
+
+// Update 更新分类
+func (r *categoryRepository) Update(category *model.Category) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if _, ok := r.store.categories[category.ID]; !ok {
+        return errors.New("分类不存在")
+    }
+    for _, existing := range r.store.categories {
+        if existing.ID != category.ID && existing.Name == category.Name {
+            return errors.New("分类已存在")
+        }
+    }
+
+    c := *category
+    r.store.categories[c.ID] = &c
+    return nil
+}
+
+// Delete 删除分类及其与文章的关联
+func (r *categoryRepository) Delete(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for postID, categoryIDs := range r.store.postCategories {
+        r.store.postCategories[postID] = removeID(categoryIDs, id)
+    }
+    delete(r.store.categories, id)
+    return nil
+}
+
+// SetPostCategories 替换文章的全部分类
+func (r *categoryRepository) SetPostCategories(postID uint, categoryIDs []uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.postCategories[postID] = append([]uint(nil), categoryIDs...)
+    return nil
+}
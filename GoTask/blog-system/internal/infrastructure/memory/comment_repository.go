@@ -0,0 +1,304 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// commentRepository 评论内存仓储实现
+type commentRepository struct {
+    store *Store
+}
+
+// NewCommentRepository 创建评论内存仓储
+func NewCommentRepository(store *Store) repository.CommentRepository {
+    return &commentRepository{store: store}
+}
+
+// Create 创建评论
+func (r *commentRepository) Create(comment *model.Comment) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.nextCommentID++
+    comment.ID = r.store.nextCommentID
+    comment.CreatedAt = time.Now()
+
+    c := *comment
+    r.store.comments[c.ID] = &c
+    return nil
+}
+
+// GetByID 根据ID获取评论
+func (r *commentRepository) GetByID(id uint) (*model.Comment, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    comment, ok := r.store.comments[id]
+    if !ok {
+        return nil, errors.New("评论不存在")
+    }
+    return r.store.commentWithUser(comment), nil
+}
+
+// GetByPostID 获取指定文章的所有评论（分页，不含已删除的占位评论）
+func (r *commentRepository) GetByPostID(postID uint, page, limit int) ([]*model.Comment, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var comments []*model.Comment
+    for _, comment := range r.store.comments {
+        if comment.PostID == postID && !comment.Deleted {
+            comments = append(comments, r.store.commentWithUser(comment))
+        }
+    }
+    sortCommentsDesc(comments)
+
+    return paginate(comments, page, limit), int64(len(comments)), nil
+}
+
+// GetByPostIDAfter 按游标获取指定文章的评论
+func (r *commentRepository) GetByPostIDAfter(postID uint, after *repository.Cursor, limit int) ([]*model.Comment, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var comments []*model.Comment
+    for _, comment := range r.store.comments {
+        if comment.PostID == postID && !comment.Deleted && after.After(comment.CreatedAt, comment.ID) {
+            comments = append(comments, r.store.commentWithUser(comment))
+        }
+    }
+    sortCommentsDesc(comments)
+
+    return paginate(comments, 1, limit), nil
+}
+
+// GetRootsByPostID 获取指定文章的顶层评论（分页）
+func (r *commentRepository) GetRootsByPostID(postID uint, page, limit int) ([]*model.Comment, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var comments []*model.Comment
+    for _, comment := range r.store.comments {
+        if comment.PostID == postID && comment.ParentID == nil {
+            comments = append(comments, r.store.commentWithUser(comment))
+        }
+    }
+    sortCommentsDesc(comments)
+
+    return paginate(comments, page, limit), int64(len(comments)), nil
+}
+
+// GetByRootIDs 获取指定顶层评论下的所有回复，按时间正序
+func (r *commentRepository) GetByRootIDs(rootIDs []uint) ([]*model.Comment, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    roots := make(map[uint]bool, len(rootIDs))
+    for _, id := range rootIDs {
+        roots[id] = true
+    }
+
+    comments := []*model.Comment{}
+    for _, comment := range r.store.comments {
+        if comment.RootID != 0 && roots[comment.RootID] {
+            comments = append(comments, r.store.commentWithUser(comment))
+        }
+    }
+    sortCommentsAsc(comments)
+
+    return comments, nil
+}
+
+// CountReplies 统计评论的直接回复数
+func (r *commentRepository) CountReplies(id uint) (int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var count int64
+    for _, comment := range r.store.comments {
+        if comment.ParentID != nil && *comment.ParentID == id {
+            count++
+        }
+    }
+    return count, nil
+}
+
+// Update 更新评论
+func (r *commentRepository) Update(comment *model.Comment) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    existing, ok := r.store.comments[comment.ID]
+    if !ok {
+        return errors.New("评论不存在")
+    }
+
+    c := *comment
+    c.User = model.User{}
+    c.Replies = nil
+    c.ReactionCount = existing.ReactionCount // 计数由回应仓储维护
+    r.store.comments[c.ID] = &c
+    return nil
+}
+
+// Delete 删除评论（软删除，移入回收站）
+func (r *commentRepository) Delete(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if comment, ok := r.store.comments[id]; ok {
+        comment.DeletedAt = softDeletedAt()
+        r.store.deletedComments[id] = comment
+        delete(r.store.comments, id)
+    }
+    return nil
+}
+
+// GetByIDWithDeleted 根据ID获取评论，包括回收站中的评论
+func (r *commentRepository) GetByIDWithDeleted(id uint) (*model.Comment, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    if comment, ok := r.store.comments[id]; ok {
+        return r.store.commentWithUser(comment), nil
+    }
+    if comment, ok := r.store.deletedComments[id]; ok {
+        return r.store.commentWithUser(comment), nil
+    }
+    return nil, errors.New("评论不存在")
+}
+
+// GetDeletedByUserID 获取用户已删除的评论（回收站中的评论和占位评论，分页）
+func (r *commentRepository) GetDeletedByUserID(userID uint, page, limit int) ([]*model.Comment, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var comments []*model.Comment
+    for _, comment := range r.store.deletedComments {
+        if comment.UserID == userID {
+            comments = append(comments, r.store.commentWithUser(comment))
+        }
+    }
+    for _, comment := range r.store.comments {
+        if comment.UserID == userID && comment.Deleted {
+            comments = append(comments, r.store.commentWithUser(comment))
+        }
+    }
+    sortCommentsDesc(comments)
+
+    return paginate(comments, page, limit), int64(len(comments)), nil
+}
+
+// Restore 从回收站恢复评论
+func (r *commentRepository) Restore(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    comment, ok := r.store.deletedComments[id]
+    if !ok {
+        return errors.New("评论不在回收站中")
+    }
+    comment.DeletedAt = gorm.DeletedAt{}
+    r.store.comments[id] = comment
+    delete(r.store.deletedComments, id)
+    return nil
+}
+
+// Purge 永久删除before之前进入回收站的评论
+func (r *commentRepository) Purge(before time.Time) (int64, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    var count int64
+    for id, comment := range r.store.deletedComments {
+        if expired(comment.DeletedAt, before) {
+            r.store.deleteReactions(model.ReactionTargetComment, id)
+            delete(r.store.deletedComments, id)
+            count++
+        }
+    }
+    return count, nil
+}
+
+// GetByUserID 获取用户的所有评论，包括占位评论和回收站中的评论（分页）
+func (r *commentRepository) GetByUserID(userID uint, page, limit int) ([]*model.Comment, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var comments []*model.Comment
+    for _, table := range []map[uint]*model.Comment{r.store.comments, r.store.deletedComments} {
+        for _, comment := range table {
+            if comment.UserID == userID {
+                comments = append(comments, r.store.commentWithUser(comment))
+            }
+        }
+    }
+    sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+
+    return paginate(comments, page, limit), int64(len(comments)), nil
+}
+
+// ReassignUser 将用户的所有评论（包括回收站中的）转给另一个用户
+func (r *commentRepository) ReassignUser(fromID, toID uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for _, table := range []map[uint]*model.Comment{r.store.comments, r.store.deletedComments} {
+        for _, comment := range table {
+            if comment.UserID == fromID {
+                comment.UserID = toID
+            }
+        }
+    }
+    return nil
+}
+
+// DeleteByUserID 删除用户的所有评论：仍有回复的评论保留为占位并转给placeholderID，其余移入回收站
+// 按层级从深到浅处理，用户自己的回复先被删除，不会让上级评论误留为占位
+func (r *commentRepository) DeleteByUserID(userID, placeholderID uint) ([]uint, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    var comments []*model.Comment
+    for _, comment := range r.store.comments {
+        if comment.UserID == userID {
+            comments = append(comments, comment)
+        }
+    }
+    sort.Slice(comments, func(i, j int) bool {
+        if comments[i].Depth == comments[j].Depth {
+            return comments[i].ID > comments[j].ID
+        }
+        return comments[i].Depth > comments[j].Depth
+    })
+
+    replies := make(map[uint]int)
+    for _, comment := range r.store.comments {
+        if comment.ParentID != nil {
+            replies[*comment.ParentID]++
+        }
+    }
+
+    ids := make([]uint, 0, len(comments))
+    for _, comment := range comments {
+        if replies[comment.ID] > 0 {
+            comment.Deleted = true
+            comment.UserID = placeholderID
+        } else {
+            comment.DeletedAt = softDeletedAt()
+            r.store.deletedComments[comment.ID] = comment
+            delete(r.store.comments, comment.ID)
+            if comment.ParentID != nil {
+                replies[*comment.ParentID]--
+            }
+        }
+        ids = append(ids, comment.ID)
+    }
+    return ids, nil
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+)
+
+// userIdentityRepository 外部身份内存仓储实现
+type userIdentityRepository struct {
+    store *Store
+}
+
+// NewUserIdentityRepository 创建外部身份内存仓储
+func NewUserIdentityRepository(store *Store) repository.UserIdentityRepository {
+    return &userIdentityRepository{store: store}
+}
+
+// Create 保存外部身份，同一外部账号已绑定时返回错误
+func (r *userIdentityRepository) Create(identity *model.UserIdentity) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for _, existing := range r.store.userIdentities {
+        if existing.Issuer == identity.Issuer && existing.Subject == identity.Subject {
+            return errors.New("该外部账号已绑定其他用户")
+        }
+    }
+
+    now := time.Now()
+    r.store.nextUserIdentityID++
+    identity.ID = r.store.nextUserIdentityID
+    identity.CreatedAt = now
+    identity.UpdatedAt = now
+
+    i := *identity
+    r.store.userIdentities[i.ID] = &i
+    return nil
+}
+
+// GetBySubject 按issuer和subject查找外部身份
+func (r *userIdentityRepository) GetBySubject(issuer, subject string) (*model.UserIdentity, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    for _, identity := range r.store.userIdentities {
+        if identity.Issuer == issuer && identity.Subject == subject {
+            i := *identity
+            return &i, nil
+        }
+    }
+    return nil, nil
+}
+
+// ListByUser 获取用户绑定的全部外部身份
+func (r *userIdentityRepository) ListByUser(userID uint) ([]*model.UserIdentity, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    identities := []*model.UserIdentity{}
+    for _, identity := range r.store.userIdentities {
+        if identity.UserID == userID {
+            i := *identity
+            identities = append(identities, &i)
+        }
+    }
+    sort.Slice(identities, func(i, j int) bool { return identities[i].ID < identities[j].ID })
+    return identities, nil
+}
+
+// Touch 更新最近登录时间和邮箱
+func (r *userIdentityRepository) Touch(id uint, email string) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if identity, ok := r.store.userIdentities[id]; ok {
+        identity.Email = email
+        identity.UpdatedAt = time.Now()
+    }
+    return nil
+}
+
+// Delete 删除外部身份
+func (r *userIdentityRepository) Delete(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    delete(r.store.userIdentities, id)
+    return nil
+}
+
+// oidcStateRepository 授权请求状态内存仓储实现
+type oidcStateRepository struct {
+    store *Store
+}
+
+// NewOIDCStateRepository 创建授权请求状态内存仓储
+func NewOIDCStateRepository(store *Store) repository.OIDCStateRepository {
+    return &oidcStateRepository{store: store}
+}
+
+// Create 保存授权请求状态
+func (r *oidcStateRepository) Create(state *model.OIDCState) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.nextOIDCStateID++
+    state.ID = r.store.nextOIDCStateID
+    state.CreatedAt = time.Now()
+
+    s := *state
+    r.store.oidcStates[s.ID] = &s
+    return nil
+}
+
+// Consume 删除未过期的状态并返回
+func (r *oidcStateRepository) Consume(stateHash string, now time.Time) (*model.OIDCState, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for id, state := range r.store.oidcStates {
+        if state.StateHash == stateHash && state.ExpiresAt.After(now) {
+            delete(r.store.oidcStates, id)
+            return state, nil
+        }
+    }
+    return nil, errors.New("登录请求无效或已过期，请重新登录")
+}
+
+// DeleteExpired 删除before之前过期的状态
+func (r *oidcStateRepository) DeleteExpired(before time.Time) (int64, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    var count int64
+    for id, state := range r.store.oidcStates {
+        if state.ExpiresAt.Before(before) {
+            delete(r.store.oidcStates, id)
+            count++
+        }
+    }
+    return count, nil
+}
@@ -0,0 +1,202 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+)
+
+// loginAttemptRepository 登录尝试审计记录内存仓储实现
+type loginAttemptRepository struct {
+    store *Store
+}
+
+// NewLoginAttemptRepository 创建登录尝试审计记录内存仓储
+func NewLoginAttemptRepository(store *Store) repository.LoginAttemptRepository {
+    return &loginAttemptRepository{store: store}
+}
+
+// Create 保存登录记录
+func (r *loginAttemptRepository) Create(attempt *model.LoginAttempt) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.nextLoginAttemptID++
+    attempt.ID = r.store.nextLoginAttemptID
+    attempt.CreatedAt = time.Now()
+
+    a := *attempt
+    r.store.loginAttempts[a.ID] = &a
+    return nil
+}
+
+// List 查询登录记录（分页，按时间倒序）
+func (r *loginAttemptRepository) List(username, ip string, page, limit int) ([]*model.LoginAttempt, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var attempts []*model.LoginAttempt
+    for _, attempt := range r.store.loginAttempts {
+        if (username == "" || attempt.Username == username) && (ip == "" || attempt.IP == ip) {
+            a := *attempt
+            attempts = append(attempts, &a)
+        }
+    }
+    sort.Slice(attempts, func(i, j int) bool { return attempts[i].ID > attempts[j].ID })
+
+    return paginate(attempts, page, limit), int64(len(attempts)), nil
+}
+
+// DeleteBefore 删除before之前的记录
+func (r *loginAttemptRepository) DeleteBefore(before time.Time) (int64, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    var count int64
+    for id, attempt := range r.store.loginAttempts {
+        if attempt.CreatedAt.Before(before) {
+            delete(r.store.loginAttempts, id)
+            count++
+        }
+    }
+    return count, nil
+}
+
+// loginLockoutRepository 登录失败计数和锁定状态内存仓储实现
+type loginLockoutRepository struct {
+    store *Store
+}
+
+// NewLoginLockoutRepository 创建登录失败计数和锁定状态内存仓储
+func NewLoginLockoutRepository(store *Store) repository.LoginLockoutRepository {
+    return &loginLockoutRepository{store: store}
+}
+
+// Get 获取计数记录，不存在时返回nil
+func (r *loginLockoutRepository) Get(scope, subject string) (*model.LoginLockout, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    if lockout := r.store.findLoginLockout(scope, subject); lockout != nil {
+        l := *lockout
+        return &l, nil
+    }
+    return nil, nil
+}
+
+// GetByID 根据ID获取计数记录
+func (r *loginLockoutRepository) GetByID(id uint) (*model.LoginLockout, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    lockout, ok := r.store.loginLockouts[id]
+    if !ok {
+        return nil, errors.New("锁定记录不存在")
+    }
+    l := *lockout
+    return &l, nil
+}
+
+// AddFailure 将失败次数加一，上次失败早于resetBefore时从1重新计数
+func (r *loginLockoutRepository) AddFailure(scope, subject string, now, resetBefore time.Time) (*model.LoginLockout, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    lockout := r.store.findLoginLockout(scope, subject)
+    if lockout == nil {
+        r.store.nextLoginLockoutID++
+        lockout = &model.LoginLockout{ID: r.store.nextLoginLockoutID, Scope: scope, Subject: subject}
+        r.store.loginLockouts[lockout.ID] = lockout
+    }
+
+    if lockout.LastFailedAt.Before(resetBefore) {
+        lockout.Failures = 1
+    } else {
+        lockout.Failures++
+    }
+    lockout.LastFailedAt = now
+    lockout.UpdatedAt = now
+
+    l := *lockout
+    return &l, nil
+}
+
+// Lock 设置锁定截止时间
+func (r *loginLockoutRepository) Lock(id uint, until time.Time) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if lockout, ok := r.store.loginLockouts[id]; ok {
+        lockout.LockedUntil = &until
+    }
+    return nil
+}
+
+// Reset 清除计数
+func (r *loginLockoutRepository) Reset(scope, subject string) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if lockout := r.store.findLoginLockout(scope, subject); lockout != nil {
+        delete(r.store.loginLockouts, lockout.ID)
+    }
+    return nil
+}
+
+// List 获取计数记录（分页，按最近失败时间倒序）
+func (r *loginLockoutRepository) List(lockedOnly bool, now time.Time, page, limit int) ([]*model.LoginLockout, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var lockouts []*model.LoginLockout
+    for _, lockout := range r.store.loginLockouts {
+        if !lockedOnly || lockout.IsLocked(now) {
+            l := *lockout
+            lockouts = append(lockouts, &l)
+        }
+    }
+    sort.Slice(lockouts, func(i, j int) bool {
+        if lockouts[i].LastFailedAt.Equal(lockouts[j].LastFailedAt) {
+            return lockouts[i].ID > lockouts[j].ID
+        }
+        return lockouts[i].LastFailedAt.After(lockouts[j].LastFailedAt)
+    })
+
+    return paginate(lockouts, page, limit), int64(len(lockouts)), nil
+}
+
+// Delete 删除计数记录（解除锁定）
+func (r *loginLockoutRepository) Delete(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    delete(r.store.loginLockouts, id)
+    return nil
+}
+
+// DeleteStale 删除最近失败早于before且已解除锁定的记录
+func (r *loginLockoutRepository) DeleteStale(before, now time.Time) (int64, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    var count int64
+    for id, lockout := range r.store.loginLockouts {
+        if lockout.LastFailedAt.Before(before) && !lockout.IsLocked(now) {
+            delete(r.store.loginLockouts, id)
+            count++
+        }
+    }
+    return count, nil
+}
+
+// findLoginLockout 按维度和值查找计数记录（调用方需持有锁）
+func (s *Store) findLoginLockout(scope, subject string) *model.LoginLockout {
+    for _, lockout := range s.loginLockouts {
+        if lockout.Scope == scope && lockout.Subject == subject {
+            return lockout
+        }
+    }
+    return nil
+}
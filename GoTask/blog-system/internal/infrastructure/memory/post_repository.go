@@ -0,0 +1,425 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "cmp"
+    "errors"
+    "sort"
+    "strings"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// postRepository 文章内存仓储实现
+type postRepository struct {
+    store *Store
+}
+
+// NewPostRepository 创建文章内存仓储
+func NewPostRepository(store *Store) repository.PostRepository {
+    return &postRepository{store: store}
+}
+
+// Create 创建文章
+func (r *postRepository) Create(post *model.Post) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.nextPostID++
+    now := time.Now()
+    post.ID = r.store.nextPostID
+    post.CreatedAt = now
+    post.UpdatedAt = now
+
+    p := *post
+    r.store.posts[p.ID] = &p
+    return nil
+}
+
+// GetByID 根据ID获取文章
+func (r *postRepository) GetByID(id uint) (*model.Post, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    post, ok := r.store.posts[id]
+    if !ok {
+        return nil, errors.New("文章不存在")
+    }
+    return r.store.postWithAssociations(post), nil
+}
+
+// GetAll 获取所有公开的文章（分页），可按标签和分类筛选
+func (r *postRepository) GetAll(filter repository.PostFilter, page, limit int) ([]*model.Post, int64, error) {
+    return r.list(page, limit, filter.Sort, r.matchFilter(filter))
+}
+
+// GetByUserID 获取指定用户的所有公开文章（分页）
+func (r *postRepository) GetByUserID(userID uint, page, limit int) ([]*model.Post, int64, error) {
+    return r.list(page, limit, nil, func(p *model.Post) bool { return p.IsPublic() && p.UserID == userID })
+}
+
+// GetAllAfter 按游标获取公开的文章
+func (r *postRepository) GetAllAfter(filter repository.PostFilter, after *repository.Cursor, limit int) ([]*model.Post, error) {
+    match := r.matchFilter(filter)
+    posts, _, err := r.list(1, limit, nil, func(p *model.Post) bool { return after.After(p.CreatedAt, p.ID) && match(p) })
+    return posts, err
+}
+
+// GetByUserIDAfter 按游标获取指定用户的公开文章
+func (r *postRepository) GetByUserIDAfter(userID uint, after *repository.Cursor, limit int) ([]*model.Post, error) {
+    posts, _, err := r.list(1, limit, nil, func(p *model.Post) bool {
+        return after.After(p.CreatedAt, p.ID) && p.IsPublic() && p.UserID == userID
+    })
+    return posts, err
+}
+
+// matchFilter 公开文章列表的筛选条件（调用时需持有读锁）
+func (r *postRepository) matchFilter(filter repository.PostFilter) func(p *model.Post) bool {
+    return func(p *model.Post) bool {
+        if !p.IsPublic() {
+            return false
+        }
+        if filter.Tag != "" && !r.hasTag(p.ID, filter.Tag) {
+            return false
+        }
+        if filter.Category != "" && !r.hasCategory(p.ID, filter.Category) {
+            return false
+        }
+        if filter.Author != "" {
+            if user, ok := r.store.users[p.UserID]; !ok || user.Username != filter.Author {
+                return false
+            }
+        }
+        if filter.CreatedAfter != nil && p.CreatedAt.Before(*filter.CreatedAfter) {
+            return false
+        }
+        if filter.CreatedBefore != nil && !p.CreatedAt.Before(*filter.CreatedBefore) {
+            return false
+        }
+        if filter.TitleContains != "" && !strings.Contains(strings.ToLower(p.Title), strings.ToLower(filter.TitleContains)) {
+            return false
+        }
+        return true
+    }
+}
+
+// GetByAuthor 获取作者自己的文章（分页），包含草稿等所有状态，status为空表示不筛选
+func (r *postRepository) GetByAuthor(userID uint, status string, page, limit int) ([]*model.Post, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var posts []*model.Post
+    for _, post := range r.store.posts {
+        if post.UserID == userID && (status == "" || post.Status == status) {
+            posts = append(posts, r.store.postWithAssociations(post))
+        }
+    }
+    sort.Slice(posts, func(i, j int) bool {
+        if posts[i].UpdatedAt.Equal(posts[j].UpdatedAt) {
+            return posts[i].ID > posts[j].ID
+        }
+        return posts[i].UpdatedAt.After(posts[j].UpdatedAt)
+    })
+
+    return paginate(posts, page, limit), int64(len(posts)), nil
+}
+
+// GetDueScheduled 获取发布时间已到的定时文章
+func (r *postRepository) GetDueScheduled(now time.Time, limit int) ([]*model.Post, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var posts []*model.Post
+    for _, post := range r.store.posts {
+        if post.Status == model.PostStatusScheduled && post.PublishAt != nil && !post.PublishAt.After(now) {
+            posts = append(posts, r.store.postWithAssociations(post))
+        }
+    }
+    sort.Slice(posts, func(i, j int) bool { return posts[i].PublishAt.Before(*posts[j].PublishAt) })
+
+    return paginate(posts, 1, limit), nil
+}
+
+// GetWithoutContentHTML 获取尚未生成content_html的文章，包括回收站中的
+func (r *postRepository) GetWithoutContentHTML(limit int) ([]*model.Post, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var posts []*model.Post
+    for _, table := range []map[uint]*model.Post{r.store.posts, r.store.deletedPosts} {
+        for _, post := range table {
+            if post.ContentHTML == "" && strings.TrimSpace(post.Content) != "" {
+                p := *post
+                posts = append(posts, &p)
+            }
+        }
+    }
+    sort.Slice(posts, func(i, j int) bool { return posts[i].ID < posts[j].ID })
+
+    return paginate(posts, 1, limit), nil
+}
+
+// SetContentHTML 只更新文章的content_html，不修改更新时间
+func (r *postRepository) SetContentHTML(id uint, html string) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if post, ok := r.store.posts[id]; ok {
+        post.ContentHTML = html
+    } else if post, ok := r.store.deletedPosts[id]; ok {
+        post.ContentHTML = html
+    }
+    return nil
+}
+
+// Update 更新文章
+func (r *postRepository) Update(post *model.Post) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    existing, ok := r.store.posts[post.ID]
+    if !ok {
+        return errors.New("文章不存在")
+    }
+
+    post.UpdatedAt = time.Now()
+    p := *post
+    p.User = model.User{}
+    p.Tags = nil
+    p.Categories = nil
+    // 计数由回应和收藏仓储维护，不使用读到的旧值
+    p.ReactionCount = existing.ReactionCount
+    p.BookmarkCount = existing.BookmarkCount
+    r.store.posts[p.ID] = &p
+    return nil
+}
+
+// Delete 删除文章（软删除，移入回收站），评论和关联数据保留以便恢复
+func (r *postRepository) Delete(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if post, ok := r.store.posts[id]; ok {
+        post.DeletedAt = softDeletedAt()
+        r.store.deletedPosts[id] = post
+        delete(r.store.posts, id)
+    }
+    return nil
+}
+
+// GetDeletedByID 获取回收站中的文章
+func (r *postRepository) GetDeletedByID(id uint) (*model.Post, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    post, ok := r.store.deletedPosts[id]
+    if !ok {
+        return nil, errors.New("文章不在回收站中")
+    }
+    return r.store.postWithAssociations(post), nil
+}
+
+// GetDeletedByAuthor 获取作者回收站中的文章（分页）
+func (r *postRepository) GetDeletedByAuthor(userID uint, page, limit int) ([]*model.Post, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var posts []*model.Post
+    for _, post := range r.store.deletedPosts {
+        if post.UserID == userID {
+            posts = append(posts, r.store.postWithAssociations(post))
+        }
+    }
+    sort.Slice(posts, func(i, j int) bool { return posts[i].DeletedAt.Time.After(posts[j].DeletedAt.Time) })
+
+    return paginate(posts, page, limit), int64(len(posts)), nil
+}
+
+// Restore 从回收站恢复文章
+func (r *postRepository) Restore(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    post, ok := r.store.deletedPosts[id]
+    if !ok {
+        return errors.New("文章不在回收站中")
+    }
+    post.DeletedAt = gorm.DeletedAt{}
+    r.store.posts[id] = post
+    delete(r.store.deletedPosts, id)
+    return nil
+}
+
+// Purge 永久删除before之前进入回收站的文章及其评论、修订记录和关联，附件保留给上传者
+func (r *postRepository) Purge(before time.Time) (int64, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    var count int64
+    for id, post := range r.store.deletedPosts {
+        if !expired(post.DeletedAt, before) {
+            continue
+        }
+        for commentID, comment := range r.store.comments {
+            if comment.PostID == id {
+                r.store.deleteReactions(model.ReactionTargetComment, commentID)
+                delete(r.store.comments, commentID)
+            }
+        }
+        for commentID, comment := range r.store.deletedComments {
+            if comment.PostID == id {
+                r.store.deleteReactions(model.ReactionTargetComment, commentID)
+                delete(r.store.deletedComments, commentID)
+            }
+        }
+        r.store.deleteReactions(model.ReactionTargetPost, id)
+        for bookmarkID, bookmark := range r.store.bookmarks {
+            if bookmark.PostID == id {
+                delete(r.store.bookmarks, bookmarkID)
+            }
+        }
+        for revisionID, rev := range r.store.revisions {
+            if rev.PostID == id {
+                delete(r.store.revisions, revisionID)
+            }
+        }
+        for _, attachment := range r.store.attachments {
+            if attachment.PostID != nil && *attachment.PostID == id {
+                attachment.PostID = nil
+            }
+        }
+        delete(r.store.postTags, id)
+        delete(r.store.postCategories, id)
+        delete(r.store.deletedPosts, id)
+        count++
+    }
+    return count, nil
+}
+
+// ReassignUser 将用户的所有文章（包括回收站中的）、修订记录和文章中的附件转给另一个用户
+func (r *postRepository) ReassignUser(fromID, toID uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for _, posts := range []map[uint]*model.Post{r.store.posts, r.store.deletedPosts} {
+        for _, post := range posts {
+            if post.UserID == fromID {
+                post.UserID = toID
+            }
+        }
+    }
+    for _, attachment := range r.store.attachments {
+        if attachment.UserID == fromID && attachment.PostID != nil {
+            attachment.UserID = toID
+        }
+    }
+    for _, rev := range r.store.revisions {
+        if rev.UserID == fromID {
+            rev.UserID = toID
+        }
+    }
+    return nil
+}
+
+// DeleteByUserID 将用户的所有文章移入回收站，返回被删除的文章ID
+func (r *postRepository) DeleteByUserID(userID uint) ([]uint, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    ids := []uint{}
+    for id, post := range r.store.posts {
+        if post.UserID == userID {
+            post.DeletedAt = softDeletedAt()
+            r.store.deletedPosts[id] = post
+            delete(r.store.posts, id)
+            ids = append(ids, id)
+        }
+    }
+    return ids, nil
+}
+
+// list 按条件筛选并分页
+func (r *postRepository) list(page, limit int, sorts []repository.PostSort, match func(p *model.Post) bool) ([]*model.Post, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var posts []*model.Post
+    for _, post := range r.store.posts {
+        if match(post) {
+            posts = append(posts, r.store.postWithAssociations(post))
+        }
+    }
+    if len(sorts) == 0 {
+        sortPostsDesc(posts)
+    } else {
+        sortPostsBy(posts, sorts)
+    }
+
+    return paginate(posts, page, limit), int64(len(posts)), nil
+}
+
+// sortPostsBy 按排序条件排序文章，最后按ID倒序，与数据库实现一致（NULL排在最前）
+func sortPostsBy(posts []*model.Post, sorts []repository.PostSort) {
+    sort.SliceStable(posts, func(i, j int) bool {
+        for _, s := range sorts {
+            c := comparePosts(posts[i], posts[j], s.Field)
+            if c == 0 {
+                continue
+            }
+            if s.Desc {
+                return c > 0
+            }
+            return c < 0
+        }
+        return posts[i].ID > posts[j].ID
+    })
+}
+
+// comparePosts 按单个字段比较两篇文章
+func comparePosts(a, b *model.Post, field string) int {
+    switch field {
+    case repository.PostSortCreatedAt:
+        return a.CreatedAt.Compare(b.CreatedAt)
+    case repository.PostSortUpdatedAt:
+        return a.UpdatedAt.Compare(b.UpdatedAt)
+    case repository.PostSortPublishAt:
+        switch {
+        case a.PublishAt == nil && b.PublishAt == nil:
+            return 0
+        case a.PublishAt == nil:
+            return -1
+        case b.PublishAt == nil:
+            return 1
+        }
+        return a.PublishAt.Compare(*b.PublishAt)
+    case repository.PostSortTitle:
+        return strings.Compare(a.Title, b.Title)
+    case repository.PostSortReactions:
+        return cmp.Compare(a.ReactionCount, b.ReactionCount)
+    case repository.PostSortBookmarks:
+        return cmp.Compare(a.BookmarkCount, b.BookmarkCount)
+    }
+    return 0
+}
+
+// hasTag 文章是否带有指定名称的标签（调用方需持有读锁）
+func (r *postRepository) hasTag(postID uint, name string) bool {
+    for _, tagID := range r.store.postTags[postID] {
+        if tag, ok := r.store.tags[tagID]; ok && tag.Name == name {
+            return true
+        }
+    }
+    return false
+}
+
+// hasCategory 文章是否属于指定名称的分类（调用方需持有读锁）
+func (r *postRepository) hasCategory(postID uint, name string) bool {
+    for _, categoryID := range r.store.postCategories[postID] {
+        if category, ok := r.store.categories[categoryID]; ok && category.Name == name {
+            return true
+        }
+    }
+    return false
+}
\ No newline at end of file
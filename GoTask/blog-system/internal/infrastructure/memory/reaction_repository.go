@@ -0,0 +1,147 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "sort"
+    "time"
+)
+
+// reactionRepository 表情回应内存仓储实现
+type reactionRepository struct {
+    store *Store
+}
+
+// NewReactionRepository 创建表情回应内存仓储
+func NewReactionRepository(store *Store) repository.ReactionRepository {
+    return &reactionRepository{store: store}
+}
+
+// Add 添加回应，已存在时不做修改
+func (r *reactionRepository) Add(reaction *model.Reaction) (bool, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if r.find(reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Emoji) != nil {
+        return false, nil
+    }
+
+    r.store.nextReactionID++
+    reaction.ID = r.store.nextReactionID
+    reaction.CreatedAt = time.Now()
+
+    v := *reaction
+    r.store.reactions[v.ID] = &v
+    if counter := r.store.reactionCounter(v.TargetType, v.TargetID); counter != nil {
+        *counter++
+    }
+    return true, nil
+}
+
+// Remove 删除回应，不存在时不做修改
+func (r *reactionRepository) Remove(userID uint, targetType string, targetID uint, emoji string) (bool, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    reaction := r.find(userID, targetType, targetID, emoji)
+    if reaction == nil {
+        return false, nil
+    }
+
+    delete(r.store.reactions, reaction.ID)
+    if counter := r.store.reactionCounter(targetType, targetID); counter != nil {
+        *counter--
+    }
+    return true, nil
+}
+
+// CountByTarget 按表情统计目标的回应数
+func (r *reactionRepository) CountByTarget(targetType string, targetID uint) (map[string]int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    counts := make(map[string]int64)
+    for _, reaction := range r.store.reactions {
+        if reaction.TargetType == targetType && reaction.TargetID == targetID {
+            counts[reaction.Emoji]++
+        }
+    }
+    return counts, nil
+}
+
+// ListByUser 用户对目标的全部回应表情
+func (r *reactionRepository) ListByUser(userID uint, targetType string, targetID uint) ([]string, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var reactions []*model.Reaction
+    for _, reaction := range r.store.reactions {
+        if reaction.UserID == userID && reaction.TargetType == targetType && reaction.TargetID == targetID {
+            reactions = append(reactions, reaction)
+        }
+    }
+    sort.Slice(reactions, func(i, j int) bool { return reactions[i].ID < reactions[j].ID })
+
+    emojis := make([]string, 0, len(reactions))
+    for _, reaction := range reactions {
+        emojis = append(emojis, reaction.Emoji)
+    }
+    return emojis, nil
+}
+
+// DeleteByUser 删除用户的全部回应并减少计数
+func (r *reactionRepository) DeleteByUser(userID uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for id, reaction := range r.store.reactions {
+        if reaction.UserID != userID {
+            continue
+        }
+        if counter := r.store.reactionCounter(reaction.TargetType, reaction.TargetID); counter != nil {
+            *counter--
+        }
+        delete(r.store.reactions, id)
+    }
+    return nil
+}
+
+// find 查找用户对目标的某个回应（调用方需持有锁）
+func (r *reactionRepository) find(userID uint, targetType string, targetID uint, emoji string) *model.Reaction {
+    for _, reaction := range r.store.reactions {
+        if reaction.UserID == userID && reaction.TargetType == targetType && reaction.TargetID == targetID && reaction.Emoji == emoji {
+            return reaction
+        }
+    }
+    return nil
+}
+
+// reactionCounter 目标的回应计数，回收站中的目标同样返回，目标不存在时为nil（调用方需持有写锁）
+func (s *Store) reactionCounter(targetType string, targetID uint) *int64 {
+    switch targetType {
+    case model.ReactionTargetPost:
+        if post, ok := s.posts[targetID]; ok {
+            return &post.ReactionCount
+        }
+        if post, ok := s.deletedPosts[targetID]; ok {
+            return &post.ReactionCount
+        }
+    case model.ReactionTargetComment:
+        if comment, ok := s.comments[targetID]; ok {
+            return &comment.ReactionCount
+        }
+        if comment, ok := s.deletedComments[targetID]; ok {
+            return &comment.ReactionCount
+        }
+    }
+    return nil
+}
+
+// deleteReactions 删除目标上的全部回应，用于永久删除文章和评论（调用方需持有写锁）
+func (s *Store) deleteReactions(targetType string, targetID uint) {
+    for id, reaction := range s.reactions {
+        if reaction.TargetType == targetType && reaction.TargetID == targetID {
+            delete(s.reactions, id)
+        }
+    }
+}
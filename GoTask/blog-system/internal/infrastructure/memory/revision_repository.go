@@ -0,0 +1,80 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+)
+
+// postRevisionRepository 文章修订记录内存仓储实现
+type postRevisionRepository struct {
+    store *Store
+}
+
+// NewPostRevisionRepository 创建文章修订记录内存仓储
+func NewPostRevisionRepository(store *Store) repository.PostRevisionRepository {
+    return &postRevisionRepository{store: store}
+}
+
+// Create 保存修订记录，Revision为0时自动分配下一个版本号
+func (r *postRevisionRepository) Create(revision *model.PostRevision) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    latest := 0
+    for _, rev := range r.store.revisions {
+        if rev.PostID != revision.PostID {
+            continue
+        }
+        if rev.Revision == revision.Revision {
+            return errors.New("修订版本已存在")
+        }
+        if rev.Revision > latest {
+            latest = rev.Revision
+        }
+    }
+    if revision.Revision == 0 {
+        revision.Revision = latest + 1
+    }
+
+    r.store.nextRevisionID++
+    revision.ID = r.store.nextRevisionID
+    revision.CreatedAt = time.Now()
+
+    rev := *revision
+    r.store.revisions[rev.ID] = &rev
+    return nil
+}
+
+// GetByPostID 获取文章的修订记录（分页，按版本号倒序）
+func (r *postRevisionRepository) GetByPostID(postID uint, page, limit int) ([]*model.PostRevision, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var revisions []*model.PostRevision
+    for _, rev := range r.store.revisions {
+        if rev.PostID == postID {
+            c := *rev
+            revisions = append(revisions, &c)
+        }
+    }
+    sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision > revisions[j].Revision })
+
+    return paginate(revisions, page, limit), int64(len(revisions)), nil
+}
+
+// GetByRevision 根据版本号获取修订记录
+func (r *postRevisionRepository) GetByRevision(postID uint, revision int) (*model.PostRevision, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    for _, rev := range r.store.revisions {
+        if rev.PostID == postID && rev.Revision == revision {
+            c := *rev
+            return &c, nil
+        }
+    }
+    return nil, errors.New("修订版本不存在")
+}
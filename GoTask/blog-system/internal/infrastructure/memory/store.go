@@ -0,0 +1,310 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "sort"
+    "sync"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// Store 内存数据存储，供各内存仓储共享，适用于本地开发和测试
+type Store struct {
+    mu   sync.RWMutex
+    txMu sync.Mutex // 串行执行事务
+
+    users    map[uint]*model.User
+    posts    map[uint]*model.Post
+    comments map[uint]*model.Comment
+
+    // 回收站：软删除的记录从上面的表移到这里，恢复时再移回
+    deletedUsers    map[uint]*model.User
+    deletedPosts    map[uint]*model.Post
+    deletedComments map[uint]*model.Comment
+
+    tags           map[uint]*model.Tag
+    categories     map[uint]*model.Category
+    postTags       map[uint][]uint // 文章ID -> 标签ID
+    postCategories map[uint][]uint // 文章ID -> 分类ID
+    revisions      map[uint]*model.PostRevision
+    attachments    map[uint]*model.Attachment
+    loginAttempts  map[uint]*model.LoginAttempt
+    loginLockouts  map[uint]*model.LoginLockout
+    userTokens     map[uint]*model.UserToken
+    userTOTPs      map[uint]*model.UserTOTP
+    recoveryCodes  map[uint]*model.RecoveryCode
+    userIdentities map[uint]*model.UserIdentity
+    oidcStates     map[uint]*model.OIDCState
+    apiTokens      map[uint]*model.APIToken
+    reactions      map[uint]*model.Reaction
+    bookmarks      map[uint]*model.Bookmark
+
+    nextUserID         uint
+    nextPostID         uint
+    nextCommentID      uint
+    nextTagID          uint
+    nextCategoryID     uint
+    nextRevisionID     uint
+    nextAttachmentID   uint
+    nextLoginAttemptID uint
+    nextLoginLockoutID uint
+    nextUserTokenID    uint
+    nextUserTOTPID     uint
+    nextRecoveryCodeID uint
+    nextUserIdentityID uint
+    nextOIDCStateID    uint
+    nextAPITokenID     uint
+    nextReactionID     uint
+    nextBookmarkID     uint
+}
+
+// NewStore 创建内存数据存储
+func NewStore() *Store {
+    return &Store{
+        users:    make(map[uint]*model.User),
+        posts:    make(map[uint]*model.Post),
+        comments: make(map[uint]*model.Comment),
+
+        deletedUsers:    make(map[uint]*model.User),
+        deletedPosts:    make(map[uint]*model.Post),
+        deletedComments: make(map[uint]*model.Comment),
+
+        tags:           make(map[uint]*model.Tag),
+        categories:     make(map[uint]*model.Category),
+        postTags:       make(map[uint][]uint),
+        postCategories: make(map[uint][]uint),
+        revisions:      make(map[uint]*model.PostRevision),
+        attachments:    make(map[uint]*model.Attachment),
+        loginAttempts:  make(map[uint]*model.LoginAttempt),
+        loginLockouts:  make(map[uint]*model.LoginLockout),
+        userTokens:     make(map[uint]*model.UserToken),
+        userTOTPs:      make(map[uint]*model.UserTOTP),
+        recoveryCodes:  make(map[uint]*model.RecoveryCode),
+        userIdentities: make(map[uint]*model.UserIdentity),
+        oidcStates:     make(map[uint]*model.OIDCState),
+        apiTokens:      make(map[uint]*model.APIToken),
+        reactions:      make(map[uint]*model.Reaction),
+        bookmarks:      make(map[uint]*model.Bookmark),
+    }
+}
+
+// paginate 按页码截取切片
+func paginate[T any](items []T, page, limit int) []T {
+    offset := (page - 1) * limit
+    if offset < 0 {
+        offset = 0
+    }
+    if offset >= len(items) {
+        return []T{}
+    }
+    end := len(items)
+    if limit > 0 && offset+limit < end {
+        end = offset + limit
+    }
+    return items[offset:end]
+}
+
+// sortPostsDesc 按创建时间倒序排序文章
+func sortPostsDesc(posts []*model.Post) {
+    sort.Slice(posts, func(i, j int) bool {
+        if posts[i].CreatedAt.Equal(posts[j].CreatedAt) {
+            return posts[i].ID > posts[j].ID
+        }
+        return posts[i].CreatedAt.After(posts[j].CreatedAt)
+    })
+}
+
+// sortCommentsDesc 按创建时间倒序排序评论
+func sortCommentsDesc(comments []*model.Comment) {
+    sort.Slice(comments, func(i, j int) bool {
+        if comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+            return comments[i].ID > comments[j].ID
+        }
+        return comments[i].CreatedAt.After(comments[j].CreatedAt)
+    })
+}
+
+// sortCommentsAsc 按创建时间正序排序评论
+func sortCommentsAsc(comments []*model.Comment) {
+    sort.Slice(comments, func(i, j int) bool {
+        if comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+            return comments[i].ID < comments[j].ID
+        }
+        return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+    })
+}
+
+// postWithAssociations 复制文章并填充作者、标签、分类和附件（调用方需持有读锁）
+func (s *Store) postWithAssociations(post *model.Post) *model.Post {
+    p := *post
+    if user, ok := s.users[p.UserID]; ok {
+        p.User = *user
+    }
+
+    p.Tags = []model.Tag{}
+    for _, tagID := range s.postTags[p.ID] {
+        if tag, ok := s.tags[tagID]; ok {
+            p.Tags = append(p.Tags, *tag)
+        }
+    }
+
+    p.Categories = []model.Category{}
+    for _, categoryID := range s.postCategories[p.ID] {
+        if category, ok := s.categories[categoryID]; ok {
+            p.Categories = append(p.Categories, *category)
+        }
+    }
+
+    p.Attachments = []model.Attachment{}
+    for _, attachment := range s.attachments {
+        if attachment.PostID != nil && *attachment.PostID == p.ID {
+            p.Attachments = append(p.Attachments, *attachment)
+        }
+    }
+    sort.Slice(p.Attachments, func(i, j int) bool { return p.Attachments[i].ID < p.Attachments[j].ID })
+    return &p
+}
+
+// removeID 从ID列表中删除指定ID
+func removeID(ids []uint, id uint) []uint {
+    result := ids[:0]
+    for _, v := range ids {
+        if v != id {
+            result = append(result, v)
+        }
+    }
+    return result
+}
+
+// commentWithUser 复制评论并填充作者信息（调用方需持有读锁）
+func (s *Store) commentWithUser(comment *model.Comment) *model.Comment {
+    c := *comment
+    if user, ok := s.users[c.UserID]; ok {
+        c.User = *user
+    }
+    return &c
+}
+
+// softDeletedAt 生成回收站记录的删除时间
+func softDeletedAt() gorm.DeletedAt {
+    return gorm.DeletedAt{Time: time.Now(), Valid: true}
+}
+
+// expired 回收站记录是否在before之前删除
+func expired(deletedAt gorm.DeletedAt, before time.Time) bool {
+    return deletedAt.Valid && deletedAt.Time.Before(before)
+}
+
+// snapshot 复制全部数据，用于事务回滚
+func (s *Store) snapshot() *Store {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    return &Store{
+        users:              copyTable(s.users),
+        posts:              copyTable(s.posts),
+        comments:           copyTable(s.comments),
+        deletedUsers:       copyTable(s.deletedUsers),
+        deletedPosts:       copyTable(s.deletedPosts),
+        deletedComments:    copyTable(s.deletedComments),
+        tags:               copyTable(s.tags),
+        categories:         copyTable(s.categories),
+        postTags:           copyLinks(s.postTags),
+        postCategories:     copyLinks(s.postCategories),
+        revisions:          copyTable(s.revisions),
+        attachments:        copyTable(s.attachments),
+        loginAttempts:      copyTable(s.loginAttempts),
+        loginLockouts:      copyTable(s.loginLockouts),
+        userTokens:         copyTable(s.userTokens),
+        userTOTPs:          copyTable(s.userTOTPs),
+        recoveryCodes:      copyTable(s.recoveryCodes),
+        userIdentities:     copyTable(s.userIdentities),
+        oidcStates:         copyTable(s.oidcStates),
+        apiTokens:          copyTable(s.apiTokens),
+        reactions:          copyTable(s.reactions),
+        bookmarks:          copyTable(s.bookmarks),
+        nextUserID:         s.nextUserID,
+        nextPostID:         s.nextPostID,
+        nextCommentID:      s.nextCommentID,
+        nextTagID:          s.nextTagID,
+        nextCategoryID:     s.nextCategoryID,
+        nextRevisionID:     s.nextRevisionID,
+        nextAttachmentID:   s.nextAttachmentID,
+        nextLoginAttemptID: s.nextLoginAttemptID,
+        nextLoginLockoutID: s.nextLoginLockoutID,
+        nextUserTokenID:    s.nextUserTokenID,
+        nextUserTOTPID:     s.nextUserTOTPID,
+        nextRecoveryCodeID: s.nextRecoveryCodeID,
+        nextUserIdentityID: s.nextUserIdentityID,
+        nextOIDCStateID:    s.nextOIDCStateID,
+        nextAPITokenID:     s.nextAPITokenID,
+        nextReactionID:     s.nextReactionID,
+        nextBookmarkID:     s.nextBookmarkID,
+    }
+}
+
+// restore 用快照覆盖当前数据
+func (s *Store) restore(snapshot *Store) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    s.users = snapshot.users
+    s.posts = snapshot.posts
+    s.comments = snapshot.comments
+    s.deletedUsers = snapshot.deletedUsers
+    s.deletedPosts = snapshot.deletedPosts
+    s.deletedComments = snapshot.deletedComments
+    s.tags = snapshot.tags
+    s.categories = snapshot.categories
+    s.postTags = snapshot.postTags
+    s.postCategories = snapshot.postCategories
+    s.revisions = snapshot.revisions
+    s.attachments = snapshot.attachments
+    s.loginAttempts = snapshot.loginAttempts
+    s.loginLockouts = snapshot.loginLockouts
+    s.userTokens = snapshot.userTokens
+    s.userTOTPs = snapshot.userTOTPs
+    s.recoveryCodes = snapshot.recoveryCodes
+    s.userIdentities = snapshot.userIdentities
+    s.oidcStates = snapshot.oidcStates
+    s.apiTokens = snapshot.apiTokens
+    s.reactions = snapshot.reactions
+    s.bookmarks = snapshot.bookmarks
+    s.nextUserID = snapshot.nextUserID
+    s.nextPostID = snapshot.nextPostID
+    s.nextCommentID = snapshot.nextCommentID
+    s.nextTagID = snapshot.nextTagID
+    s.nextCategoryID = snapshot.nextCategoryID
+    s.nextRevisionID = snapshot.nextRevisionID
+    s.nextAttachmentID = snapshot.nextAttachmentID
+    s.nextLoginAttemptID = snapshot.nextLoginAttemptID
+    s.nextLoginLockoutID = snapshot.nextLoginLockoutID
+    s.nextUserTokenID = snapshot.nextUserTokenID
+    s.nextUserTOTPID = snapshot.nextUserTOTPID
+    s.nextRecoveryCodeID = snapshot.nextRecoveryCodeID
+    s.nextUserIdentityID = snapshot.nextUserIdentityID
+    s.nextOIDCStateID = snapshot.nextOIDCStateID
+    s.nextAPITokenID = snapshot.nextAPITokenID
+    s.nextReactionID = snapshot.nextReactionID
+    s.nextBookmarkID = snapshot.nextBookmarkID
+}
+
+// copyTable 复制数据表，记录按值复制，避免原地修改影响快照
+func copyTable[T any](table map[uint]*T) map[uint]*T {
+    result := make(map[uint]*T, len(table))
+    for id, item := range table {
+        v := *item
+        result[id] = &v
+    }
+    return result
+}
+
+// copyLinks 复制文章与标签、分类的关联
+func copyLinks(links map[uint][]uint) map[uint][]uint {
+    result := make(map[uint][]uint, len(links))
+    for id, ids := range links {
+        result[id] = append([]uint(nil), ids...)
+    }
+    return result
+}
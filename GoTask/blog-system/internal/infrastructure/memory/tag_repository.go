@@ -0,0 +1,155 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+)
+
+// tagRepository 标签内存仓储实现
+type tagRepository struct {
+    store *Store
+}
+
+// NewTagRepository 创建标签内存仓储
+func NewTagRepository(store *Store) repository.TagRepository {
+    return &tagRepository{store: store}
+}
+
+// Create 创建标签
+func (r *tagRepository) Create(tag *model.Tag) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for _, existing := range r.store.tags {
+        if existing.Name == tag.Name {
+            return errors.New("标签已存在")
+        }
+    }
+
+    r.store.nextTagID++
+    tag.ID = r.store.nextTagID
+    tag.CreatedAt = time.Now()
+
+    t := *tag
+    r.store.tags[t.ID] = &t
+    return nil
+}
+
+// GetByID 根据ID获取标签
+func (r *tagRepository) GetByID(id uint) (*model.Tag, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    tag, ok := r.store.tags[id]
+    if !ok {
+        return nil, errors.New("标签不存在")
+    }
+    t := *tag
+    return &t, nil
+}
+
+// GetByName 根据名称获取标签
+func (r *tagRepository) GetByName(name string) (*model.Tag, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    for _, tag := range r.store.tags {
+        if tag.Name == name {
+            t := *tag
+            return &t, nil
+        }
+    }
+    return nil, errors.New("标签不存在")
+}
+
+// GetAll 获取所有标签
+func (r *tagRepository) GetAll() ([]*model.Tag, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    tags := make([]*model.Tag, 0, len(r.store.tags))
+    for _, tag := range r.store.tags {
+        t := *tag
+        tags = append(tags, &t)
+    }
+    sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+    return tags, nil
+}
+
+// Update 更新标签
+func (r *tagRepository) Update(tag *model.Tag) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if _, ok := r.store.tags[tag.ID]; !ok {
+        return errors.New("标签不存在")
+    }
+    for _, existing := range r.store.tags {
+        if existing.ID != tag.ID && existing.Name == tag.Name {
+            return errors.New("标签已存在")
+        }
+    }
+
+    t := *tag
+    r.store.tags[t.ID] = &t
+    return nil
+}
+
+// Delete 删除标签及其与文章的关联
+func (r *tagRepository) Delete(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for postID, tagIDs := range r.store.postTags {
+        r.store.postTags[postID] = removeID(tagIDs, id)
+    }
+    delete(r.store.tags, id)
+    return nil
+}
+
+// SetPostTags 替换文章的全部标签
+func (r *tagRepository) SetPostTags(postID uint, tagIDs []uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.postTags[postID] = append([]uint(nil), tagIDs...)
+    return nil
+}
+
+// Cloud 获取标签云（按公开文章数降序）
+func (r *tagRepository) Cloud(limit int) ([]*model.TagCount, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    counts := make(map[uint]int64)
+    for postID, tagIDs := range r.store.postTags {
+        post, ok := r.store.posts[postID]
+        if !ok || !post.IsPublic() {
+            continue
+        }
+        for _, tagID := range tagIDs {
+            counts[tagID]++
+        }
+    }
+
+    result := make([]*model.TagCount, 0, len(counts))
+    for tagID, count := range counts {
+        if tag, ok := r.store.tags[tagID]; ok {
+            result = append(result, &model.TagCount{ID: tag.ID, Name: tag.Name, PostCount: count})
+        }
+    }
+    sort.Slice(result, func(i, j int) bool {
+        if result[i].PostCount != result[j].PostCount {
+            return result[i].PostCount > result[j].PostCount
+        }
+        return result[i].Name < result[j].Name
+    })
+
+    if limit > 0 && len(result) > limit {
+        result = result[:limit]
+    }
+    return result, nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+)
+
+// transactor 内存事务执行器：执行前保存数据快照，失败时整体回滚
+// 事务之间互斥执行，但不隔离事务外的并发写入，仅适用于本地开发和测试
+type transactor struct {
+    store *Store
+}
+
+// NewTransactor 创建内存事务执行器
+func NewTransactor(store *Store) repository.Transactor {
+    return &transactor{store: store}
+}
+
+// WithinTransaction 在事务中执行fn，fn返回错误时恢复执行前的数据
+func (t *transactor) WithinTransaction(fn func(repos repository.TxRepositories) error) error {
+    t.store.txMu.Lock()
+    defer t.store.txMu.Unlock()
+
+    snapshot := t.store.snapshot()
+    err := fn(repository.TxRepositories{
+        Users:      NewUserRepository(t.store),
+        Posts:      NewPostRepository(t.store),
+        Comments:   NewCommentRepository(t.store),
+        Tags:       NewTagRepository(t.store),
+        Categories: NewCategoryRepository(t.store),
+        Revisions:  NewPostRevisionRepository(t.store),
+        Identities: NewUserIdentityRepository(t.store),
+        APITokens:  NewAPITokenRepository(t.store),
+        Reactions:  NewReactionRepository(t.store),
+        Bookmarks:  NewBookmarkRepository(t.store),
+    })
+    if err != nil {
+        t.store.restore(snapshot)
+    }
+    return err
+}
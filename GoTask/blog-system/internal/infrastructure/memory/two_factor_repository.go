@@ -0,0 +1,152 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "time"
+)
+
+// userTOTPRepository TOTP设置内存仓储实现
+type userTOTPRepository struct {
+    store *Store
+}
+
+// NewUserTOTPRepository 创建TOTP设置内存仓储
+func NewUserTOTPRepository(store *Store) repository.UserTOTPRepository {
+    return &userTOTPRepository{store: store}
+}
+
+// GetByUser 获取用户的TOTP设置
+func (r *userTOTPRepository) GetByUser(userID uint) (*model.UserTOTP, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    for _, totp := range r.store.userTOTPs {
+        if totp.UserID == userID {
+            t := *totp
+            return &t, nil
+        }
+    }
+    return nil, nil
+}
+
+// Save 保存TOTP设置
+func (r *userTOTPRepository) Save(totp *model.UserTOTP) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    now := time.Now()
+    if totp.ID == 0 {
+        r.store.nextUserTOTPID++
+        totp.ID = r.store.nextUserTOTPID
+        totp.CreatedAt = now
+    }
+    totp.UpdatedAt = now
+
+    t := *totp
+    r.store.userTOTPs[t.ID] = &t
+    return nil
+}
+
+// UseStep 记录已使用的时间步
+func (r *userTOTPRepository) UseStep(userID uint, step int64) (bool, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for _, totp := range r.store.userTOTPs {
+        if totp.UserID == userID && totp.LastStep < step {
+            totp.LastStep = step
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+// DeleteByUser 删除用户的TOTP设置
+func (r *userTOTPRepository) DeleteByUser(userID uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for id, totp := range r.store.userTOTPs {
+        if totp.UserID == userID {
+            delete(r.store.userTOTPs, id)
+        }
+    }
+    return nil
+}
+
+// recoveryCodeRepository 恢复码内存仓储实现
+type recoveryCodeRepository struct {
+    store *Store
+}
+
+// NewRecoveryCodeRepository 创建恢复码内存仓储
+func NewRecoveryCodeRepository(store *Store) repository.RecoveryCodeRepository {
+    return &recoveryCodeRepository{store: store}
+}
+
+// Replace 删除旧恢复码并保存新恢复码
+func (r *recoveryCodeRepository) Replace(userID uint, codeHashes []string) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for id, code := range r.store.recoveryCodes {
+        if code.UserID == userID {
+            delete(r.store.recoveryCodes, id)
+        }
+    }
+
+    now := time.Now()
+    for _, hash := range codeHashes {
+        r.store.nextRecoveryCodeID++
+        r.store.recoveryCodes[r.store.nextRecoveryCodeID] = &model.RecoveryCode{
+            ID:        r.store.nextRecoveryCodeID,
+            UserID:    userID,
+            CodeHash:  hash,
+            CreatedAt: now,
+        }
+    }
+    return nil
+}
+
+// Consume 将未使用的恢复码标记为已使用
+func (r *recoveryCodeRepository) Consume(userID uint, codeHash string, now time.Time) (bool, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for _, code := range r.store.recoveryCodes {
+        if code.UserID == userID && code.CodeHash == codeHash && code.UsedAt == nil {
+            usedAt := now
+            code.UsedAt = &usedAt
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+// CountUnused 统计未使用的恢复码
+func (r *recoveryCodeRepository) CountUnused(userID uint) (int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    var count int64
+    for _, code := range r.store.recoveryCodes {
+        if code.UserID == userID && code.UsedAt == nil {
+            count++
+        }
+    }
+    return count, nil
+}
+
+// DeleteByUser 删除用户的全部恢复码
+func (r *recoveryCodeRepository) DeleteByUser(userID uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for id, code := range r.store.recoveryCodes {
+        if code.UserID == userID {
+            delete(r.store.recoveryCodes, id)
+        }
+    }
+    return nil
+}
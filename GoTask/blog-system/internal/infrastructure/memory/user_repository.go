@@ -0,0 +1,213 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "sort"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// userRepository 用户内存仓储实现
+type userRepository struct {
+    store *Store
+}
+
+// NewUserRepository 创建用户内存仓储
+func NewUserRepository(store *Store) repository.UserRepository {
+    return &userRepository{store: store}
+}
+
+// Create 创建用户
+func (r *userRepository) Create(user *model.User) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if err := r.checkUnique(user); err != nil {
+        return err
+    }
+
+    r.store.nextUserID++
+    now := time.Now()
+    user.ID = r.store.nextUserID
+    user.CreatedAt = now
+    user.UpdatedAt = now
+    if user.Role == "" {
+        user.Role = model.RoleUser
+    }
+
+    u := *user
+    r.store.users[u.ID] = &u
+    return nil
+}
+
+// GetByID 根据ID获取用户
+func (r *userRepository) GetByID(id uint) (*model.User, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    user, ok := r.store.users[id]
+    if !ok {
+        return nil, errors.New("用户不存在")
+    }
+    u := *user
+    return &u, nil
+}
+
+// GetByUsername 根据用户名获取用户
+func (r *userRepository) GetByUsername(username string) (*model.User, error) {
+    return r.find(func(u *model.User) bool { return u.Username == username })
+}
+
+// GetByEmail 根据邮箱获取用户
+func (r *userRepository) GetByEmail(email string) (*model.User, error) {
+    return r.find(func(u *model.User) bool { return u.Email == email })
+}
+
+// List 获取用户列表（分页）
+func (r *userRepository) List(page, limit int) ([]*model.User, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    users := make([]*model.User, 0, len(r.store.users))
+    for _, user := range r.store.users {
+        u := *user
+        users = append(users, &u)
+    }
+    sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+    return paginate(users, page, limit), int64(len(users)), nil
+}
+
+// Update 更新用户信息
+func (r *userRepository) Update(user *model.User) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if _, ok := r.store.users[user.ID]; !ok {
+        return errors.New("用户不存在")
+    }
+    if err := r.checkUnique(user); err != nil {
+        return err
+    }
+
+    user.UpdatedAt = time.Now()
+    u := *user
+    r.store.users[u.ID] = &u
+    return nil
+}
+
+// Delete 删除用户（软删除，移入回收站）
+func (r *userRepository) Delete(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if user, ok := r.store.users[id]; ok {
+        user.DeletedAt = softDeletedAt()
+        r.store.deletedUsers[id] = user
+        delete(r.store.users, id)
+    }
+    return nil
+}
+
+// GetDeleted 获取回收站中的用户（分页）
+func (r *userRepository) GetDeleted(page, limit int) ([]*model.User, int64, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    users := make([]*model.User, 0, len(r.store.deletedUsers))
+    for _, user := range r.store.deletedUsers {
+        u := *user
+        users = append(users, &u)
+    }
+    sort.Slice(users, func(i, j int) bool { return users[i].DeletedAt.Time.After(users[j].DeletedAt.Time) })
+
+    return paginate(users, page, limit), int64(len(users)), nil
+}
+
+// Restore 从回收站恢复用户
+func (r *userRepository) Restore(id uint) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    user, ok := r.store.deletedUsers[id]
+    if !ok {
+        return errors.New("用户不在回收站中")
+    }
+    user.DeletedAt = gorm.DeletedAt{}
+    r.store.users[id] = user
+    delete(r.store.deletedUsers, id)
+    return nil
+}
+
+// Purge 永久删除before之前进入回收站、且名下已没有文章和评论的用户
+func (r *userRepository) Purge(before time.Time) (int64, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    owners := make(map[uint]bool)
+    for _, post := range r.store.posts {
+        owners[post.UserID] = true
+    }
+    for _, post := range r.store.deletedPosts {
+        owners[post.UserID] = true
+    }
+    for _, comment := range r.store.comments {
+        owners[comment.UserID] = true
+    }
+    for _, comment := range r.store.deletedComments {
+        owners[comment.UserID] = true
+    }
+    for _, attachment := range r.store.attachments {
+        owners[attachment.UserID] = true
+    }
+
+    var count int64
+    for id, user := range r.store.deletedUsers {
+        if expired(user.DeletedAt, before) && !owners[id] {
+            delete(r.store.deletedUsers, id)
+            count++
+        }
+    }
+    return count, nil
+}
+
+// find 查找第一个满足条件的用户
+func (r *userRepository) find(match func(u *model.User) bool) (*model.User, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    for _, user := range r.store.users {
+        if match(user) {
+            u := *user
+            return &u, nil
+        }
+    }
+    return nil, errors.New("用户不存在")
+}
+
+// checkUnique 模拟数据库的唯一约束，回收站中的用户同样占用用户名和邮箱（调用方需持有写锁）
+func (r *userRepository) checkUnique(user *model.User) error {
+    if err := checkUniqueIn(r.store.users, user); err != nil {
+        return err
+    }
+    return checkUniqueIn(r.store.deletedUsers, user)
+}
+
+// checkUniqueIn 检查用户名和邮箱在指定用户表中是否已被占用
+func checkUniqueIn(users map[uint]*model.User, user *model.User) error {
+    for _, existing := range users {
+        if existing.ID == user.ID {
+            continue
+        }
+        if existing.Username == user.Username {
+            return errors.New("用户名已存在")
+        }
+        if existing.Email == user.Email {
+            return errors.New("邮箱已存在")
+        }
+    }
+    return nil
+}
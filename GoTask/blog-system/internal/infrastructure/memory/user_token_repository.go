@@ -0,0 +1,76 @@
+package memory
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "time"
+)
+
+// userTokenRepository 一次性令牌内存仓储实现
+type userTokenRepository struct {
+    store *Store
+}
+
+// NewUserTokenRepository 创建一次性令牌内存仓储
+func NewUserTokenRepository(store *Store) repository.UserTokenRepository {
+    return &userTokenRepository{store: store}
+}
+
+// Create 保存令牌
+func (r *userTokenRepository) Create(token *model.UserToken) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    r.store.nextUserTokenID++
+    token.ID = r.store.nextUserTokenID
+    token.CreatedAt = time.Now()
+
+    t := *token
+    r.store.userTokens[t.ID] = &t
+    return nil
+}
+
+// Consume 标记令牌为已使用
+func (r *userTokenRepository) Consume(purpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for _, token := range r.store.userTokens {
+        if token.Purpose == purpose && token.TokenHash == tokenHash && token.UsedAt == nil && token.ExpiresAt.After(now) {
+            usedAt := now
+            token.UsedAt = &usedAt
+            t := *token
+            return &t, nil
+        }
+    }
+    return nil, errors.New("链接无效或已过期")
+}
+
+// DeleteByUser 删除用户指定用途的全部令牌
+func (r *userTokenRepository) DeleteByUser(userID uint, purpose string) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    for id, token := range r.store.userTokens {
+        if token.UserID == userID && token.Purpose == purpose {
+            delete(r.store.userTokens, id)
+        }
+    }
+    return nil
+}
+
+// DeleteExpired 删除before之前过期或已使用的令牌
+func (r *userTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    var count int64
+    for id, token := range r.store.userTokens {
+        if token.ExpiresAt.Before(before) || (token.UsedAt != nil && token.UsedAt.Before(before)) {
+            delete(r.store.userTokens, id)
+            count++
+        }
+    }
+    return count, nil
+}
@@ -0,0 +1,181 @@
+package oidc
+
+import (
+    "context"
+    "crypto"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+    "crypto/rsa"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "math/big"
+    "net/http"
+    "sync"
+    "time"
+)
+
+// jwksRefreshInterval 遇到未知kid时重新获取JWKS的最短间隔，避免伪造的kid导致频繁请求身份提供方
+const jwksRefreshInterval = time.Minute
+
+// jsonWebKey JWKS中的一个公钥
+type jsonWebKey struct {
+    Kty string `json:"kty"`
+    Kid string `json:"kid"`
+    Use string `json:"use"`
+    Crv string `json:"crv"`
+    N   string `json:"n"`
+    E   string `json:"e"`
+    X   string `json:"x"`
+    Y   string `json:"y"`
+}
+
+// keySet 缓存身份提供方的签名公钥，身份提供方轮换密钥后按需重新获取
+type keySet struct {
+    client *http.Client
+
+    mu        sync.Mutex
+    keys      map[string]crypto.PublicKey
+    fetchedAt time.Time
+}
+
+func newKeySet(client *http.Client) *keySet {
+    return &keySet{client: client}
+}
+
+// get 按kid查找公钥，kid为空时要求JWKS中只有一个公钥
+func (s *keySet) get(ctx context.Context, jwksURI, kid string) (crypto.PublicKey, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if key, ok := s.lookup(kid); ok {
+        return key, nil
+    }
+    if s.keys != nil && time.Since(s.fetchedAt) < jwksRefreshInterval {
+        return nil, fmt.Errorf("未知的签名密钥: %s", kid)
+    }
+
+    keys, err := s.fetch(ctx, jwksURI)
+    if err != nil {
+        return nil, err
+    }
+    s.keys = keys
+    s.fetchedAt = time.Now()
+
+    if key, ok := s.lookup(kid); ok {
+        return key, nil
+    }
+    return nil, fmt.Errorf("未知的签名密钥: %s", kid)
+}
+
+// lookup 在缓存中查找公钥（调用方需持有锁）
+func (s *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+    if kid == "" && len(s.keys) == 1 {
+        for _, key := range s.keys {
+            return key, true
+        }
+    }
+    key, ok := s.keys[kid]
+    return key, ok && kid != ""
+}
+
+// fetch 获取JWKS，跳过不支持的密钥类型和非签名用途的密钥
+func (s *keySet) fetch(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+    if err != nil {
+        return nil, err
+    }
+    resp, err := s.client.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("获取JWKS失败: %w", err)
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("获取JWKS失败: HTTP %d", resp.StatusCode)
+    }
+
+    var set struct {
+        Keys []jsonWebKey `json:"keys"`
+    }
+    if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&set); err != nil {
+        return nil, fmt.Errorf("JWKS格式无效: %w", err)
+    }
+
+    keys := make(map[string]crypto.PublicKey, len(set.Keys))
+    for _, jwk := range set.Keys {
+        if jwk.Use != "" && jwk.Use != "sig" {
+            continue
+        }
+        key, err := jwk.publicKey()
+        if err != nil {
+            continue
+        }
+        keys[jwk.Kid] = key
+    }
+    if len(keys) == 0 {
+        return nil, errors.New("JWKS中没有可用的签名公钥")
+    }
+    return keys, nil
+}
+
+// publicKey 将JWK转换为公钥，支持RSA、EC（P-256/384/521）和Ed25519
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+    switch k.Kty {
+    case "RSA":
+        n, err := decodeBigInt(k.N)
+        if err != nil {
+            return nil, err
+        }
+        e, err := decodeBigInt(k.E)
+        if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+            return nil, errors.New("无效的RSA指数")
+        }
+        return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+    case "EC":
+        var curve elliptic.Curve
+        switch k.Crv {
+        case "P-256":
+            curve = elliptic.P256()
+        case "P-384":
+            curve = elliptic.P384()
+        case "P-521":
+            curve = elliptic.P521()
+        default:
+            return nil, fmt.Errorf("不支持的曲线: %s", k.Crv)
+        }
+        x, err := decodeBigInt(k.X)
+        if err != nil {
+            return nil, err
+        }
+        y, err := decodeBigInt(k.Y)
+        if err != nil {
+            return nil, err
+        }
+        if !curve.IsOnCurve(x, y) {
+            return nil, errors.New("EC公钥不在曲线上")
+        }
+        return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+    case "OKP":
+        if k.Crv != "Ed25519" {
+            return nil, fmt.Errorf("不支持的曲线: %s", k.Crv)
+        }
+        x, err := base64.RawURLEncoding.DecodeString(k.X)
+        if err != nil || len(x) != ed25519.PublicKeySize {
+            return nil, errors.New("无效的Ed25519公钥")
+        }
+        return ed25519.PublicKey(x), nil
+    }
+    return nil, fmt.Errorf("不支持的密钥类型: %s", k.Kty)
+}
+
+// decodeBigInt 解码base64url编码的大整数
+func decodeBigInt(value string) (*big.Int, error) {
+    b, err := base64.RawURLEncoding.DecodeString(value)
+    if err != nil || len(b) == 0 {
+        return nil, errors.New("无效的JWK参数")
+    }
+    return new(big.Int).SetBytes(b), nil
+}
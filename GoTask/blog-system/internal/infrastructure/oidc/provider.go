@@ -0,0 +1,287 @@
+package oidc
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/golang-jwt/jwt/v4"
+)
+
+// maxResponseSize 身份提供方响应体大小上限
+const maxResponseSize = 1 << 20
+
+// clockSkew 校验ID令牌时间时允许的时钟误差
+const clockSkew = time.Minute
+
+// Config OpenID Connect身份提供方的连接参数
+type Config struct {
+    Issuer       string   // 身份提供方地址，从 Issuer/.well-known/openid-configuration 获取其他地址
+    ClientID     string
+    ClientSecret string   // 为空时作为公开客户端，只依靠PKCE
+    RedirectURL  string   // 授权完成后的回调地址，需要在身份提供方登记
+    Scopes       []string // 必须包含openid
+}
+
+// Identity 从ID令牌中取得的用户身份
+type Identity struct {
+    Issuer            string
+    Subject           string
+    Email             string
+    EmailVerified     bool
+    Name              string
+    PreferredUsername string
+}
+
+// Provider OpenID Connect授权码流程（PKCE）
+type Provider interface {
+    // AuthCodeURL 生成授权地址，codeChallenge为CodeChallenge(verifier)
+    AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+    // Exchange 使用授权码换取ID令牌，校验签名、issuer、audience、有效期和nonce后返回身份
+    Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error)
+}
+
+// discovery 身份提供方元数据中用到的字段
+type discovery struct {
+    Issuer                string `json:"issuer"`
+    AuthorizationEndpoint string `json:"authorization_endpoint"`
+    TokenEndpoint         string `json:"token_endpoint"`
+    JWKSURI               string `json:"jwks_uri"`
+}
+
+// idTokenClaims ID令牌的声明
+type idTokenClaims struct {
+    Nonce             string `json:"nonce"`
+    Email             string `json:"email"`
+    EmailVerified     any    `json:"email_verified"` // 部分身份提供方返回字符串"true"
+    Name              string `json:"name"`
+    PreferredUsername string `json:"preferred_username"`
+    AuthorizedParty   string `json:"azp"`
+    jwt.RegisteredClaims
+}
+
+type provider struct {
+    cfg    Config
+    client *http.Client
+
+    mu       sync.Mutex
+    metadata *discovery // 首次使用时获取，身份提供方暂时不可用不影响服务启动
+    keys     *keySet
+}
+
+// NewProvider 创建OpenID Connect身份提供方客户端
+func NewProvider(cfg Config) (Provider, error) {
+    issuer, err := url.Parse(cfg.Issuer)
+    if err != nil || issuer.Host == "" || (issuer.Scheme != "http" && issuer.Scheme != "https") {
+        return nil, fmt.Errorf("无效的OIDC issuer: %s", cfg.Issuer)
+    }
+    if cfg.ClientID == "" || cfg.RedirectURL == "" {
+        return nil, errors.New("OIDC需要配置client_id和回调地址")
+    }
+    hasOpenID := false
+    for _, scope := range cfg.Scopes {
+        hasOpenID = hasOpenID || scope == "openid"
+    }
+    if !hasOpenID {
+        cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+    }
+
+    client := &http.Client{Timeout: 10 * time.Second}
+    return &provider{cfg: cfg, client: client, keys: newKeySet(client)}, nil
+}
+
+// AuthCodeURL 生成授权地址
+func (p *provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+    metadata, err := p.discover(ctx)
+    if err != nil {
+        return "", err
+    }
+
+    query := url.Values{
+        "response_type":         {"code"},
+        "client_id":             {p.cfg.ClientID},
+        "redirect_uri":          {p.cfg.RedirectURL},
+        "scope":                 {strings.Join(p.cfg.Scopes, " ")},
+        "state":                 {state},
+        "nonce":                 {nonce},
+        "code_challenge":        {codeChallenge},
+        "code_challenge_method": {"S256"},
+    }
+    separator := "?"
+    if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+        separator = "&"
+    }
+    return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange 使用授权码换取并校验ID令牌
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+    metadata, err := p.discover(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    form := url.Values{
+        "grant_type":    {"authorization_code"},
+        "code":          {code},
+        "redirect_uri":  {p.cfg.RedirectURL},
+        "code_verifier": {codeVerifier},
+    }
+    if p.cfg.ClientSecret == "" {
+        form.Set("client_id", p.cfg.ClientID)
+    }
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+    if err != nil {
+        return nil, err
+    }
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    req.Header.Set("Accept", "application/json")
+    if p.cfg.ClientSecret != "" {
+        // client_secret_basic要求先对客户端ID和密钥做表单编码
+        req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+    }
+
+    var token struct {
+        IDToken          string `json:"id_token"`
+        Error            string `json:"error"`
+        ErrorDescription string `json:"error_description"`
+    }
+    status, err := p.doJSON(req, &token)
+    if err != nil {
+        return nil, err
+    }
+    if status != http.StatusOK || token.Error != "" {
+        return nil, fmt.Errorf("换取令牌失败: %s %s", token.Error, token.ErrorDescription)
+    }
+    if token.IDToken == "" {
+        return nil, errors.New("身份提供方没有返回ID令牌")
+    }
+
+    return p.verifyIDToken(ctx, metadata, token.IDToken, nonce)
+}
+
+// verifyIDToken 校验ID令牌的签名和声明
+func (p *provider) verifyIDToken(ctx context.Context, metadata *discovery, rawToken, nonce string) (*Identity, error) {
+    parser := jwt.NewParser(jwt.WithValidMethods([]string{
+        "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA",
+    }), jwt.WithoutClaimsValidation())
+
+    var claims idTokenClaims
+    _, err := parser.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (interface{}, error) {
+        kid, _ := token.Header["kid"].(string)
+        return p.keys.get(ctx, metadata.JWKSURI, kid)
+    })
+    if err != nil {
+        return nil, fmt.Errorf("ID令牌无效: %w", err)
+    }
+
+    now := time.Now()
+    switch {
+    case claims.Issuer != metadata.Issuer:
+        return nil, errors.New("ID令牌的issuer不匹配")
+    case !claims.VerifyAudience(p.cfg.ClientID, true):
+        return nil, errors.New("ID令牌的audience不匹配")
+    case len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID:
+        return nil, errors.New("ID令牌的azp不匹配")
+    case claims.ExpiresAt == nil || now.After(claims.ExpiresAt.Add(clockSkew)):
+        return nil, errors.New("ID令牌已过期")
+    case claims.IssuedAt != nil && claims.IssuedAt.After(now.Add(clockSkew)):
+        return nil, errors.New("ID令牌的签发时间无效")
+    case claims.Nonce == "" || claims.Nonce != nonce:
+        return nil, errors.New("ID令牌的nonce不匹配")
+    case claims.Subject == "":
+        return nil, errors.New("ID令牌缺少sub")
+    }
+
+    verified := false
+    switch v := claims.EmailVerified.(type) {
+    case bool:
+        verified = v
+    case string:
+        verified = v == "true"
+    }
+    return &Identity{
+        Issuer:            claims.Issuer,
+        Subject:           claims.Subject,
+        Email:             strings.TrimSpace(claims.Email),
+        EmailVerified:     verified,
+        Name:              claims.Name,
+        PreferredUsername: claims.PreferredUsername,
+    }, nil
+}
+
+// discover 获取并缓存身份提供方元数据，失败时下次请求重试
+func (p *provider) discover(ctx context.Context) (*discovery, error) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if p.metadata != nil {
+        return p.metadata, nil
+    }
+
+    endpoint := strings.TrimRight(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+    if err != nil {
+        return nil, err
+    }
+    var metadata discovery
+    status, err := p.doJSON(req, &metadata)
+    if err != nil {
+        return nil, err
+    }
+    if status != http.StatusOK {
+        return nil, fmt.Errorf("获取OIDC元数据失败: HTTP %d", status)
+    }
+
+    // 元数据中的issuer必须与配置一致，防止被替换为其他身份提供方
+    if metadata.Issuer != p.cfg.Issuer {
+        return nil, fmt.Errorf("OIDC元数据的issuer %s 与配置 %s 不一致", metadata.Issuer, p.cfg.Issuer)
+    }
+    if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+        return nil, errors.New("OIDC元数据缺少授权、令牌或JWKS地址")
+    }
+    p.metadata = &metadata
+    return p.metadata, nil
+}
+
+// doJSON 发送请求并解析JSON响应，返回HTTP状态码
+func (p *provider) doJSON(req *http.Request, v any) (int, error) {
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return 0, fmt.Errorf("请求身份提供方失败: %w", err)
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+    if err != nil {
+        return 0, err
+    }
+    if err := json.Unmarshal(body, v); err != nil {
+        return resp.StatusCode, fmt.Errorf("身份提供方返回了无效的响应: HTTP %d", resp.StatusCode)
+    }
+    return resp.StatusCode, nil
+}
+
+// NewCodeVerifier 生成PKCE的code_verifier（32字节随机数，43个字符）
+func NewCodeVerifier() (string, error) {
+    b := make([]byte, 32)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge 计算S256方式的code_challenge
+func CodeChallenge(verifier string) string {
+    sum := sha256.Sum256([]byte(verifier))
+    return base64.RawURLEncoding.EncodeToString(sum[:])
+}
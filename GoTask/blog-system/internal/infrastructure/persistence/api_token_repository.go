@@ -0,0 +1,69 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// apiTokenRepository 个人访问令牌仓储实现
+type apiTokenRepository struct {
+    db *gorm.DB
+}
+
+// NewAPITokenRepository 创建个人访问令牌仓储
+func NewAPITokenRepository(db *gorm.DB) repository.APITokenRepository {
+    return &apiTokenRepository{db: db}
+}
+
+// Create 保存令牌
+func (r *apiTokenRepository) Create(token *model.APIToken) error {
+    return r.db.Create(token).Error
+}
+
+// GetByHash 按令牌哈希查找
+func (r *apiTokenRepository) GetByHash(tokenHash string) (*model.APIToken, error) {
+    var token model.APIToken
+    if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+        return nil, err
+    }
+    return &token, nil
+}
+
+// ListByUser 获取用户的全部令牌
+func (r *apiTokenRepository) ListByUser(userID uint) ([]*model.APIToken, error) {
+    var tokens []*model.APIToken
+    err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&tokens).Error
+    return tokens, err
+}
+
+// Touch 更新最近使用时间
+func (r *apiTokenRepository) Touch(id uint, at time.Time) error {
+    return r.db.Model(&model.APIToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+// Delete 删除用户的令牌
+func (r *apiTokenRepository) Delete(userID, id uint) error {
+    result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.APIToken{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return errors.New("令牌不存在")
+    }
+    return nil
+}
+
+// DeleteByUser 删除用户的全部令牌
+func (r *apiTokenRepository) DeleteByUser(userID uint) error {
+    return r.db.Where("user_id = ?", userID).Delete(&model.APIToken{}).Error
+}
+
+// DeleteExpired 删除before之前过期的令牌
+func (r *apiTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+    result := r.db.Where("expires_at < ?", before).Delete(&model.APIToken{})
+    return result.RowsAffected, result.Error
+}
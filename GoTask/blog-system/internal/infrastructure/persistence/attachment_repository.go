@@ -0,0 +1,75 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// attachmentRepository 附件仓储实现
+type attachmentRepository struct {
+    db *gorm.DB
+}
+
+// NewAttachmentRepository 创建附件仓储
+func NewAttachmentRepository(db *gorm.DB) repository.AttachmentRepository {
+    return &attachmentRepository{db: db}
+}
+
+// Create 保存附件记录
+func (r *attachmentRepository) Create(attachment *model.Attachment) error {
+    return r.db.Create(attachment).Error
+}
+
+// GetByID 根据ID获取附件
+func (r *attachmentRepository) GetByID(id uint) (*model.Attachment, error) {
+    var attachment model.Attachment
+    if err := r.db.First(&attachment, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("附件不存在")
+        }
+        return nil, err
+    }
+    return &attachment, nil
+}
+
+// GetByUserID 获取用户上传的附件（分页，按上传时间倒序）
+func (r *attachmentRepository) GetByUserID(userID uint, page, limit int) ([]*model.Attachment, int64, error) {
+    var attachments []*model.Attachment
+    var total int64
+
+    offset := (page - 1) * limit
+
+    if err := r.db.Model(&model.Attachment{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := r.db.Where("user_id = ?", userID).Offset(offset).Limit(limit).Order("id desc").Find(&attachments).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return attachments, total, nil
+}
+
+// Delete 删除附件记录
+func (r *attachmentRepository) Delete(id uint) error {
+    return r.db.Delete(&model.Attachment{}, id).Error
+}
+
+// GetOrphaned 获取before之前被删除的用户留下的、未关联文章的附件
+func (r *attachmentRepository) GetOrphaned(before time.Time, limit int) ([]*model.Attachment, error) {
+    var attachments []*model.Attachment
+    err := r.db.
+        Where("post_id IS NULL").
+        Where("user_id IN (?)", r.db.Unscoped().Model(&model.User{}).Select("id").Where("deleted_at < ?", before)).
+        Order("id asc").
+        Limit(limit).
+        Find(&attachments).Error
+    if err != nil {
+        return nil, err
+    }
+    return attachments, nil
+}
@@ -0,0 +1,114 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+// bookmarkRepository 收藏仓储实现
+type bookmarkRepository struct {
+    db *gorm.DB
+}
+
+// NewBookmarkRepository 创建收藏仓储
+func NewBookmarkRepository(db *gorm.DB) repository.BookmarkRepository {
+    return &bookmarkRepository{db: db}
+}
+
+// Add 添加收藏，只有真正插入了记录才增加计数
+func (r *bookmarkRepository) Add(bookmark *model.Bookmark) (bool, error) {
+    var created bool
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(bookmark)
+        if result.Error != nil || result.RowsAffected == 0 {
+            return result.Error
+        }
+        created = true
+        return adjustBookmarkCount(tx, bookmark.PostID, 1)
+    })
+    return created, err
+}
+
+// Remove 删除收藏，只有真正删除了记录才减少计数
+func (r *bookmarkRepository) Remove(userID, postID uint) (bool, error) {
+    var removed bool
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&model.Bookmark{})
+        if result.Error != nil || result.RowsAffected == 0 {
+            return result.Error
+        }
+        removed = true
+        return adjustBookmarkCount(tx, postID, -1)
+    })
+    return removed, err
+}
+
+// Exists 用户是否收藏了文章
+func (r *bookmarkRepository) Exists(userID, postID uint) (bool, error) {
+    var count int64
+    err := r.db.Model(&model.Bookmark{}).Where("user_id = ? AND post_id = ?", userID, postID).Count(&count).Error
+    return count > 0, err
+}
+
+// ListPosts 获取用户收藏的公开文章（分页），按收藏时间倒序
+func (r *bookmarkRepository) ListPosts(userID uint, page, limit int) ([]*model.Post, int64, error) {
+    var posts []*model.Post
+    var total int64
+
+    offset := (page - 1) * limit
+    postRepo := &postRepository{db: r.db}
+    query := func(db *gorm.DB) *gorm.DB {
+        return postRepo.public(db.Model(&model.Post{}).
+            Joins("JOIN bookmarks ON bookmarks.post_id = posts.id").
+            Where("bookmarks.user_id = ?", userID))
+    }
+
+    if err := query(r.db).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    err := query(postRepo.withAssociations(r.db)).
+        Select("posts.*").
+        Order("bookmarks.created_at desc, bookmarks.id desc").
+        Offset(offset).Limit(limit).
+        Find(&posts).Error
+    if err != nil {
+        return nil, 0, err
+    }
+
+    return posts, total, nil
+}
+
+// ListByUser 获取用户的全部收藏
+func (r *bookmarkRepository) ListByUser(userID uint) ([]*model.Bookmark, error) {
+    var bookmarks []*model.Bookmark
+    err := r.db.Where("user_id = ?", userID).Order("id asc").Find(&bookmarks).Error
+    return bookmarks, err
+}
+
+// DeleteByUser 删除用户的全部收藏并减少对应文章的计数
+func (r *bookmarkRepository) DeleteByUser(userID uint) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        var postIDs []uint
+        if err := tx.Model(&model.Bookmark{}).Where("user_id = ?", userID).Pluck("post_id", &postIDs).Error; err != nil {
+            return err
+        }
+        if len(postIDs) == 0 {
+            return nil
+        }
+        if err := tx.Unscoped().Model(&model.Post{}).Where("id IN ?", postIDs).
+            UpdateColumn("bookmark_count", gorm.Expr("bookmark_count - 1")).Error; err != nil {
+            return err
+        }
+        return tx.Where("user_id = ?", userID).Delete(&model.Bookmark{}).Error
+    })
+}
+
+// adjustBookmarkCount 调整文章的收藏计数，不修改updated_at
+func adjustBookmarkCount(tx *gorm.DB, postID uint, delta int64) error {
+    return tx.Unscoped().Model(&model.Post{}).Where("id = ?", postID).
+        UpdateColumn("bookmark_count", gorm.Expr("bookmark_count + ?", delta)).Error
+}
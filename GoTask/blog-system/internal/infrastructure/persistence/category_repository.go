@@ -0,0 +1,94 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+
+    "gorm.io/gorm"
+)
+
+// categoryRepository 分类仓储实现
+type categoryRepository struct {
+    db *gorm.DB
+}
+
+// NewCategoryRepository 创建分类仓储
+func NewCategoryRepository(db *gorm.DB) repository.CategoryRepository {
+    return &categoryRepository{db: db}
+}
+
+// Create 创建分类
+func (r *categoryRepository) Create(category *model.Category) error {
+    return r.db.Create(category).Error
+}
+
+// GetByID 根据ID获取分类
+func (r *categoryRepository) GetByID(id uint) (*model.Category, error) {
+    var category model.Category
+    if err := r.db.First(&category, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("分类不存在")
+        }
+        return nil, err
+    }
+    return &category, nil
+}
+
+// GetByName 根据名称获取分类
+func (r *categoryRepository) GetByName(name string) (*model.Category, error) {
+    var category model.Category
+    if err := r.db.Where("name = ?", name).First(&category).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("分类不存在")
+        }
+        return nil, err
+    }
+    return &category, nil
+}
+
+// GetAllWithCount 获取所有分类及其公开文章数
+func (r *categoryRepository) GetAllWithCount() ([]*model.CategoryCount, error) {
+    counts := []*model.CategoryCount{}
+    err := r.db.Table("categories").
+        Select("categories.id, categories.name, categories.description, COUNT(posts.id) AS post_count").
+        Joins("LEFT JOIN post_categories ON post_categories.category_id = categories.id").
+        Joins("LEFT JOIN posts ON posts.id = post_categories.post_id AND posts.status = ? AND posts.hidden = ? AND posts.deleted_at IS NULL", model.PostStatusPublished, false).
+        Group("categories.id, categories.name, categories.description").
+        Order("categories.name asc").
+        Scan(&counts).Error
+    if err != nil {
+        return nil, err
+    }
+    return counts, nil
+}
+
+// Update 更新分类
+func (r *categoryRepository) Update(category *model.Category) error {
+    return r.db.Save(category).Error
+}
+
+// Delete 删除分类及其与文章的关联
+func (r *categoryRepository) Delete(id uint) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec("DELETE FROM post_categories WHERE category_id = ?", id).Error; err != nil {
+            return err
+        }
+        return tx.Delete(&model.Category{}, id).Error
+    })
+}
+
+// SetPostCategories 替换文章的全部分类
+func (r *categoryRepository) SetPostCategories(postID uint, categoryIDs []uint) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec("DELETE FROM post_categories WHERE post_id = ?", postID).Error; err != nil {
+            return err
+        }
+        for _, categoryID := range categoryIDs {
+            if err := tx.Exec("INSERT INTO post_categories (post_id, category_id) VALUES (?, ?)", postID, categoryID).Error; err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
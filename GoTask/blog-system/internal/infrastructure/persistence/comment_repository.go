@@ -4,8 +4,10 @@ import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
     "errors"
+    "time"
 
     "gorm.io/gorm"
+    "gorm.io/gorm/clause"
 )
 
 // commentRepository 评论仓储实现
@@ -35,7 +37,7 @@ func (r *commentRepository) GetByID(id uint) (*model.Comment, error) {
     return &comment, nil
 }
 
-// GetByPostID 获取指定文章的所有评论（分页）
+// GetByPostID 获取指定文章的所有评论（分页，不含已删除的占位评论）
 func (r *commentRepository) GetByPostID(postID uint, page, limit int) ([]*model.Comment, int64, error) {
     var comments []*model.Comment
     var total int64
@@ -43,19 +45,187 @@ func (r *commentRepository) GetByPostID(postID uint, page, limit int) ([]*model.
     offset := (page - 1) * limit
 
     // 获取总数
-    if err := r.db.Model(&model.Comment{}).Where("post_id = ?", postID).Count(&total).Error; err != nil {
+    if err := r.db.Model(&model.Comment{}).Where("post_id = ? AND deleted = ?", postID, false).Count(&total).Error; err != nil {
         return nil, 0, err
     }
 
     // 获取分页数据
-    if err := r.db.Preload("User").Where("post_id = ?", postID).Offset(offset).Limit(limit).Order("created_at desc").Find(&comments).Error; err != nil {
+    if err := r.db.Preload("User").Where("post_id = ? AND deleted = ?", postID, false).Offset(offset).Limit(limit).Order("created_at desc, id desc").Find(&comments).Error; err != nil {
         return nil, 0, err
     }
 
     return comments, total, nil
 }
 
-// Delete 删除评论
+// GetByPostIDAfter 按游标获取指定文章的评论
+func (r *commentRepository) GetByPostIDAfter(postID uint, after *repository.Cursor, limit int) ([]*model.Comment, error) {
+    var comments []*model.Comment
+    query := keyset(r.db.Preload("User").Where("comments.post_id = ? AND comments.deleted = ?", postID, false), "comments", after)
+    if err := query.Limit(limit).Find(&comments).Error; err != nil {
+        return nil, err
+    }
+    return comments, nil
+}
+
+// GetRootsByPostID 获取指定文章的顶层评论（分页）
+func (r *commentRepository) GetRootsByPostID(postID uint, page, limit int) ([]*model.Comment, int64, error) {
+    var comments []*model.Comment
+    var total int64
+
+    offset := (page - 1) * limit
+    query := r.db.Model(&model.Comment{}).Where("post_id = ? AND parent_id IS NULL", postID)
+
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := query.Preload("User").Offset(offset).Limit(limit).Order("created_at desc, id desc").Find(&comments).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return comments, total, nil
+}
+
+// GetByRootIDs 获取指定顶层评论下的所有回复，按时间正序
+func (r *commentRepository) GetByRootIDs(rootIDs []uint) ([]*model.Comment, error) {
+    var comments []*model.Comment
+    if len(rootIDs) == 0 {
+        return comments, nil
+    }
+
+    if err := r.db.Preload("User").Where("root_id IN ?", rootIDs).Order("created_at asc, id asc").Find(&comments).Error; err != nil {
+        return nil, err
+    }
+    return comments, nil
+}
+
+// CountReplies 统计评论的直接回复数
+func (r *commentRepository) CountReplies(id uint) (int64, error) {
+    var count int64
+    if err := r.db.Model(&model.Comment{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+        return 0, err
+    }
+    return count, nil
+}
+
+// Update 更新评论
+func (r *commentRepository) Update(comment *model.Comment) error {
+    return r.db.Omit(clause.Associations, "reaction_count").Save(comment).Error
+}
+
+// Delete 删除评论（软删除，移入回收站）
 func (r *commentRepository) Delete(id uint) error {
     return r.db.Delete(&model.Comment{}, id).Error
-}
\ No newline at end of file
+}
+
+// GetByIDWithDeleted 根据ID获取评论，包括回收站中的评论
+func (r *commentRepository) GetByIDWithDeleted(id uint) (*model.Comment, error) {
+    var comment model.Comment
+    if err := r.db.Unscoped().Preload("User").First(&comment, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("评论不存在")
+        }
+        return nil, err
+    }
+    return &comment, nil
+}
+
+// GetDeletedByUserID 获取用户已删除的评论（回收站中的评论和占位评论，分页）
+func (r *commentRepository) GetDeletedByUserID(userID uint, page, limit int) ([]*model.Comment, int64, error) {
+    var comments []*model.Comment
+    var total int64
+
+    offset := (page - 1) * limit
+    query := r.db.Unscoped().Model(&model.Comment{}).
+        Where("user_id = ? AND (deleted_at IS NOT NULL OR deleted = ?)", userID, true)
+
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := query.Offset(offset).Limit(limit).Order("created_at desc").Find(&comments).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return comments, total, nil
+}
+
+// Restore 从回收站恢复评论
+func (r *commentRepository) Restore(id uint) error {
+    result := r.db.Unscoped().Model(&model.Comment{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return errors.New("评论不在回收站中")
+    }
+    return nil
+}
+
+// Purge 永久删除before之前进入回收站的评论及其回应
+func (r *commentRepository) Purge(before time.Time) (int64, error) {
+    var count int64
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        ids := tx.Unscoped().Model(&model.Comment{}).Select("id").Where("deleted_at < ?", before)
+        if err := deleteReactions(tx, model.ReactionTargetComment, ids); err != nil {
+            return err
+        }
+        result := tx.Unscoped().Where("deleted_at < ?", before).Delete(&model.Comment{})
+        count = result.RowsAffected
+        return result.Error
+    })
+    return count, err
+}
+
+// GetByUserID 获取用户的所有评论，包括占位评论和回收站中的评论（分页）
+func (r *commentRepository) GetByUserID(userID uint, page, limit int) ([]*model.Comment, int64, error) {
+    var comments []*model.Comment
+    var total int64
+
+    offset := (page - 1) * limit
+    query := r.db.Unscoped().Model(&model.Comment{}).Where("user_id = ?", userID)
+
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := query.Offset(offset).Limit(limit).Order("id asc").Find(&comments).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return comments, total, nil
+}
+
+// ReassignUser 将用户的所有评论（包括回收站中的）转给另一个用户
+func (r *commentRepository) ReassignUser(fromID, toID uint) error {
+    return r.db.Unscoped().Model(&model.Comment{}).Where("user_id = ?", fromID).Update("user_id", toID).Error
+}
+
+// DeleteByUserID 删除用户的所有评论：仍有回复的评论保留为占位并转给placeholderID，其余移入回收站
+// 按层级从深到浅处理，用户自己的回复先被删除，不会让上级评论误留为占位
+func (r *commentRepository) DeleteByUserID(userID, placeholderID uint) ([]uint, error) {
+    var comments []*model.Comment
+    if err := r.db.Where("user_id = ?", userID).Order("depth desc, id desc").Find(&comments).Error; err != nil {
+        return nil, err
+    }
+
+    ids := make([]uint, 0, len(comments))
+    for _, comment := range comments {
+        replies, err := r.CountReplies(comment.ID)
+        if err != nil {
+            return nil, err
+        }
+
+        if replies > 0 {
+            err = r.db.Model(&model.Comment{}).Where("id = ?", comment.ID).
+                Updates(map[string]interface{}{"deleted": true, "user_id": placeholderID}).Error
+        } else {
+            err = r.db.Delete(&model.Comment{}, comment.ID).Error
+        }
+        if err != nil {
+            return nil, err
+        }
+        ids = append(ids, comment.ID)
+    }
+    return ids, nil
+}
@@ -0,0 +1,87 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/config"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "fmt"
+
+    "gorm.io/gorm"
+)
+
+// OpenDatabase 根据DB_DRIVER创建数据库连接，不做任何表结构处理
+func OpenDatabase(cfg *config.Config) (*gorm.DB, error) {
+    switch cfg.DBConfig.Driver {
+    case config.DriverMySQL:
+        return NewMySQLConnection(cfg)
+    case config.DriverSQLite:
+        return NewSQLiteConnection(cfg)
+    default:
+        return nil, fmt.Errorf("数据库驱动 %s 不使用GORM连接", cfg.DBConfig.Driver)
+    }
+}
+
+// NewDatabase 创建数据库连接，并按DB_MIGRATION_MODE准备表结构
+func NewDatabase(cfg *config.Config) (*gorm.DB, error) {
+    db, err := OpenDatabase(cfg)
+    if err != nil {
+        return nil, err
+    }
+
+    switch cfg.DBConfig.Migration {
+    case config.MigrationAutoMigrate:
+        logger.Warn("使用AutoMigrate同步表结构，仅用于开发环境")
+        if err := autoMigrate(db); err != nil {
+            return nil, fmt.Errorf("数据库迁移失败: %w", err)
+        }
+    case config.MigrationStartup:
+        migrator, err := NewMigrator(db)
+        if err != nil {
+            return nil, err
+        }
+        count, err := migrator.Up()
+        if err != nil {
+            return nil, err
+        }
+        logger.Info(fmt.Sprintf("已执行 %d 个数据库迁移", count))
+    default:
+        migrator, err := NewMigrator(db)
+        if err != nil {
+            return nil, err
+        }
+        pending, err := migrator.Pending()
+        if err != nil {
+            return nil, err
+        }
+        if pending > 0 {
+            logger.Warn(fmt.Sprintf("有 %d 个数据库迁移尚未执行，请运行 migrate up", pending))
+        }
+    }
+
+    return db, nil
+}
+
+// autoMigrate 使用GORM AutoMigrate同步表结构（开发模式）
+func autoMigrate(db *gorm.DB) error {
+    return db.AutoMigrate(
+        &model.User{},
+        &model.Post{},
+        &model.Comment{},
+        &model.Tag{},
+        &model.Category{},
+        &model.PostRevision{},
+        &model.Attachment{},
+        &model.LoginAttempt{},
+        &model.LoginLockout{},
+        &model.UserToken{},
+        &model.UserTOTP{},
+        &model.RecoveryCode{},
+        &model.UserIdentity{},
+        &model.OIDCState{},
+        &model.APIToken{},
+        &model.Reaction{},
+        &model.Bookmark{},
+        &model.RevokedToken{},
+        &model.UserTokenRevocation{},
+    )
+}
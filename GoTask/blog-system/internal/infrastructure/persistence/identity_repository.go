@@ -0,0 +1,105 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// userIdentityRepository 外部身份仓储实现
+type userIdentityRepository struct {
+    db *gorm.DB
+}
+
+// NewUserIdentityRepository 创建外部身份仓储
+func NewUserIdentityRepository(db *gorm.DB) repository.UserIdentityRepository {
+    return &userIdentityRepository{db: db}
+}
+
+// Create 保存外部身份，同一外部账号已绑定时返回错误
+func (r *userIdentityRepository) Create(identity *model.UserIdentity) error {
+    existing, err := r.GetBySubject(identity.Issuer, identity.Subject)
+    if err != nil {
+        return err
+    }
+    if existing != nil {
+        return errors.New("该外部账号已绑定其他用户")
+    }
+    return r.db.Create(identity).Error
+}
+
+// GetBySubject 按issuer和subject查找外部身份
+func (r *userIdentityRepository) GetBySubject(issuer, subject string) (*model.UserIdentity, error) {
+    var identity model.UserIdentity
+    err := r.db.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &identity, nil
+}
+
+// ListByUser 获取用户绑定的全部外部身份
+func (r *userIdentityRepository) ListByUser(userID uint) ([]*model.UserIdentity, error) {
+    var identities []*model.UserIdentity
+    err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&identities).Error
+    return identities, err
+}
+
+// Touch 更新最近登录时间和邮箱
+func (r *userIdentityRepository) Touch(id uint, email string) error {
+    return r.db.Model(&model.UserIdentity{}).Where("id = ?", id).
+        Updates(map[string]interface{}{"email": email, "updated_at": time.Now()}).Error
+}
+
+// Delete 删除外部身份
+func (r *userIdentityRepository) Delete(id uint) error {
+    return r.db.Delete(&model.UserIdentity{}, id).Error
+}
+
+// oidcStateRepository 授权请求状态仓储实现
+type oidcStateRepository struct {
+    db *gorm.DB
+}
+
+// NewOIDCStateRepository 创建授权请求状态仓储
+func NewOIDCStateRepository(db *gorm.DB) repository.OIDCStateRepository {
+    return &oidcStateRepository{db: db}
+}
+
+// Create 保存授权请求状态
+func (r *oidcStateRepository) Create(state *model.OIDCState) error {
+    return r.db.Create(state).Error
+}
+
+// Consume 读取后按ID删除，只有删除成功的请求才能继续，保证并发回调时只有一次成功
+func (r *oidcStateRepository) Consume(stateHash string, now time.Time) (*model.OIDCState, error) {
+    var state model.OIDCState
+    err := r.db.Where("state_hash = ? AND expires_at > ?", stateHash, now).First(&state).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, errors.New("登录请求无效或已过期，请重新登录")
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    result := r.db.Delete(&model.OIDCState{}, state.ID)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, errors.New("登录请求无效或已过期，请重新登录")
+    }
+    return &state, nil
+}
+
+// DeleteExpired 删除before之前过期的状态
+func (r *oidcStateRepository) DeleteExpired(before time.Time) (int64, error) {
+    result := r.db.Where("expires_at < ?", before).Delete(&model.OIDCState{})
+    return result.RowsAffected, result.Error
+}
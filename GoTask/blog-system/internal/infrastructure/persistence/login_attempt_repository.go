@@ -0,0 +1,170 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "time"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+// loginAttemptRepository 登录尝试审计记录仓储实现
+type loginAttemptRepository struct {
+    db *gorm.DB
+}
+
+// NewLoginAttemptRepository 创建登录尝试审计记录仓储
+func NewLoginAttemptRepository(db *gorm.DB) repository.LoginAttemptRepository {
+    return &loginAttemptRepository{db: db}
+}
+
+// Create 保存登录记录
+func (r *loginAttemptRepository) Create(attempt *model.LoginAttempt) error {
+    return r.db.Create(attempt).Error
+}
+
+// List 查询登录记录（分页，按时间倒序）
+func (r *loginAttemptRepository) List(username, ip string, page, limit int) ([]*model.LoginAttempt, int64, error) {
+    var attempts []*model.LoginAttempt
+    var total int64
+
+    offset := (page - 1) * limit
+
+    query := r.db.Model(&model.LoginAttempt{})
+    if username != "" {
+        query = query.Where("username = ?", username)
+    }
+    if ip != "" {
+        query = query.Where("ip = ?", ip)
+    }
+
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := query.Offset(offset).Limit(limit).Order("id desc").Find(&attempts).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return attempts, total, nil
+}
+
+// DeleteBefore 删除before之前的记录
+func (r *loginAttemptRepository) DeleteBefore(before time.Time) (int64, error) {
+    result := r.db.Where("created_at < ?", before).Delete(&model.LoginAttempt{})
+    return result.RowsAffected, result.Error
+}
+
+// loginLockoutRepository 登录失败计数和锁定状态仓储实现
+type loginLockoutRepository struct {
+    db *gorm.DB
+}
+
+// NewLoginLockoutRepository 创建登录失败计数和锁定状态仓储
+func NewLoginLockoutRepository(db *gorm.DB) repository.LoginLockoutRepository {
+    return &loginLockoutRepository{db: db}
+}
+
+// Get 获取计数记录，不存在时返回nil
+func (r *loginLockoutRepository) Get(scope, subject string) (*model.LoginLockout, error) {
+    var lockout model.LoginLockout
+    if err := r.db.Where("scope = ? AND subject = ?", scope, subject).First(&lockout).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, nil
+        }
+        return nil, err
+    }
+    return &lockout, nil
+}
+
+// GetByID 根据ID获取计数记录
+func (r *loginLockoutRepository) GetByID(id uint) (*model.LoginLockout, error) {
+    var lockout model.LoginLockout
+    if err := r.db.First(&lockout, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("锁定记录不存在")
+        }
+        return nil, err
+    }
+    return &lockout, nil
+}
+
+// AddFailure 原子地将失败次数加一，并发的失败登录不会丢失计数
+func (r *loginLockoutRepository) AddFailure(scope, subject string, now, resetBefore time.Time) (*model.LoginLockout, error) {
+    var lockout model.LoginLockout
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        // 记录不存在时先插入，唯一索引保证并发插入只有一条成功
+        if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.LoginLockout{
+            Scope:        scope,
+            Subject:      subject,
+            LastFailedAt: now,
+        }).Error; err != nil {
+            return err
+        }
+
+        if err := tx.Model(&model.LoginLockout{}).
+            Where("scope = ? AND subject = ?", scope, subject).
+            Updates(map[string]interface{}{
+                "failures":       gorm.Expr("CASE WHEN last_failed_at < ? THEN 1 ELSE failures + 1 END", resetBefore),
+                "last_failed_at": now,
+                "updated_at":     now,
+            }).Error; err != nil {
+            return err
+        }
+
+        return tx.Where("scope = ? AND subject = ?", scope, subject).First(&lockout).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &lockout, nil
+}
+
+// Lock 设置锁定截止时间
+func (r *loginLockoutRepository) Lock(id uint, until time.Time) error {
+    return r.db.Model(&model.LoginLockout{}).Where("id = ?", id).Update("locked_until", until).Error
+}
+
+// Reset 清除计数
+func (r *loginLockoutRepository) Reset(scope, subject string) error {
+    return r.db.Where("scope = ? AND subject = ?", scope, subject).Delete(&model.LoginLockout{}).Error
+}
+
+// List 获取计数记录（分页，按最近失败时间倒序）
+func (r *loginLockoutRepository) List(lockedOnly bool, now time.Time, page, limit int) ([]*model.LoginLockout, int64, error) {
+    var lockouts []*model.LoginLockout
+    var total int64
+
+    offset := (page - 1) * limit
+
+    query := r.db.Model(&model.LoginLockout{})
+    if lockedOnly {
+        query = query.Where("locked_until > ?", now)
+    }
+
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := query.Offset(offset).Limit(limit).Order("last_failed_at desc, id desc").Find(&lockouts).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return lockouts, total, nil
+}
+
+// Delete 删除计数记录（解除锁定）
+func (r *loginLockoutRepository) Delete(id uint) error {
+    return r.db.Delete(&model.LoginLockout{}, id).Error
+}
+
+// DeleteStale 删除最近失败早于before且已解除锁定的记录
+func (r *loginLockoutRepository) DeleteStale(before, now time.Time) (int64, error) {
+    result := r.db.
+        Where("last_failed_at < ?", before).
+        Where("locked_until IS NULL OR locked_until <= ?", now).
+        Delete(&model.LoginLockout{})
+    return result.RowsAffected, result.Error
+}
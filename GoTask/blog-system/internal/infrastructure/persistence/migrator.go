@@ -0,0 +1,260 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/migrations"
+    "fmt"
+    "io/fs"
+    "path"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// SchemaMigration 已执行的迁移记录
+type SchemaMigration struct {
+    Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+    Name      string    `gorm:"size:255;not null"`
+    AppliedAt time.Time `gorm:"not null"`
+}
+
+// MigrationStatus 迁移状态
+type MigrationStatus struct {
+    Version   uint
+    Name      string
+    Applied   bool
+    AppliedAt time.Time
+}
+
+// migration 一个版本的上下行迁移脚本
+type migration struct {
+    version uint
+    name    string
+    up      string
+    down    string
+}
+
+// Migrator 版本化迁移执行器
+type Migrator struct {
+    db         *gorm.DB
+    migrations []migration
+}
+
+// NewMigrator 创建迁移执行器，按当前连接的数据库方言加载嵌入的迁移文件
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+    dialect := db.Dialector.Name()
+    list, err := loadMigrations(migrations.FS, dialect)
+    if err != nil {
+        return nil, err
+    }
+    if len(list) == 0 {
+        return nil, fmt.Errorf("没有找到 %s 的迁移文件", dialect)
+    }
+
+    if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+        return nil, fmt.Errorf("创建 schema_migrations 表失败: %w", err)
+    }
+
+    return &Migrator{db: db, migrations: list}, nil
+}
+
+// Up 执行所有未执行的迁移，返回执行的数量
+func (m *Migrator) Up() (int, error) {
+    applied, err := m.applied()
+    if err != nil {
+        return 0, err
+    }
+
+    count := 0
+    for _, mg := range m.migrations {
+        if _, ok := applied[mg.version]; ok {
+            continue
+        }
+
+        err := m.db.Transaction(func(tx *gorm.DB) error {
+            if err := execScript(tx, mg.up); err != nil {
+                return err
+            }
+            return tx.Create(&SchemaMigration{
+                Version:   mg.version,
+                Name:      mg.name,
+                AppliedAt: time.Now(),
+            }).Error
+        })
+        if err != nil {
+            return count, fmt.Errorf("执行迁移 %04d_%s 失败: %w", mg.version, mg.name, err)
+        }
+        count++
+    }
+
+    return count, nil
+}
+
+// Down 回滚最近执行的steps个迁移，返回回滚的数量
+func (m *Migrator) Down(steps int) (int, error) {
+    applied, err := m.applied()
+    if err != nil {
+        return 0, err
+    }
+
+    count := 0
+    for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+        mg := m.migrations[i]
+        if _, ok := applied[mg.version]; !ok {
+            continue
+        }
+
+        err := m.db.Transaction(func(tx *gorm.DB) error {
+            if err := execScript(tx, mg.down); err != nil {
+                return err
+            }
+            return tx.Delete(&SchemaMigration{}, mg.version).Error
+        })
+        if err != nil {
+            return count, fmt.Errorf("回滚迁移 %04d_%s 失败: %w", mg.version, mg.name, err)
+        }
+        count++
+    }
+
+    return count, nil
+}
+
+// Status 获取所有迁移的执行状态
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+    applied, err := m.applied()
+    if err != nil {
+        return nil, err
+    }
+
+    statuses := make([]MigrationStatus, 0, len(m.migrations))
+    for _, mg := range m.migrations {
+        status := MigrationStatus{Version: mg.version, Name: mg.name}
+        if record, ok := applied[mg.version]; ok {
+            status.Applied = true
+            status.AppliedAt = record.AppliedAt
+        }
+        statuses = append(statuses, status)
+    }
+    return statuses, nil
+}
+
+// Pending 获取未执行的迁移数量
+func (m *Migrator) Pending() (int, error) {
+    statuses, err := m.Status()
+    if err != nil {
+        return 0, err
+    }
+
+    count := 0
+    for _, status := range statuses {
+        if !status.Applied {
+            count++
+        }
+    }
+    return count, nil
+}
+
+// applied 获取已执行的迁移记录
+func (m *Migrator) applied() (map[uint]SchemaMigration, error) {
+    var records []SchemaMigration
+    if err := m.db.Find(&records).Error; err != nil {
+        return nil, err
+    }
+
+    result := make(map[uint]SchemaMigration, len(records))
+    for _, record := range records {
+        result[record.Version] = record
+    }
+    return result, nil
+}
+
+// loadMigrations 读取指定方言目录下的迁移文件
+func loadMigrations(fsys fs.FS, dialect string) ([]migration, error) {
+    entries, err := fs.ReadDir(fsys, dialect)
+    if err != nil {
+        return nil, err
+    }
+
+    byVersion := make(map[uint]*migration)
+    for _, entry := range entries {
+        fileName := entry.Name()
+
+        var direction string
+        switch {
+        case strings.HasSuffix(fileName, ".up.sql"):
+            direction = "up"
+        case strings.HasSuffix(fileName, ".down.sql"):
+            direction = "down"
+        default:
+            continue
+        }
+
+        base := strings.TrimSuffix(fileName, "."+direction+".sql")
+        versionPart, name, ok := strings.Cut(base, "_")
+        if !ok {
+            return nil, fmt.Errorf("迁移文件名格式错误: %s", fileName)
+        }
+        version, err := strconv.ParseUint(versionPart, 10, 32)
+        if err != nil {
+            return nil, fmt.Errorf("迁移文件版本号错误: %s", fileName)
+        }
+
+        content, err := fs.ReadFile(fsys, path.Join(dialect, fileName))
+        if err != nil {
+            return nil, err
+        }
+
+        mg, exists := byVersion[uint(version)]
+        if !exists {
+            mg = &migration{version: uint(version), name: name}
+            byVersion[uint(version)] = mg
+        } else if mg.name != name {
+            return nil, fmt.Errorf("迁移版本号重复: %04d", version)
+        }
+
+        if direction == "up" {
+            mg.up = string(content)
+        } else {
+            mg.down = string(content)
+        }
+    }
+
+    list := make([]migration, 0, len(byVersion))
+    for _, mg := range byVersion {
+        if mg.up == "" || mg.down == "" {
+            return nil, fmt.Errorf("迁移 %04d_%s 缺少 up 或 down 文件", mg.version, mg.name)
+        }
+        list = append(list, *mg)
+    }
+    sort.Slice(list, func(i, j int) bool { return list[i].version < list[j].version })
+
+    return list, nil
+}
+
+// execScript 逐条执行脚本中的SQL语句（语句以行尾分号结束）
+func execScript(tx *gorm.DB, script string) error {
+    var stmt strings.Builder
+    for _, line := range strings.Split(script, "\n") {
+        trimmed := strings.TrimSpace(line)
+        if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+            continue
+        }
+
+        stmt.WriteString(line)
+        stmt.WriteString("\n")
+
+        if strings.HasSuffix(trimmed, ";") {
+            if err := tx.Exec(stmt.String()).Error; err != nil {
+                return err
+            }
+            stmt.Reset()
+        }
+    }
+
+    if strings.TrimSpace(stmt.String()) != "" {
+        return tx.Exec(stmt.String()).Error
+    }
+    return nil
+}
@@ -2,9 +2,7 @@ package persistence
 
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/config"
-    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
     "fmt"
-    "log"
 
     "gorm.io/driver/mysql"
     "gorm.io/gorm"
@@ -21,16 +19,8 @@ func NewMySQLConnection(cfg *config.Config) (*gorm.DB, error){
     })
     
     if err != nil {
-        log.Fatalf("数据库连接失败: %v", err)
-        return nil, err
-    }
-    
-    // 自动迁移模型
-    err = db.AutoMigrate(&model.User{}, &model.Post{}, &model.Comment{})
-    if err != nil {
-        log.Fatalf("数据库迁移失败: %v", err)
-        return nil, err
+        return nil, fmt.Errorf("数据库连接失败: %w", err)
     }
     
     return db, nil
-}
\ No newline at end of file
+}
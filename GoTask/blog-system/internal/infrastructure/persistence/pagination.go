@@ -0,0 +1,18 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "fmt"
+
+    "gorm.io/gorm"
+)
+
+// keyset 按 (created_at, id) 倒序排列并跳过游标之前的记录，由 (created_at, id) 上的联合索引支撑
+// 不使用行值比较 (a, b) < (?, ?)，SQLite和MySQL对它能否走索引的处理不一致
+func keyset(db *gorm.DB, table string, after *repository.Cursor) *gorm.DB {
+    if after != nil {
+        db = db.Where(fmt.Sprintf("%[1]s.created_at < ? OR (%[1]s.created_at = ? AND %[1]s.id < ?)", table),
+            after.CreatedAt, after.CreatedAt, after.ID)
+    }
+    return db.Order(fmt.Sprintf("%[1]s.created_at desc, %[1]s.id desc", table))
+}
@@ -4,8 +4,11 @@ import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
     "errors"
+    "strings"
+    "time"
 
     "gorm.io/gorm"
+    "gorm.io/gorm/clause"
 )
 
 // postRepository 文章仓储实现
@@ -26,7 +29,7 @@ func (r *postRepository) Create(post *model.Post) error {
 // GetByID 根据ID获取文章
 func (r *postRepository) GetByID(id uint) (*model.Post, error) {
     var post model.Post
-    if err := r.db.Preload("User").First(&post, id).Error; err != nil {
+    if err := r.withAssociations(r.db).First(&post, id).Error; err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
             return nil, errors.New("文章不存在")
         }
@@ -35,27 +38,27 @@ func (r *postRepository) GetByID(id uint) (*model.Post, error) {
     return &post, nil
 }
 
-// GetAll 获取所有文章（分页）
-func (r *postRepository) GetAll(page, limit int) ([]*model.Post, int64, error) {
+// GetAll 获取所有公开的文章（分页），可按标签和分类筛选
+func (r *postRepository) GetAll(filter repository.PostFilter, page, limit int) ([]*model.Post, int64, error) {
     var posts []*model.Post
     var total int64
 
     offset := (page - 1) * limit
 
     // 获取总数
-    if err := r.db.Model(&model.Post{}).Count(&total).Error; err != nil {
+    if err := r.applyFilter(r.db.Model(&model.Post{}), filter).Count(&total).Error; err != nil {
         return nil, 0, err
     }
 
     // 获取分页数据
-    if err := r.db.Preload("User").Offset(offset).Limit(limit).Order("created_at desc").Find(&posts).Error; err != nil {
+    if err := r.order(r.applyFilter(r.withAssociations(r.db), filter), filter.Sort).Offset(offset).Limit(limit).Find(&posts).Error; err != nil {
         return nil, 0, err
     }
 
     return posts, total, nil
 }
 
-// GetByUserID 获取指定用户的所有文章（分页）
+// GetByUserID 获取指定用户的所有公开文章（分页）
 func (r *postRepository) GetByUserID(userID uint, page, limit int) ([]*model.Post, int64, error) {
     var posts []*model.Post
     var total int64
@@ -63,34 +66,276 @@ func (r *postRepository) GetByUserID(userID uint, page, limit int) ([]*model.Pos
     offset := (page - 1) * limit
 
     // 获取总数
-    if err := r.db.Model(&model.Post{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+    if err := r.public(r.db.Model(&model.Post{})).Where("user_id = ?", userID).Count(&total).Error; err != nil {
         return nil, 0, err
     }
 
     // 获取分页数据
-    if err := r.db.Preload("User").Where("user_id = ?", userID).Offset(offset).Limit(limit).Order("created_at desc").Find(&posts).Error; err != nil {
+    if err := r.public(r.withAssociations(r.db)).Where("user_id = ?", userID).Offset(offset).Limit(limit).Order("created_at desc, id desc").Find(&posts).Error; err != nil {
         return nil, 0, err
     }
 
     return posts, total, nil
 }
 
+// GetAllAfter 按游标获取公开的文章
+func (r *postRepository) GetAllAfter(filter repository.PostFilter, after *repository.Cursor, limit int) ([]*model.Post, error) {
+    var posts []*model.Post
+    query := keyset(r.applyFilter(r.withAssociations(r.db), filter), "posts", after)
+    if err := query.Limit(limit).Find(&posts).Error; err != nil {
+        return nil, err
+    }
+    return posts, nil
+}
+
+// GetByUserIDAfter 按游标获取指定用户的公开文章
+func (r *postRepository) GetByUserIDAfter(userID uint, after *repository.Cursor, limit int) ([]*model.Post, error) {
+    var posts []*model.Post
+    query := keyset(r.public(r.withAssociations(r.db)).Where("posts.user_id = ?", userID), "posts", after)
+    if err := query.Limit(limit).Find(&posts).Error; err != nil {
+        return nil, err
+    }
+    return posts, nil
+}
+
+// GetByAuthor 获取作者自己的文章（分页），包含草稿等所有状态，status为空表示不筛选
+func (r *postRepository) GetByAuthor(userID uint, status string, page, limit int) ([]*model.Post, int64, error) {
+    var posts []*model.Post
+    var total int64
+
+    offset := (page - 1) * limit
+    scope := func(db *gorm.DB) *gorm.DB {
+        db = db.Where("user_id = ?", userID)
+        if status != "" {
+            db = db.Where("status = ?", status)
+        }
+        return db
+    }
+
+    if err := r.db.Model(&model.Post{}).Scopes(scope).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := r.withAssociations(r.db).Scopes(scope).Offset(offset).Limit(limit).Order("updated_at desc").Find(&posts).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return posts, total, nil
+}
+
+// GetDueScheduled 获取发布时间已到的定时文章
+func (r *postRepository) GetDueScheduled(now time.Time, limit int) ([]*model.Post, error) {
+    var posts []*model.Post
+    err := r.withAssociations(r.db).
+        Where("status = ? AND publish_at <= ?", model.PostStatusScheduled, now).
+        Order("publish_at asc").
+        Limit(limit).
+        Find(&posts).Error
+    if err != nil {
+        return nil, err
+    }
+    return posts, nil
+}
+
+// GetWithoutContentHTML 获取尚未生成content_html的文章，包括回收站中的
+func (r *postRepository) GetWithoutContentHTML(limit int) ([]*model.Post, error) {
+    var posts []*model.Post
+    if err := r.db.Unscoped().Where("content_html IS NULL").Order("id asc").Limit(limit).Find(&posts).Error; err != nil {
+        return nil, err
+    }
+    return posts, nil
+}
+
+// SetContentHTML 只更新文章的content_html，不修改更新时间
+func (r *postRepository) SetContentHTML(id uint, html string) error {
+    return r.db.Unscoped().Model(&model.Post{ID: id}).UpdateColumn("content_html", html).Error
+}
+
 // Update 更新文章
 func (r *postRepository) Update(post *model.Post) error {
-    return r.db.Save(post).Error
+    // 关联数据通过各自的仓储维护，计数由回应和收藏仓储原子更新，保存时不能用读到的旧值覆盖
+    return r.db.Omit(clause.Associations, "reaction_count", "bookmark_count").Save(post).Error
 }
 
-// Delete 删除文章
+// Delete 删除文章（软删除，移入回收站），评论和关联数据保留以便恢复
 func (r *postRepository) Delete(id uint) error {
-    // 删除文章时同时删除相关评论
-    tx := r.db.Begin()
-    if err := tx.Where("post_id = ?", id).Delete(&model.Comment{}).Error; err != nil {
-        tx.Rollback()
+    return r.db.Delete(&model.Post{}, id).Error
+}
+
+// GetDeletedByID 获取回收站中的文章
+func (r *postRepository) GetDeletedByID(id uint) (*model.Post, error) {
+    var post model.Post
+    if err := r.withAssociations(r.db.Unscoped()).Where("deleted_at IS NOT NULL").First(&post, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("文章不在回收站中")
+        }
+        return nil, err
+    }
+    return &post, nil
+}
+
+// GetDeletedByAuthor 获取作者回收站中的文章（分页）
+func (r *postRepository) GetDeletedByAuthor(userID uint, page, limit int) ([]*model.Post, int64, error) {
+    var posts []*model.Post
+    var total int64
+
+    offset := (page - 1) * limit
+
+    if err := r.db.Unscoped().Model(&model.Post{}).Where("user_id = ? AND deleted_at IS NOT NULL", userID).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := r.withAssociations(r.db.Unscoped()).Where("user_id = ? AND deleted_at IS NOT NULL", userID).Offset(offset).Limit(limit).Order("deleted_at desc").Find(&posts).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return posts, total, nil
+}
+
+// Restore 从回收站恢复文章
+func (r *postRepository) Restore(id uint) error {
+    result := r.db.Unscoped().Model(&model.Post{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return errors.New("文章不在回收站中")
+    }
+    return nil
+}
+
+// Purge 永久删除before之前进入回收站的文章及其评论、修订记录、回应、收藏和关联，附件保留给上传者
+func (r *postRepository) Purge(before time.Time) (int64, error) {
+    var ids []uint
+    if err := r.db.Unscoped().Model(&model.Post{}).Where("deleted_at < ?", before).Pluck("id", &ids).Error; err != nil {
+        return 0, err
+    }
+
+    var count int64
+    for _, id := range ids {
+        if err := r.purge(id); err != nil {
+            return count, err
+        }
+        count++
+    }
+    return count, nil
+}
+
+// purge 在一个事务中永久删除文章及其相关数据
+func (r *postRepository) purge(id uint) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec("DELETE FROM post_tags WHERE post_id = ?", id).Error; err != nil {
+            return err
+        }
+        if err := tx.Exec("DELETE FROM post_categories WHERE post_id = ?", id).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("post_id = ?", id).Delete(&model.PostRevision{}).Error; err != nil {
+            return err
+        }
+        if err := deleteReactions(tx, model.ReactionTargetPost, []uint{id}); err != nil {
+            return err
+        }
+        commentIDs := tx.Unscoped().Model(&model.Comment{}).Select("id").Where("post_id = ?", id)
+        if err := deleteReactions(tx, model.ReactionTargetComment, commentIDs); err != nil {
+            return err
+        }
+        if err := tx.Where("post_id = ?", id).Delete(&model.Bookmark{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Unscoped().Where("post_id = ?", id).Delete(&model.Comment{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Model(&model.Attachment{}).Where("post_id = ?", id).Update("post_id", nil).Error; err != nil {
+            return err
+        }
+        return tx.Unscoped().Delete(&model.Post{}, id).Error
+    })
+}
+
+// ReassignUser 将用户的所有文章（包括回收站中的）、修订记录和文章中的附件转给另一个用户
+func (r *postRepository) ReassignUser(fromID, toID uint) error {
+    if err := r.db.Unscoped().Model(&model.Post{}).Where("user_id = ?", fromID).Update("user_id", toID).Error; err != nil {
         return err
     }
-    if err := tx.Delete(&model.Post{}, id).Error; err != nil {
-        tx.Rollback()
+    if err := r.db.Model(&model.Attachment{}).Where("user_id = ? AND post_id IS NOT NULL", fromID).Update("user_id", toID).Error; err != nil {
         return err
     }
-    return tx.Commit().Error
+    return r.db.Model(&model.PostRevision{}).Where("user_id = ?", fromID).Update("user_id", toID).Error
+}
+
+// DeleteByUserID 将用户的所有文章移入回收站，返回被删除的文章ID
+func (r *postRepository) DeleteByUserID(userID uint) ([]uint, error) {
+    var ids []uint
+    if err := r.db.Model(&model.Post{}).Where("user_id = ?", userID).Pluck("id", &ids).Error; err != nil {
+        return nil, err
+    }
+    if len(ids) == 0 {
+        return ids, nil
+    }
+    if err := r.db.Where("id IN ?", ids).Delete(&model.Post{}).Error; err != nil {
+        return nil, err
+    }
+    return ids, nil
+}
+
+// withAssociations 预加载作者、标签、分类和附件
+func (r *postRepository) withAssociations(db *gorm.DB) *gorm.DB {
+    return db.Preload("User").Preload("Tags").Preload("Categories").Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+        return db.Order("id asc")
+    })
+}
+
+// public 只保留已发布且未被隐藏的文章
+func (r *postRepository) public(db *gorm.DB) *gorm.DB {
+    return db.Where("posts.status = ? AND posts.hidden = ?", model.PostStatusPublished, false)
+}
+
+// applyFilter 应用列表筛选条件
+func (r *postRepository) applyFilter(db *gorm.DB, filter repository.PostFilter) *gorm.DB {
+    db = r.public(db)
+    if filter.Tag != "" {
+        db = db.Where("posts.id IN (?)", r.db.Table("post_tags").
+            Select("post_tags.post_id").
+            Joins("JOIN tags ON tags.id = post_tags.tag_id").
+            Where("tags.name = ?", filter.Tag))
+    }
+    if filter.Category != "" {
+        db = db.Where("posts.id IN (?)", r.db.Table("post_categories").
+            Select("post_categories.post_id").
+            Joins("JOIN categories ON categories.id = post_categories.category_id").
+            Where("categories.name = ?", filter.Category))
+    }
+    if filter.Author != "" {
+        db = db.Where("posts.user_id IN (?)", r.db.Table("users").
+            Select("users.id").
+            Where("users.username = ?", filter.Author))
+    }
+    if filter.CreatedAfter != nil {
+        db = db.Where("posts.created_at >= ?", *filter.CreatedAfter)
+    }
+    if filter.CreatedBefore != nil {
+        db = db.Where("posts.created_at < ?", *filter.CreatedBefore)
+    }
+    if filter.TitleContains != "" {
+        db = db.Where("posts.title LIKE ? ESCAPE '!'", "%"+likeEscaper.Replace(filter.TitleContains)+"%")
+    }
+    return db
+}
+
+// likeEscaper 转义LIKE中的通配符，转义字符用'!'，反斜杠在MySQL和SQLite的字符串字面量中含义不同
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// order 按排序条件排序，最后按ID倒序保证顺序稳定；字段必须在 repository.PostSortFields 中，其余的忽略
+func (r *postRepository) order(db *gorm.DB, sorts []repository.PostSort) *gorm.DB {
+    if len(sorts) == 0 {
+        return db.Order("posts.created_at desc, posts.id desc")
+    }
+    for _, sort := range sorts {
+        if !repository.IsValidPostSortField(sort.Field) {
+            continue
+        }
+        db = db.Order(clause.OrderByColumn{Column: clause.Column{Table: "posts", Name: sort.Field}, Desc: sort.Desc})
+    }
+    return db.Order("posts.id desc")
 }
\ No newline at end of file
@@ -0,0 +1,128 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "fmt"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+// reactionRepository 表情回应仓储实现
+type reactionRepository struct {
+    db *gorm.DB
+}
+
+// NewReactionRepository 创建表情回应仓储
+func NewReactionRepository(db *gorm.DB) repository.ReactionRepository {
+    return &reactionRepository{db: db}
+}
+
+// Add 添加回应，只有真正插入了记录才增加计数，重复请求不会重复计数
+func (r *reactionRepository) Add(reaction *model.Reaction) (bool, error) {
+    var created bool
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+        if result.Error != nil || result.RowsAffected == 0 {
+            return result.Error
+        }
+        created = true
+        return adjustReactionCount(tx, reaction.TargetType, reaction.TargetID, 1)
+    })
+    return created, err
+}
+
+// Remove 删除回应，只有真正删除了记录才减少计数
+func (r *reactionRepository) Remove(userID uint, targetType string, targetID uint, emoji string) (bool, error) {
+    var removed bool
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("target_type = ? AND target_id = ? AND user_id = ? AND emoji = ?", targetType, targetID, userID, emoji).
+            Delete(&model.Reaction{})
+        if result.Error != nil || result.RowsAffected == 0 {
+            return result.Error
+        }
+        removed = true
+        return adjustReactionCount(tx, targetType, targetID, -1)
+    })
+    return removed, err
+}
+
+// CountByTarget 按表情统计目标的回应数
+func (r *reactionRepository) CountByTarget(targetType string, targetID uint) (map[string]int64, error) {
+    var rows []struct {
+        Emoji string
+        Count int64
+    }
+    err := r.db.Model(&model.Reaction{}).
+        Select("emoji, COUNT(*) AS count").
+        Where("target_type = ? AND target_id = ?", targetType, targetID).
+        Group("emoji").
+        Scan(&rows).Error
+    if err != nil {
+        return nil, err
+    }
+
+    counts := make(map[string]int64, len(rows))
+    for _, row := range rows {
+        counts[row.Emoji] = row.Count
+    }
+    return counts, nil
+}
+
+// ListByUser 用户对目标的全部回应表情
+func (r *reactionRepository) ListByUser(userID uint, targetType string, targetID uint) ([]string, error) {
+    emojis := []string{}
+    err := r.db.Model(&model.Reaction{}).
+        Where("target_type = ? AND target_id = ? AND user_id = ?", targetType, targetID, userID).
+        Order("id asc").
+        Pluck("emoji", &emojis).Error
+    return emojis, err
+}
+
+// DeleteByUser 删除用户的全部回应，按目标汇总后减少计数
+func (r *reactionRepository) DeleteByUser(userID uint) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        var rows []struct {
+            TargetType string
+            TargetID   uint
+            Count      int64
+        }
+        err := tx.Model(&model.Reaction{}).
+            Select("target_type, target_id, COUNT(*) AS count").
+            Where("user_id = ?", userID).
+            Group("target_type, target_id").
+            Scan(&rows).Error
+        if err != nil {
+            return err
+        }
+
+        for _, row := range rows {
+            if err := adjustReactionCount(tx, row.TargetType, row.TargetID, -row.Count); err != nil {
+                return err
+            }
+        }
+        return tx.Where("user_id = ?", userID).Delete(&model.Reaction{}).Error
+    })
+}
+
+// adjustReactionCount 调整文章或评论的回应计数，回收站中的目标同样调整；不修改updated_at
+func adjustReactionCount(tx *gorm.DB, targetType string, targetID uint, delta int64) error {
+    var target interface{}
+    switch targetType {
+    case model.ReactionTargetPost:
+        target = &model.Post{}
+    case model.ReactionTargetComment:
+        target = &model.Comment{}
+    default:
+        return fmt.Errorf("未知的回应目标类型: %s", targetType)
+    }
+    return tx.Unscoped().Model(target).Where("id = ?", targetID).
+        UpdateColumn("reaction_count", gorm.Expr("reaction_count + ?", delta)).Error
+}
+
+// deleteReactions 删除目标上的全部回应，用于永久删除文章和评论，目标本身随后删除所以不调整计数
+// ids为查询目标ID的子查询
+func deleteReactions(tx *gorm.DB, targetType string, ids interface{}) error {
+    return tx.Where("target_type = ? AND target_id IN (?)", targetType, ids).Delete(&model.Reaction{}).Error
+}
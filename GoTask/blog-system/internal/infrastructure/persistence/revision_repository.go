@@ -0,0 +1,66 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+
+    "gorm.io/gorm"
+)
+
+// postRevisionRepository 文章修订记录仓储实现
+type postRevisionRepository struct {
+    db *gorm.DB
+}
+
+// NewPostRevisionRepository 创建文章修订记录仓储
+func NewPostRevisionRepository(db *gorm.DB) repository.PostRevisionRepository {
+    return &postRevisionRepository{db: db}
+}
+
+// Create 保存修订记录，Revision为0时自动分配下一个版本号
+func (r *postRevisionRepository) Create(revision *model.PostRevision) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if revision.Revision == 0 {
+            var latest int
+            if err := tx.Model(&model.PostRevision{}).
+                Where("post_id = ?", revision.PostID).
+                Select("COALESCE(MAX(revision), 0)").
+                Scan(&latest).Error; err != nil {
+                return err
+            }
+            revision.Revision = latest + 1
+        }
+        return tx.Create(revision).Error
+    })
+}
+
+// GetByPostID 获取文章的修订记录（分页，按版本号倒序）
+func (r *postRevisionRepository) GetByPostID(postID uint, page, limit int) ([]*model.PostRevision, int64, error) {
+    var revisions []*model.PostRevision
+    var total int64
+
+    offset := (page - 1) * limit
+
+    if err := r.db.Model(&model.PostRevision{}).Where("post_id = ?", postID).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := r.db.Where("post_id = ?", postID).Offset(offset).Limit(limit).Order("revision desc").Find(&revisions).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return revisions, total, nil
+}
+
+// GetByRevision 根据版本号获取修订记录
+func (r *postRevisionRepository) GetByRevision(postID uint, revision int) (*model.PostRevision, error) {
+    var rev model.PostRevision
+    if err := r.db.Where("post_id = ? AND revision = ?", postID, revision).First(&rev).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("修订版本不存在")
+        }
+        return nil, err
+    }
+    return &rev, nil
+}
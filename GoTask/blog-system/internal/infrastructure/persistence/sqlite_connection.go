@@ -0,0 +1,32 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/config"
+    "fmt"
+
+    "gorm.io/driver/sqlite"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+// NewSQLiteConnection 创建SQLite连接，DB_PATH为":memory:"时使用内存数据库
+func NewSQLiteConnection(cfg *config.Config) (*gorm.DB, error) {
+    // 开启外键约束，并在并发写入时等待锁而不是立即报错
+    dsn := cfg.DBConfig.Path + "?_foreign_keys=on&_busy_timeout=5000"
+
+    db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+        Logger: logger.Default.LogMode(logger.Info),
+    })
+    if err != nil {
+        return nil, fmt.Errorf("数据库连接失败: %w", err)
+    }
+
+    // SQLite同一时间只允许一个写连接；内存数据库每个连接相互独立，也必须共用同一连接
+    sqlDB, err := db.DB()
+    if err != nil {
+        return nil, err
+    }
+    sqlDB.SetMaxOpenConns(1)
+
+    return db, nil
+}
@@ -0,0 +1,104 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+
+    "gorm.io/gorm"
+)
+
+// tagRepository 标签仓储实现
+type tagRepository struct {
+    db *gorm.DB
+}
+
+// NewTagRepository 创建标签仓储
+func NewTagRepository(db *gorm.DB) repository.TagRepository {
+    return &tagRepository{db: db}
+}
+
+// Create 创建标签
+func (r *tagRepository) Create(tag *model.Tag) error {
+    return r.db.Create(tag).Error
+}
+
+// GetByID 根据ID获取标签
+func (r *tagRepository) GetByID(id uint) (*model.Tag, error) {
+    var tag model.Tag
+    if err := r.db.First(&tag, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("标签不存在")
+        }
+        return nil, err
+    }
+    return &tag, nil
+}
+
+// GetByName 根据名称获取标签
+func (r *tagRepository) GetByName(name string) (*model.Tag, error) {
+    var tag model.Tag
+    if err := r.db.Where("name = ?", name).First(&tag).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("标签不存在")
+        }
+        return nil, err
+    }
+    return &tag, nil
+}
+
+// GetAll 获取所有标签
+func (r *tagRepository) GetAll() ([]*model.Tag, error) {
+    var tags []*model.Tag
+    if err := r.db.Order("name asc").Find(&tags).Error; err != nil {
+        return nil, err
+    }
+    return tags, nil
+}
+
+// Update 更新标签
+func (r *tagRepository) Update(tag *model.Tag) error {
+    return r.db.Save(tag).Error
+}
+
+// Delete 删除标签及其与文章的关联
+func (r *tagRepository) Delete(id uint) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec("DELETE FROM post_tags WHERE tag_id = ?", id).Error; err != nil {
+            return err
+        }
+        return tx.Delete(&model.Tag{}, id).Error
+    })
+}
+
+// SetPostTags 替换文章的全部标签
+func (r *tagRepository) SetPostTags(postID uint, tagIDs []uint) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec("DELETE FROM post_tags WHERE post_id = ?", postID).Error; err != nil {
+            return err
+        }
+        for _, tagID := range tagIDs {
+            if err := tx.Exec("INSERT INTO post_tags (post_id, tag_id) VALUES (?, ?)", postID, tagID).Error; err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+// Cloud 获取标签云（按公开文章数降序）
+func (r *tagRepository) Cloud(limit int) ([]*model.TagCount, error) {
+    counts := []*model.TagCount{}
+    err := r.db.Table("tags").
+        Select("tags.id, tags.name, COUNT(posts.id) AS post_count").
+        Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+        Joins("JOIN posts ON posts.id = post_tags.post_id AND posts.status = ? AND posts.hidden = ? AND posts.deleted_at IS NULL", model.PostStatusPublished, false).
+        Group("tags.id, tags.name").
+        Order("post_count desc, tags.name asc").
+        Limit(limit).
+        Scan(&counts).Error
+    if err != nil {
+        return nil, err
+    }
+    return counts, nil
+}
@@ -0,0 +1,35 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+
+    "gorm.io/gorm"
+)
+
+// transactor 基于数据库事务的事务执行器
+type transactor struct {
+    db *gorm.DB
+}
+
+// NewTransactor 创建事务执行器
+func NewTransactor(db *gorm.DB) repository.Transactor {
+    return &transactor{db: db}
+}
+
+// WithinTransaction 在数据库事务中执行fn，事务内的仓储共用同一个连接
+func (t *transactor) WithinTransaction(fn func(repos repository.TxRepositories) error) error {
+    return t.db.Transaction(func(tx *gorm.DB) error {
+        return fn(repository.TxRepositories{
+            Users:      NewUserRepository(tx),
+            Posts:      NewPostRepository(tx),
+            Comments:   NewCommentRepository(tx),
+            Tags:       NewTagRepository(tx),
+            Categories: NewCategoryRepository(tx),
+            Revisions:  NewPostRevisionRepository(tx),
+            Identities: NewUserIdentityRepository(tx),
+            APITokens:  NewAPITokenRepository(tx),
+            Reactions:  NewReactionRepository(tx),
+            Bookmarks:  NewBookmarkRepository(tx),
+        })
+    })
+}
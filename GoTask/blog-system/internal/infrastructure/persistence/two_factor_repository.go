@@ -0,0 +1,102 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// userTOTPRepository TOTP设置仓储实现
+type userTOTPRepository struct {
+    db *gorm.DB
+}
+
+// NewUserTOTPRepository 创建TOTP设置仓储
+func NewUserTOTPRepository(db *gorm.DB) repository.UserTOTPRepository {
+    return &userTOTPRepository{db: db}
+}
+
+// GetByUser 获取用户的TOTP设置
+func (r *userTOTPRepository) GetByUser(userID uint) (*model.UserTOTP, error) {
+    var totp model.UserTOTP
+    err := r.db.Where("user_id = ?", userID).First(&totp).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &totp, nil
+}
+
+// Save 保存TOTP设置
+func (r *userTOTPRepository) Save(totp *model.UserTOTP) error {
+    if totp.ID == 0 {
+        return r.db.Create(totp).Error
+    }
+    return r.db.Save(totp).Error
+}
+
+// UseStep 条件更新保证同一个时间步并发使用时只有一次成功
+func (r *userTOTPRepository) UseStep(userID uint, step int64) (bool, error) {
+    result := r.db.Model(&model.UserTOTP{}).
+        Where("user_id = ? AND last_step < ?", userID, step).
+        Update("last_step", step)
+    return result.RowsAffected > 0, result.Error
+}
+
+// DeleteByUser 删除用户的TOTP设置
+func (r *userTOTPRepository) DeleteByUser(userID uint) error {
+    return r.db.Where("user_id = ?", userID).Delete(&model.UserTOTP{}).Error
+}
+
+// recoveryCodeRepository 恢复码仓储实现
+type recoveryCodeRepository struct {
+    db *gorm.DB
+}
+
+// NewRecoveryCodeRepository 创建恢复码仓储
+func NewRecoveryCodeRepository(db *gorm.DB) repository.RecoveryCodeRepository {
+    return &recoveryCodeRepository{db: db}
+}
+
+// Replace 在事务中删除旧恢复码并保存新恢复码
+func (r *recoveryCodeRepository) Replace(userID uint, codeHashes []string) error {
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+            return err
+        }
+
+        codes := make([]model.RecoveryCode, len(codeHashes))
+        for i, hash := range codeHashes {
+            codes[i] = model.RecoveryCode{UserID: userID, CodeHash: hash}
+        }
+        if len(codes) == 0 {
+            return nil
+        }
+        return tx.Create(&codes).Error
+    })
+}
+
+// Consume 条件更新保证同一个恢复码并发使用时只有一次成功
+func (r *recoveryCodeRepository) Consume(userID uint, codeHash string, now time.Time) (bool, error) {
+    result := r.db.Model(&model.RecoveryCode{}).
+        Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+        Update("used_at", now)
+    return result.RowsAffected > 0, result.Error
+}
+
+// CountUnused 统计未使用的恢复码
+func (r *recoveryCodeRepository) CountUnused(userID uint) (int64, error) {
+    var count int64
+    err := r.db.Model(&model.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+    return count, err
+}
+
+// DeleteByUser 删除用户的全部恢复码
+func (r *recoveryCodeRepository) DeleteByUser(userID uint) error {
+    return r.db.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+}
@@ -4,6 +4,7 @@ import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
     "errors"
+    "time"
 
     "gorm.io/gorm"
 )
@@ -20,6 +21,9 @@ func NewUserRepository(db *gorm.DB) repository.UserRepository {
 
 // Create 创建用户
 func (r *userRepository) Create(user *model.User) error {
+    if err := r.checkUnique(user); err != nil {
+        return err
+    }
     return r.db.Create(user).Error
 }
 
@@ -59,12 +63,96 @@ func (r *userRepository) GetByEmail(email string) (*model.User, error) {
     return &user, nil
 }
 
+// List 获取用户列表（分页）
+func (r *userRepository) List(page, limit int) ([]*model.User, int64, error) {
+    var users []*model.User
+    var total int64
+
+    offset := (page - 1) * limit
+
+    // 获取总数
+    if err := r.db.Model(&model.User{}).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    // 获取分页数据
+    if err := r.db.Offset(offset).Limit(limit).Order("id asc").Find(&users).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return users, total, nil
+}
+
 // Update 更新用户信息
 func (r *userRepository) Update(user *model.User) error {
+    if err := r.checkUnique(user); err != nil {
+        return err
+    }
     return r.db.Save(user).Error
 }
 
-// Delete 删除用户
+// Delete 删除用户（软删除，移入回收站）
 func (r *userRepository) Delete(id uint) error {
     return r.db.Delete(&model.User{}, id).Error
-}
\ No newline at end of file
+}
+
+// GetDeleted 获取回收站中的用户（分页）
+func (r *userRepository) GetDeleted(page, limit int) ([]*model.User, int64, error) {
+    var users []*model.User
+    var total int64
+
+    offset := (page - 1) * limit
+    query := r.db.Unscoped().Model(&model.User{}).Where("deleted_at IS NOT NULL")
+
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if err := query.Offset(offset).Limit(limit).Order("deleted_at desc").Find(&users).Error; err != nil {
+        return nil, 0, err
+    }
+
+    return users, total, nil
+}
+
+// Restore 从回收站恢复用户
+func (r *userRepository) Restore(id uint) error {
+    result := r.db.Unscoped().Model(&model.User{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return errors.New("用户不在回收站中")
+    }
+    return nil
+}
+
+// Purge 永久删除before之前进入回收站、且名下已没有文章、评论和附件的用户
+func (r *userRepository) Purge(before time.Time) (int64, error) {
+    result := r.db.Unscoped().
+        Where("deleted_at < ?", before).
+        Where("NOT EXISTS (SELECT 1 FROM posts WHERE posts.user_id = users.id)").
+        Where("NOT EXISTS (SELECT 1 FROM comments WHERE comments.user_id = users.id)").
+        Where("NOT EXISTS (SELECT 1 FROM attachments WHERE attachments.user_id = users.id)").
+        Delete(&model.User{})
+    return result.RowsAffected, result.Error
+}
+
+// checkUnique 检查用户名和邮箱是否已被占用，回收站中的用户同样占用，避免返回数据库约束错误
+func (r *userRepository) checkUnique(user *model.User) error {
+    var count int64
+    if err := r.db.Unscoped().Model(&model.User{}).Where("username = ? AND id <> ?", user.Username, user.ID).Count(&count).Error; err != nil {
+        return err
+    }
+    if count > 0 {
+        return errors.New("用户名已存在")
+    }
+
+    if err := r.db.Unscoped().Model(&model.User{}).Where("email = ? AND id <> ?", user.Email, user.ID).Count(&count).Error; err != nil {
+        return err
+    }
+    if count > 0 {
+        return errors.New("邮箱已存在")
+    }
+    return nil
+}
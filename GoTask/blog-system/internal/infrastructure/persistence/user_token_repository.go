@@ -0,0 +1,55 @@
+package persistence
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// userTokenRepository 一次性令牌仓储实现
+type userTokenRepository struct {
+    db *gorm.DB
+}
+
+// NewUserTokenRepository 创建一次性令牌仓储
+func NewUserTokenRepository(db *gorm.DB) repository.UserTokenRepository {
+    return &userTokenRepository{db: db}
+}
+
+// Create 保存令牌
+func (r *userTokenRepository) Create(token *model.UserToken) error {
+    return r.db.Create(token).Error
+}
+
+// Consume 标记令牌为已使用，条件更新保证同一个令牌并发使用时只有一次成功
+func (r *userTokenRepository) Consume(purpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+    result := r.db.Model(&model.UserToken{}).
+        Where("purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?", purpose, tokenHash, now).
+        Update("used_at", now)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, errors.New("链接无效或已过期")
+    }
+
+    var token model.UserToken
+    if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+        return nil, err
+    }
+    return &token, nil
+}
+
+// DeleteByUser 删除用户指定用途的全部令牌
+func (r *userTokenRepository) DeleteByUser(userID uint, purpose string) error {
+    return r.db.Where("user_id = ? AND purpose = ?", userID, purpose).Delete(&model.UserToken{}).Error
+}
+
+// DeleteExpired 删除before之前过期或已使用的令牌
+func (r *userTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+    result := r.db.Where("expires_at < ? OR used_at < ?", before, before).Delete(&model.UserToken{})
+    return result.RowsAffected, result.Error
+}
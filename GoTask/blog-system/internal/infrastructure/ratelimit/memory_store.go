@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+    "sync"
+    "time"
+)
+
+// sweepInterval 清理已补满的桶的最小间隔
+const sweepInterval = time.Minute
+
+// bucket 单个key的令牌桶状态
+type bucket struct {
+    tokens  float64
+    updated time.Time
+    fullAt  time.Time // 到这个时间桶已补满，可以直接删除
+}
+
+// memoryStore 内存令牌桶存储，适用于单实例部署
+type memoryStore struct {
+    mu        sync.Mutex
+    buckets   map[string]*bucket
+    lastSweep time.Time
+}
+
+// NewMemoryStore 创建内存令牌桶存储
+func NewMemoryStore() Store {
+    return &memoryStore{buckets: make(map[string]*bucket)}
+}
+
+// Take 从key对应的桶中取一个令牌
+func (s *memoryStore) Take(key string, rule Rule, now time.Time) (Result, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    s.sweep(now)
+
+    b, ok := s.buckets[key]
+    if !ok {
+        b = &bucket{tokens: float64(rule.Limit), updated: now}
+        s.buckets[key] = b
+    }
+
+    result, tokens := take(b.tokens, b.updated, rule, now)
+    b.tokens = tokens
+    b.updated = now
+    b.fullAt = now.Add(result.ResetAfter)
+    return result, nil
+}
+
+// sweep 删除已补满的桶，补满的桶与不存在的桶等价，避免按IP建立的桶无限增长
+func (s *memoryStore) sweep(now time.Time) {
+    if now.Sub(s.lastSweep) < sweepInterval {
+        return
+    }
+    s.lastSweep = now
+    for key, b := range s.buckets {
+        if !b.fullAt.After(now) {
+            delete(s.buckets, key)
+        }
+    }
+}
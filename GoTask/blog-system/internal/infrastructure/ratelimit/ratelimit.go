@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+    "time"
+)
+
+// Rule 令牌桶规则：桶容量为Limit，每个Period补满一次（匀速补充）
+type Rule struct {
+    Limit  int
+    Period time.Duration
+}
+
+// Enabled 规则是否生效，Limit或Period为0表示不限流
+func (r Rule) Enabled() bool {
+    return r.Limit > 0 && r.Period > 0
+}
+
+// interval 补充一个令牌所需的时间
+func (r Rule) interval() time.Duration {
+    return r.Period / time.Duration(r.Limit)
+}
+
+// Result 一次取令牌的结果
+type Result struct {
+    Allowed    bool
+    Limit      int           // 桶容量
+    Remaining  int           // 剩余令牌数
+    RetryAfter time.Duration // 被拒绝时距离下一个令牌可用的时间
+    ResetAfter time.Duration // 桶补满所需的时间
+}
+
+// Store 令牌桶存储接口，Take需要保证同一个key的并发调用是原子的；
+// 多实例部署时可以用Redis等共享存储实现（如用Lua脚本保存令牌数和更新时间）
+type Store interface {
+    // Take 从key对应的桶中取一个令牌，桶不存在时按满桶处理
+    Take(key string, rule Rule, now time.Time) (Result, error)
+}
+
+// take 根据桶的当前状态计算取令牌的结果，tokens为上次更新时的令牌数，
+// 返回结果和更新后的令牌数
+func take(tokens float64, updated time.Time, rule Rule, now time.Time) (Result, float64) {
+    interval := rule.interval()
+    if elapsed := now.Sub(updated); elapsed > 0 {
+        tokens += float64(elapsed) / float64(interval)
+    }
+    if limit := float64(rule.Limit); tokens > limit {
+        tokens = limit
+    }
+
+    result := Result{Limit: rule.Limit}
+    if tokens >= 1 {
+        tokens--
+        result.Allowed = true
+    } else {
+        result.RetryAfter = time.Duration((1 - tokens) * float64(interval))
+    }
+    result.Remaining = int(tokens)
+    result.ResetAfter = time.Duration((float64(rule.Limit) - tokens) * float64(interval))
+    return result, tokens
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+    "html"
+    "strings"
+    "unicode"
+)
+
+// snippetRadius 高亮片段中匹配位置前后保留的字符数
+const snippetRadius = 40
+
+// Highlight 从文本中截取包含查询词的片段，匹配词以<mark>包裹，其余内容做HTML转义
+func Highlight(text, query string) string {
+    terms := queryTerms(query)
+    runes := []rune(text)
+
+    // 逐字符转小写，保证下标与原文一致
+    lower := make([]rune, len(runes))
+    for i, r := range runes {
+        lower[i] = unicode.ToLower(r)
+    }
+
+    termRunes := make([][]rune, len(terms))
+    for i, term := range terms {
+        termRunes[i] = []rune(term)
+    }
+
+    // matchAt 返回在位置i匹配到的最长查询词长度
+    matchAt := func(i int) int {
+        for _, term := range termRunes {
+            if i+len(term) <= len(lower) && equalRunes(lower[i:i+len(term)], term) {
+                return len(term)
+            }
+        }
+        return 0
+    }
+
+    // 以第一个匹配位置为中心截取片段
+    first := -1
+    for i := range lower {
+        if matchAt(i) > 0 {
+            first = i
+            break
+        }
+    }
+
+    start, end := 0, len(runes)
+    if first >= 0 {
+        start = first - snippetRadius
+        end = first + snippetRadius
+    } else {
+        end = snippetRadius * 2
+    }
+    if start < 0 {
+        start = 0
+    }
+    if end > len(runes) {
+        end = len(runes)
+    }
+
+    var b strings.Builder
+    if start > 0 {
+        b.WriteString("...")
+    }
+    plainStart := start
+    for i := start; i < end; {
+        n := matchAt(i)
+        if n == 0 {
+            i++
+            continue
+        }
+        if i+n > end {
+            n = end - i
+        }
+        b.WriteString(html.EscapeString(string(runes[plainStart:i])))
+        b.WriteString("<mark>")
+        b.WriteString(html.EscapeString(string(runes[i : i+n])))
+        b.WriteString("</mark>")
+        i += n
+        plainStart = i
+    }
+    b.WriteString(html.EscapeString(string(runes[plainStart:end])))
+    if end < len(runes) {
+        b.WriteString("...")
+    }
+
+    return b.String()
+}
+
+func equalRunes(a, b []rune) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i] != b[i] {
+            return false
+        }
+    }
+    return true
+}
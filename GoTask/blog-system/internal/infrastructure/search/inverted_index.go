@@ -0,0 +1,215 @@
+package search
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "fmt"
+    "math"
+    "sort"
+    "sync"
+)
+
+// BM25参数
+const (
+    bm25K1      = 1.2
+    bm25B       = 0.75
+    titleWeight = 3 // 标题中的词按多次出现计算
+)
+
+// indexedDoc 索引中的文档
+type indexedDoc struct {
+    doc    model.SearchDocument
+    terms  map[string]int // 词频
+    length int
+}
+
+// invertedIndex 纯Go实现的内存倒排索引
+type invertedIndex struct {
+    mu          sync.RWMutex
+    docs        map[string]*indexedDoc
+    postings    map[string]map[string]struct{} // 词 -> 文档key集合
+    postDocs    map[uint]map[string]struct{}   // 文章ID -> 文章及评论文档key集合
+    totalLength int
+}
+
+// NewInvertedIndex 创建内存倒排索引
+func NewInvertedIndex() repository.SearchIndex {
+    return &invertedIndex{
+        docs:     make(map[string]*indexedDoc),
+        postings: make(map[string]map[string]struct{}),
+        postDocs: make(map[uint]map[string]struct{}),
+    }
+}
+
+func docKey(docType string, id uint) string {
+    return fmt.Sprintf("%s:%d", docType, id)
+}
+
+// Index 新增或更新文档
+func (idx *invertedIndex) Index(doc *model.SearchDocument) error {
+    idx.mu.Lock()
+    defer idx.mu.Unlock()
+
+    key := docKey(doc.Type, doc.ID)
+    idx.removeLocked(key)
+
+    terms := make(map[string]int)
+    length := 0
+    // 评论文档的标题只用于展示，不参与索引
+    if doc.Type == model.SearchTypePost {
+        for _, token := range Tokenize(doc.Title) {
+            terms[token] += titleWeight
+            length += titleWeight
+        }
+    }
+    for _, token := range Tokenize(doc.Content) {
+        terms[token]++
+        length++
+    }
+
+    idx.docs[key] = &indexedDoc{doc: *doc, terms: terms, length: length}
+    idx.totalLength += length
+    for term := range terms {
+        if idx.postings[term] == nil {
+            idx.postings[term] = make(map[string]struct{})
+        }
+        idx.postings[term][key] = struct{}{}
+    }
+    if idx.postDocs[doc.PostID] == nil {
+        idx.postDocs[doc.PostID] = make(map[string]struct{})
+    }
+    idx.postDocs[doc.PostID][key] = struct{}{}
+
+    // 文章标题变更时同步评论文档中展示的标题
+    if doc.Type == model.SearchTypePost {
+        for k := range idx.postDocs[doc.PostID] {
+            idx.docs[k].doc.Title = doc.Title
+        }
+    }
+
+    return nil
+}
+
+// Remove 删除单个文档
+func (idx *invertedIndex) Remove(docType string, id uint) error {
+    idx.mu.Lock()
+    defer idx.mu.Unlock()
+
+    idx.removeLocked(docKey(docType, id))
+    return nil
+}
+
+// RemovePost 删除文章及其所有评论的文档
+func (idx *invertedIndex) RemovePost(postID uint) error {
+    idx.mu.Lock()
+    defer idx.mu.Unlock()
+
+    for key := range idx.postDocs[postID] {
+        idx.removeLocked(key)
+    }
+    delete(idx.postDocs, postID)
+    return nil
+}
+
+// removeLocked 删除文档（调用方需持有写锁）
+func (idx *invertedIndex) removeLocked(key string) {
+    d, ok := idx.docs[key]
+    if !ok {
+        return
+    }
+
+    for term := range d.terms {
+        delete(idx.postings[term], key)
+        if len(idx.postings[term]) == 0 {
+            delete(idx.postings, term)
+        }
+    }
+    if keys, ok := idx.postDocs[d.doc.PostID]; ok {
+        delete(keys, key)
+        if len(keys) == 0 {
+            delete(idx.postDocs, d.doc.PostID)
+        }
+    }
+    idx.totalLength -= d.length
+    delete(idx.docs, key)
+}
+
+// Search 使用BM25算法按相关度排序搜索
+func (idx *invertedIndex) Search(query, docType string, page, limit int) ([]*model.SearchHit, int64, error) {
+    idx.mu.RLock()
+    defer idx.mu.RUnlock()
+
+    tokens := Tokenize(query)
+    if len(tokens) == 0 || len(idx.docs) == 0 {
+        return []*model.SearchHit{}, 0, nil
+    }
+
+    n := float64(len(idx.docs))
+    avgLength := float64(idx.totalLength) / n
+    scores := make(map[string]float64)
+
+    seen := make(map[string]bool)
+    for _, token := range tokens {
+        if seen[token] {
+            continue
+        }
+        seen[token] = true
+
+        keys := idx.postings[token]
+        if len(keys) == 0 {
+            continue
+        }
+        df := float64(len(keys))
+        idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+        for key := range keys {
+            d := idx.docs[key]
+            if docType != "" && d.doc.Type != docType {
+                continue
+            }
+            tf := float64(d.terms[token])
+            norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(d.length)/avgLength))
+            scores[key] += idf * norm
+        }
+    }
+
+    type scored struct {
+        doc   *model.SearchDocument
+        score float64
+    }
+    results := make([]scored, 0, len(scores))
+    for key, score := range scores {
+        results = append(results, scored{doc: &idx.docs[key].doc, score: score})
+    }
+    sort.Slice(results, func(i, j int) bool {
+        if results[i].score != results[j].score {
+            return results[i].score > results[j].score
+        }
+        return results[i].doc.CreatedAt.After(results[j].doc.CreatedAt)
+    })
+
+    total := int64(len(results))
+    offset := (page - 1) * limit
+    if offset >= len(results) {
+        return []*model.SearchHit{}, total, nil
+    }
+    end := offset + limit
+    if end > len(results) {
+        end = len(results)
+    }
+
+    // 只为当前页生成高亮片段
+    hits := make([]*model.SearchHit, 0, end-offset)
+    for _, r := range results[offset:end] {
+        hits = append(hits, &model.SearchHit{
+            Type:      r.doc.Type,
+            ID:        r.doc.ID,
+            PostID:    r.doc.PostID,
+            Title:     r.doc.Title,
+            Snippet:   Highlight(r.doc.Content, query),
+            Score:     math.Round(r.score*1000) / 1000,
+            CreatedAt: r.doc.CreatedAt,
+        })
+    }
+    return hits, total, nil
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "math"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+const (
+    postMatchSQL = `SELECT 'post' AS type, p.id AS id, p.id AS post_id, p.title AS title, p.content AS content,
+    MATCH(p.title, p.content) AGAINST (@q IN NATURAL LANGUAGE MODE) AS score, p.created_at AS created_at
+FROM posts p
+WHERE p.status = 'published' AND p.hidden = FALSE AND p.deleted_at IS NULL
+    AND MATCH(p.title, p.content) AGAINST (@q IN NATURAL LANGUAGE MODE)`
+
+    commentMatchSQL = `SELECT 'comment' AS type, c.id AS id, c.post_id AS post_id, p.title AS title, c.content AS content,
+    MATCH(c.content) AGAINST (@q IN NATURAL LANGUAGE MODE) AS score, c.created_at AS created_at
+FROM comments c JOIN posts p ON p.id = c.post_id
+WHERE p.status = 'published' AND p.hidden = FALSE AND p.deleted_at IS NULL
+    AND c.deleted = FALSE AND c.deleted_at IS NULL AND MATCH(c.content) AGAINST (@q IN NATURAL LANGUAGE MODE)`
+)
+
+// mysqlFullTextIndex 基于MySQL FULLTEXT索引（ngram分词）的搜索实现，
+// 数据直接来自posts和comments表，因此写入操作无需处理
+type mysqlFullTextIndex struct {
+    db *gorm.DB
+}
+
+// NewMySQLFullTextIndex 创建MySQL全文搜索索引
+func NewMySQLFullTextIndex(db *gorm.DB) repository.SearchIndex {
+    return &mysqlFullTextIndex{db: db}
+}
+
+// Index 由MySQL维护索引，无需处理
+func (idx *mysqlFullTextIndex) Index(doc *model.SearchDocument) error {
+    return nil
+}
+
+// Remove 由MySQL维护索引，无需处理
+func (idx *mysqlFullTextIndex) Remove(docType string, id uint) error {
+    return nil
+}
+
+// RemovePost 由MySQL维护索引，无需处理
+func (idx *mysqlFullTextIndex) RemovePost(postID uint) error {
+    return nil
+}
+
+// Search 使用MATCH ... AGAINST按相关度排序搜索
+func (idx *mysqlFullTextIndex) Search(query, docType string, page, limit int) ([]*model.SearchHit, int64, error) {
+    var sql string
+    switch docType {
+    case model.SearchTypePost:
+        sql = postMatchSQL
+    case model.SearchTypeComment:
+        sql = commentMatchSQL
+    default:
+        sql = postMatchSQL + "\nUNION ALL\n" + commentMatchSQL
+    }
+
+    args := map[string]interface{}{"q": query}
+
+    var total int64
+    if err := idx.db.Raw("SELECT COUNT(*) FROM ("+sql+") AS matched", args).Scan(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var rows []struct {
+        Type      string
+        ID        uint
+        PostID    uint
+        Title     string
+        Content   string
+        Score     float64
+        CreatedAt time.Time
+    }
+    args["limit"] = limit
+    args["offset"] = (page - 1) * limit
+    err := idx.db.Raw("SELECT * FROM ("+sql+") AS matched ORDER BY score DESC, created_at DESC LIMIT @limit OFFSET @offset", args).
+        Scan(&rows).Error
+    if err != nil {
+        return nil, 0, err
+    }
+
+    hits := make([]*model.SearchHit, 0, len(rows))
+    for _, row := range rows {
+        hits = append(hits, &model.SearchHit{
+            Type:      row.Type,
+            ID:        row.ID,
+            PostID:    row.PostID,
+            Title:     row.Title,
+            Snippet:   Highlight(row.Content, query),
+            Score:     math.Round(row.Score*1000) / 1000,
+            CreatedAt: row.CreatedAt,
+        })
+    }
+    return hits, total, nil
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+    "sort"
+    "strings"
+    "unicode"
+)
+
+// Tokenize 将文本切分为索引词：
+// 英文和数字按单词切分并转为小写，中文等表意文字按相邻两字切分（单字时保留单字）
+func Tokenize(text string) []string {
+    var tokens []string
+    var word []rune
+    var han []rune
+
+    flushWord := func() {
+        if len(word) > 0 {
+            tokens = append(tokens, string(word))
+            word = word[:0]
+        }
+    }
+    flushHan := func() {
+        switch {
+        case len(han) == 1:
+            tokens = append(tokens, string(han))
+        case len(han) > 1:
+            for i := 0; i+1 < len(han); i++ {
+                tokens = append(tokens, string(han[i:i+2]))
+            }
+        }
+        han = han[:0]
+    }
+
+    for _, r := range text {
+        switch {
+        case unicode.Is(unicode.Han, r):
+            flushWord()
+            han = append(han, r)
+        case unicode.IsLetter(r) || unicode.IsDigit(r):
+            flushHan()
+            word = append(word, unicode.ToLower(r))
+        default:
+            flushWord()
+            flushHan()
+        }
+    }
+    flushWord()
+    flushHan()
+
+    return tokens
+}
+
+// queryTerms 获取用于高亮的查询词：原始关键词以及切分后的索引词，按长度降序
+func queryTerms(query string) []string {
+    seen := make(map[string]bool)
+    var terms []string
+    add := func(term string) {
+        term = strings.ToLower(strings.TrimSpace(term))
+        if term != "" && !seen[term] {
+            seen[term] = true
+            terms = append(terms, term)
+        }
+    }
+
+    for _, field := range strings.Fields(query) {
+        add(field)
+    }
+    for _, token := range Tokenize(query) {
+        add(token)
+    }
+
+    // 优先匹配较长的词
+    sort.SliceStable(terms, func(i, j int) bool {
+        return len([]rune(terms[i])) > len([]rune(terms[j]))
+    })
+    return terms
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "io"
+    "os"
+    "path"
+    "path/filepath"
+    "strings"
+)
+
+// localStorage 本地文件系统存储
+type localStorage struct {
+    dir     string
+    baseURL string
+}
+
+// NewLocalStorage 创建本地文件系统存储，文件保存在dir下，通过baseURL访问
+func NewLocalStorage(dir, baseURL string) (repository.FileStorage, error) {
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        return nil, err
+    }
+    return &localStorage{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Put 保存文件，先写入临时文件再重命名，避免读到写了一半的文件
+func (s *localStorage) Put(key string, r io.Reader, size int64, contentType string) error {
+    name, err := s.path(key)
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+        return err
+    }
+
+    tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name())
+
+    if _, err := io.Copy(tmp, r); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp.Name(), name)
+}
+
+// Open 读取文件，目录等非普通文件按不存在处理
+func (s *localStorage) Open(key string) (io.ReadCloser, error) {
+    name, err := s.path(key)
+    if err != nil {
+        return nil, err
+    }
+    f, err := os.Open(name)
+    if errors.Is(err, os.ErrNotExist) {
+        return nil, errors.New("文件不存在")
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    info, err := f.Stat()
+    if err != nil {
+        f.Close()
+        return nil, err
+    }
+    if !info.Mode().IsRegular() {
+        f.Close()
+        return nil, errors.New("文件不存在")
+    }
+    return f, nil
+}
+
+// Delete 删除文件
+func (s *localStorage) Delete(key string) error {
+    name, err := s.path(key)
+    if err != nil {
+        return err
+    }
+    if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+        return err
+    }
+    return nil
+}
+
+// URL 返回文件的访问地址
+func (s *localStorage) URL(key string) string {
+    return s.baseURL + "/" + key
+}
+
+// path 将key转换为存储目录下的文件路径
+func (s *localStorage) path(key string) (string, error) {
+    if err := validateKey(key); err != nil {
+        return "", err
+    }
+    return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// validateKey 拒绝空路径、绝对路径和包含..的路径，防止访问存储目录之外的文件
+func validateKey(key string) error {
+    if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." || key == "." {
+        return errors.New("无效的文件路径")
+    }
+    return nil
+}
@@ -0,0 +1,227 @@
+package storage
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "strings"
+    "time"
+)
+
+// unsignedPayload 上传文件时不计算请求体的哈希，文件以流的方式发送
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// emptyPayloadHash 空请求体的SHA-256
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config S3兼容对象存储的连接参数
+type S3Config struct {
+    Endpoint  string // 服务地址，如 https://s3.amazonaws.com 或 http://localhost:9000
+    Region    string
+    Bucket    string
+    AccessKey string
+    SecretKey string
+    PathStyle bool   // 使用 endpoint/bucket/key 形式的地址，MinIO等自建服务通常需要开启
+    PublicURL string // 文件的对外访问地址前缀（如CDN），为空时使用对象地址
+}
+
+// s3Storage S3兼容对象存储，使用AWS Signature V4签名请求
+type s3Storage struct {
+    cfg      S3Config
+    endpoint *url.URL
+    client   *http.Client
+}
+
+// NewS3Storage 创建S3兼容对象存储
+func NewS3Storage(cfg S3Config) (repository.FileStorage, error) {
+    endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+    if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+        return nil, fmt.Errorf("无效的S3地址: %s", cfg.Endpoint)
+    }
+    if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+        return nil, errors.New("S3存储需要配置bucket和访问密钥")
+    }
+    if cfg.Region == "" {
+        cfg.Region = "us-east-1"
+    }
+    cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+
+    return &s3Storage{
+        cfg:      cfg,
+        endpoint: endpoint,
+        client:   &http.Client{Timeout: time.Minute},
+    }, nil
+}
+
+// Put 上传文件
+func (s *s3Storage) Put(key string, r io.Reader, size int64, contentType string) error {
+    req, err := s.newRequest(http.MethodPut, key, r)
+    if err != nil {
+        return err
+    }
+    req.ContentLength = size
+    req.Header.Set("Content-Type", contentType)
+
+    resp, err := s.do(req, unsignedPayload)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+    return checkResponse(resp)
+}
+
+// Open 下载文件
+func (s *s3Storage) Open(key string) (io.ReadCloser, error) {
+    req, err := s.newRequest(http.MethodGet, key, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    resp, err := s.do(req, emptyPayloadHash)
+    if err != nil {
+        return nil, err
+    }
+    if resp.StatusCode == http.StatusNotFound {
+        resp.Body.Close()
+        return nil, errors.New("文件不存在")
+    }
+    if err := checkResponse(resp); err != nil {
+        resp.Body.Close()
+        return nil, err
+    }
+    return resp.Body, nil
+}
+
+// Delete 删除文件
+func (s *s3Storage) Delete(key string) error {
+    req, err := s.newRequest(http.MethodDelete, key, nil)
+    if err != nil {
+        return err
+    }
+
+    resp, err := s.do(req, emptyPayloadHash)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode == http.StatusNotFound {
+        return nil
+    }
+    return checkResponse(resp)
+}
+
+// URL 返回文件的访问地址
+func (s *s3Storage) URL(key string) string {
+    if s.cfg.PublicURL != "" {
+        return s.cfg.PublicURL + "/" + uriEncode(key, false)
+    }
+    return s.objectURL(key).String()
+}
+
+// objectURL 对象的请求地址
+func (s *s3Storage) objectURL(key string) *url.URL {
+    u := *s.endpoint
+    path, rawPath := "/"+key, "/"+uriEncode(key, false)
+    if s.cfg.PathStyle {
+        path, rawPath = "/"+s.cfg.Bucket+path, "/"+uriEncode(s.cfg.Bucket, true)+rawPath
+    } else {
+        u.Host = s.cfg.Bucket + "." + u.Host
+    }
+    u.Path = strings.TrimRight(s.endpoint.Path, "/") + path
+    u.RawPath = strings.TrimRight(s.endpoint.EscapedPath(), "/") + rawPath
+    return &u
+}
+
+// newRequest 创建对象请求
+func (s *s3Storage) newRequest(method, key string, body io.Reader) (*http.Request, error) {
+    if err := validateKey(key); err != nil {
+        return nil, err
+    }
+    return http.NewRequest(method, s.objectURL(key).String(), body)
+}
+
+// do 签名并发送请求
+func (s *s3Storage) do(req *http.Request, payloadHash string) (*http.Response, error) {
+    s.sign(req, payloadHash, time.Now().UTC())
+    return s.client.Do(req)
+}
+
+// sign 按AWS Signature V4为请求添加Authorization头
+func (s *s3Storage) sign(req *http.Request, payloadHash string, now time.Time) {
+    amzDate := now.Format("20060102T150405Z")
+    date := now.Format("20060102")
+    req.Header.Set("X-Amz-Date", amzDate)
+    req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+    const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+    canonicalHeaders := "host:" + req.URL.Host + "\n" +
+        "x-amz-content-sha256:" + payloadHash + "\n" +
+        "x-amz-date:" + amzDate + "\n"
+    canonicalRequest := strings.Join([]string{
+        req.Method,
+        req.URL.EscapedPath(),
+        req.URL.RawQuery,
+        canonicalHeaders,
+        signedHeaders,
+        payloadHash,
+    }, "\n")
+
+    scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+    stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex(canonicalRequest)
+
+    signingKey := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+    signingKey = hmacSHA256(signingKey, s.cfg.Region)
+    signingKey = hmacSHA256(signingKey, "s3")
+    signingKey = hmacSHA256(signingKey, "aws4_request")
+    signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+    req.Header.Set("Authorization", fmt.Sprintf(
+        "AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+        s.cfg.AccessKey, scope, signedHeaders, signature,
+    ))
+}
+
+// checkResponse 非2xx响应转换为错误
+func checkResponse(resp *http.Response) error {
+    if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+        return nil
+    }
+    body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+    return fmt.Errorf("对象存储返回 %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// uriEncode 按S3签名规则编码路径，只保留字母、数字和-._~，encodeSlash为false时保留/
+func uriEncode(s string, encodeSlash bool) string {
+    var b strings.Builder
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        switch {
+        case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+            b.WriteByte(c)
+        case c == '/' && !encodeSlash:
+            b.WriteByte(c)
+        default:
+            fmt.Fprintf(&b, "%%%02X", c)
+        }
+    }
+    return b.String()
+}
+
+// hashHex 计算SHA-256并返回十六进制字符串
+func hashHex(s string) string {
+    sum := sha256.Sum256([]byte(s))
+    return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 计算HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+    mac := hmac.New(sha256.New, key)
+    mac.Write([]byte(data))
+    return mac.Sum(nil)
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "crypto/rand"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+// 个人访问令牌的限制
+const (
+    maxAPITokensPerUser    = 20          // 每个用户最多持有的令牌数量
+    maxAPITokenNameLength  = 100         // 名称最多字符数
+    maxAPITokenExpiresDays = 365         // 有效期上限（天），0表示永不过期
+    apiTokenRandomBytes    = 32          // 令牌中随机部分的字节数
+    apiTokenTouchInterval  = time.Minute // 最近使用时间的更新间隔，避免每个请求都写数据库
+)
+
+// errInvalidAPIToken 令牌不存在、已过期或所属用户不可用，不区分具体原因
+var errInvalidAPIToken = errors.New("无效的令牌")
+
+// APITokenUseCase 个人访问令牌用例接口
+type APITokenUseCase interface {
+    // Create 创建令牌，返回令牌信息和明文令牌，明文只在创建时返回一次
+    // mfa表示当前登录令牌是否通过了两步验证，启用了两步验证的用户必须通过才能创建
+    Create(userID uint, mfa bool, name string, scopes []string, expiresInDays int) (*model.APIToken, string, error)
+    List(userID uint) ([]*model.APIToken, error)
+    Revoke(userID, tokenID uint) error
+    // Authenticate 验证请求携带的令牌，返回令牌和所属用户
+    Authenticate(token string) (*model.APIToken, *model.User, error)
+    PurgeExpired(now time.Time) (int, error)
+}
+
+type apiTokenUseCase struct {
+    tokenRepo repository.APITokenRepository
+    userRepo  repository.UserRepository
+    totpRepo  repository.UserTOTPRepository
+}
+
+// NewAPITokenUseCase 创建个人访问令牌用例
+func NewAPITokenUseCase(tokenRepo repository.APITokenRepository, userRepo repository.UserRepository, totpRepo repository.UserTOTPRepository) APITokenUseCase {
+    return &apiTokenUseCase{
+        tokenRepo: tokenRepo,
+        userRepo:  userRepo,
+        totpRepo:  totpRepo,
+    }
+}
+
+// Create 创建个人访问令牌
+func (uc *apiTokenUseCase) Create(userID uint, mfa bool, name string, scopes []string, expiresInDays int) (*model.APIToken, string, error) {
+    if err := requireMFA(uc.totpRepo, userID, mfa); err != nil {
+        return nil, "", err
+    }
+
+    name = strings.TrimSpace(name)
+    if name == "" || utf8.RuneCountInString(name) > maxAPITokenNameLength {
+        return nil, "", fmt.Errorf("令牌名称不能为空，且不能超过%d个字符", maxAPITokenNameLength)
+    }
+    if expiresInDays < 0 || expiresInDays > maxAPITokenExpiresDays {
+        return nil, "", fmt.Errorf("有效期必须在0到%d天之间，0表示永不过期", maxAPITokenExpiresDays)
+    }
+    scopes, err := normalizeScopes(scopes)
+    if err != nil {
+        return nil, "", err
+    }
+
+    existing, err := uc.tokenRepo.ListByUser(userID)
+    if err != nil {
+        return nil, "", err
+    }
+    if len(existing) >= maxAPITokensPerUser {
+        return nil, "", fmt.Errorf("最多只能创建%d个令牌，请先删除不再使用的令牌", maxAPITokensPerUser)
+    }
+
+    b := make([]byte, apiTokenRandomBytes)
+    if _, err := rand.Read(b); err != nil {
+        return nil, "", err
+    }
+    plain := model.APITokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+    token := &model.APIToken{
+        UserID:    userID,
+        Name:      name,
+        TokenHash: hashAccountToken(plain),
+        Hint:      plain[len(plain)-4:],
+        Scopes:    scopes,
+    }
+    if expiresInDays > 0 {
+        expiresAt := time.Now().Add(time.Duration(expiresInDays) * 24 * time.Hour)
+        token.ExpiresAt = &expiresAt
+    }
+    if err := uc.tokenRepo.Create(token); err != nil {
+        return nil, "", err
+    }
+    return token, plain, nil
+}
+
+// List 获取用户的个人访问令牌
+func (uc *apiTokenUseCase) List(userID uint) ([]*model.APIToken, error) {
+    return uc.tokenRepo.ListByUser(userID)
+}
+
+// Revoke 删除个人访问令牌，立即失效
+func (uc *apiTokenUseCase) Revoke(userID, tokenID uint) error {
+    return uc.tokenRepo.Delete(userID, tokenID)
+}
+
+// Authenticate 验证个人访问令牌，所属用户已删除或被封禁时令牌无效
+func (uc *apiTokenUseCase) Authenticate(plain string) (*model.APIToken, *model.User, error) {
+    if !strings.HasPrefix(plain, model.APITokenPrefix) {
+        return nil, nil, errInvalidAPIToken
+    }
+
+    token, err := uc.tokenRepo.GetByHash(hashAccountToken(plain))
+    if err != nil {
+        return nil, nil, errInvalidAPIToken
+    }
+    now := time.Now()
+    if token.Expired(now) {
+        return nil, nil, errInvalidAPIToken
+    }
+
+    user, err := uc.userRepo.GetByID(token.UserID)
+    if err != nil || user.Banned {
+        return nil, nil, errInvalidAPIToken
+    }
+
+    if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenTouchInterval {
+        if err := uc.tokenRepo.Touch(token.ID, now); err != nil {
+            logger.Error("更新令牌使用时间失败", err)
+        }
+        token.LastUsedAt = &now
+    }
+    return token, user, nil
+}
+
+// PurgeExpired 删除已过期的个人访问令牌
+func (uc *apiTokenUseCase) PurgeExpired(now time.Time) (int, error) {
+    count, err := uc.tokenRepo.DeleteExpired(now)
+    return int(count), err
+}
+
+// normalizeScopes 检查权限范围并去重，保持提交时的顺序
+func normalizeScopes(scopes []string) ([]string, error) {
+    result := make([]string, 0, len(scopes))
+    seen := make(map[string]bool, len(scopes))
+    for _, scope := range scopes {
+        scope = strings.TrimSpace(scope)
+        if !model.IsValidScope(scope) {
+            return nil, fmt.Errorf("无效的权限范围: %s，可选值为 %s", scope, strings.Join(model.APITokenScopes, "、"))
+        }
+        if !seen[scope] {
+            seen[scope] = true
+            result = append(result, scope)
+        }
+    }
+    if len(result) == 0 {
+        return nil, errors.New("至少需要一个权限范围")
+    }
+    return result, nil
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "strings"
+)
+
+// CategoryUseCase 分类用例接口
+type CategoryUseCase interface {
+    GetAll() ([]*model.CategoryCount, error)
+    Create(userID uint, name, description string) (*model.Category, error)
+    Update(userID, id uint, name, description string) error
+    Delete(userID, id uint) error
+}
+
+type categoryUseCase struct {
+    categoryRepo repository.CategoryRepository
+    userRepo     repository.UserRepository
+}
+
+// NewCategoryUseCase 创建分类用例
+func NewCategoryUseCase(categoryRepo repository.CategoryRepository, userRepo repository.UserRepository) CategoryUseCase {
+    return &categoryUseCase{
+        categoryRepo: categoryRepo,
+        userRepo:     userRepo,
+    }
+}
+
+// GetAll 获取所有分类及文章数
+func (uc *categoryUseCase) GetAll() ([]*model.CategoryCount, error) {
+    return uc.categoryRepo.GetAllWithCount()
+}
+
+// Create 创建分类
+func (uc *categoryUseCase) Create(userID uint, name, description string) (*model.Category, error) {
+    if err := uc.requireManager(userID); err != nil {
+        return nil, err
+    }
+
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return nil, errors.New("分类名不能为空")
+    }
+    if existing, _ := uc.categoryRepo.GetByName(name); existing != nil {
+        return nil, errors.New("分类已存在")
+    }
+
+    category := &model.Category{Name: name, Description: description}
+    if err := uc.categoryRepo.Create(category); err != nil {
+        return nil, err
+    }
+    return category, nil
+}
+
+// Update 更新分类
+func (uc *categoryUseCase) Update(userID, id uint, name, description string) error {
+    if err := uc.requireManager(userID); err != nil {
+        return err
+    }
+
+    category, err := uc.categoryRepo.GetByID(id)
+    if err != nil {
+        return err
+    }
+
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return errors.New("分类名不能为空")
+    }
+    if existing, _ := uc.categoryRepo.GetByName(name); existing != nil && existing.ID != id {
+        return errors.New("分类已存在")
+    }
+
+    category.Name = name
+    category.Description = description
+    return uc.categoryRepo.Update(category)
+}
+
+// Delete 删除分类
+func (uc *categoryUseCase) Delete(userID, id uint) error {
+    if err := uc.requireManager(userID); err != nil {
+        return err
+    }
+
+    if _, err := uc.categoryRepo.GetByID(id); err != nil {
+        return err
+    }
+
+    return uc.categoryRepo.Delete(id)
+}
+
+// requireManager 检查操作者是否可以管理分类
+func (uc *categoryUseCase) requireManager(userID uint) error {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanManageCategories(user) {
+        return errors.New("没有权限管理分类")
+    }
+    return nil
+}
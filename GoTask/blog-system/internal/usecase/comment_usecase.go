@@ -2,83 +2,330 @@ package usecase
 
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/sanitize"
     "errors"
+    "strings"
 )
 
+// maxCommentDepth 评论最多嵌套的层数（顶层评论为第1层）
+const maxCommentDepth = 5
+
 // CommentUseCase 评论用例接口
 type CommentUseCase interface {
-    Create(content string, userID, postID uint) error
+    Create(content string, userID, postID uint, parentID *uint) error
     GetByID(id uint) (*model.Comment, error)
     GetByPostID(postID uint, page, limit int) ([]*model.Comment, int64, error)
+    GetByPostIDAfter(postID uint, after *repository.Cursor, limit int) ([]*model.Comment, *repository.Cursor, error)
+    GetTree(postID uint, page, limit int) ([]*model.Comment, int64, error)
     Delete(id, userID uint) error
+    GetTrash(userID uint, page, limit int) ([]*model.Comment, int64, error)
+    Restore(id, userID uint) error
 }
 
 type commentUseCase struct {
     commentRepo repository.CommentRepository
     postRepo    repository.PostRepository
     userRepo    repository.UserRepository
+    searchIndex repository.SearchIndex
 }
 
 // NewCommentUseCase 创建评论用例
-func NewCommentUseCase(commentRepo repository.CommentRepository, postRepo repository.PostRepository, userRepo repository.UserRepository) CommentUseCase {
+func NewCommentUseCase(commentRepo repository.CommentRepository, postRepo repository.PostRepository, userRepo repository.UserRepository, searchIndex repository.SearchIndex) CommentUseCase {
     return &commentUseCase{
         commentRepo: commentRepo,
         postRepo:    postRepo,
         userRepo:    userRepo,
+        searchIndex: searchIndex,
     }
 }
 
-// Create 创建评论
-func (uc *commentUseCase) Create(content string, userID, postID uint) error {
+// Create 创建评论，parentID不为空时作为回复
+func (uc *commentUseCase) Create(content string, userID, postID uint, parentID *uint) error {
     // 检查用户是否存在
-    _, err := uc.userRepo.GetByID(userID)
+    user, err := uc.userRepo.GetByID(userID)
     if err != nil {
         return errors.New("用户不存在")
     }
 
+    if err := contentCreationError(user); err != nil {
+        return err
+    }
+
     // 检查文章是否存在
-    _, err = uc.postRepo.GetByID(postID)
-    if err != nil {
+    post, err := uc.postRepo.GetByID(postID)
+    if err != nil || !post.IsPublic() {
         return errors.New("文章不存在")
     }
 
+    // 评论允许少量HTML，保存前按白名单清洗
+    content = sanitize.HTML(content)
+    if strings.TrimSpace(content) == "" {
+        return errors.New("评论内容不能为空")
+    }
+
     comment := &model.Comment{
         Content: content,
         UserID:  userID,
         PostID:  postID,
     }
 
-    return uc.commentRepo.Create(comment)
+    if parentID != nil {
+        parent, err := uc.commentRepo.GetByID(*parentID)
+        if err != nil || parent.PostID != postID {
+            return errors.New("回复的评论不存在")
+        }
+        if parent.Deleted {
+            return errors.New("不能回复已删除的评论")
+        }
+        if parent.Depth+1 >= maxCommentDepth {
+            return errors.New("评论最多嵌套5层")
+        }
+
+        comment.ParentID = &parent.ID
+        comment.Depth = parent.Depth + 1
+        comment.RootID = parent.RootID
+        if parent.ParentID == nil {
+            comment.RootID = parent.ID
+        }
+    }
+
+    if err := uc.commentRepo.Create(comment); err != nil {
+        return err
+    }
+
+    if err := uc.searchIndex.Index(commentDocument(comment, post)); err != nil {
+        logger.Error("更新搜索索引失败", err)
+    }
+    return nil
 }
 
 // GetByID 根据ID获取评论
 func (uc *commentUseCase) GetByID(id uint) (*model.Comment, error) {
-    return uc.commentRepo.GetByID(id)
+    comment, err := uc.commentRepo.GetByID(id)
+    if err != nil {
+        return nil, err
+    }
+    return hideTombstone(comment), nil
 }
 
 // GetByPostID 获取指定文章的所有评论（分页）
 func (uc *commentUseCase) GetByPostID(postID uint, page, limit int) ([]*model.Comment, int64, error) {
     // 检查文章是否存在
-    _, err := uc.postRepo.GetByID(postID)
-    if err != nil {
+    post, err := uc.postRepo.GetByID(postID)
+    if err != nil || !post.IsPublic() {
         return nil, 0, errors.New("文章不存在")
     }
 
     return uc.commentRepo.GetByPostID(postID, page, limit)
 }
 
-// Delete 删除评论
+// GetByPostIDAfter 按游标获取指定文章的评论，返回下一页的游标，没有下一页时为nil
+func (uc *commentUseCase) GetByPostIDAfter(postID uint, after *repository.Cursor, limit int) ([]*model.Comment, *repository.Cursor, error) {
+    post, err := uc.postRepo.GetByID(postID)
+    if err != nil || !post.IsPublic() {
+        return nil, nil, errors.New("文章不存在")
+    }
+
+    comments, err := uc.commentRepo.GetByPostIDAfter(postID, after, limit+1)
+    if err != nil {
+        return nil, nil, err
+    }
+    comments, next := cutPage(comments, limit, commentPosition)
+    return comments, next, nil
+}
+
+// GetTree 获取指定文章的评论树，按顶层评论分页
+func (uc *commentUseCase) GetTree(postID uint, page, limit int) ([]*model.Comment, int64, error) {
+    post, err := uc.postRepo.GetByID(postID)
+    if err != nil || !post.IsPublic() {
+        return nil, 0, errors.New("文章不存在")
+    }
+
+    roots, total, err := uc.commentRepo.GetRootsByPostID(postID, page, limit)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    rootIDs := make([]uint, 0, len(roots))
+    for _, root := range roots {
+        rootIDs = append(rootIDs, root.ID)
+    }
+
+    replies, err := uc.commentRepo.GetByRootIDs(rootIDs)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    return buildCommentTree(roots, replies), total, nil
+}
+
+// Delete 删除评论；仍有回复的评论保留为占位，避免回复失去上下文
 func (uc *commentUseCase) Delete(id, userID uint) error {
     comment, err := uc.commentRepo.GetByID(id)
     if err != nil {
         return err
     }
+    if comment.Deleted {
+        return errors.New("评论不存在")
+    }
+
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
 
-    // 检查是否是评论作者
-    if comment.UserID != userID {
+    if !policy.CanDeleteComment(user, comment) {
         return errors.New("没有权限删除此评论")
     }
 
-    return uc.commentRepo.Delete(id)
+    replies, err := uc.commentRepo.CountReplies(id)
+    if err != nil {
+        return err
+    }
+
+    if replies > 0 {
+        // 占位评论保留原内容以便恢复，展示时隐藏
+        comment.Deleted = true
+        if err := uc.commentRepo.Update(comment); err != nil {
+            return err
+        }
+    } else {
+        if err := uc.commentRepo.Delete(id); err != nil {
+            return err
+        }
+        uc.pruneTombstones(comment.ParentID)
+    }
+
+    if err := uc.searchIndex.Remove(model.SearchTypeComment, id); err != nil {
+        logger.Error("更新搜索索引失败", err)
+    }
+    return nil
+}
+
+// GetTrash 获取当前用户已删除的评论（分页）
+func (uc *commentUseCase) GetTrash(userID uint, page, limit int) ([]*model.Comment, int64, error) {
+    return uc.commentRepo.GetDeletedByUserID(userID, page, limit)
+}
+
+// Restore 恢复已删除的评论；回收站中的上级评论以占位形式一并恢复，保证回复能挂回评论树
+func (uc *commentUseCase) Restore(id, userID uint) error {
+    comment, err := uc.commentRepo.GetByIDWithDeleted(id)
+    if err != nil {
+        return err
+    }
+    if !comment.DeletedAt.Valid && !comment.Deleted {
+        return errors.New("评论不在回收站中")
+    }
+
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanDeleteComment(user, comment) {
+        return errors.New("没有权限恢复此评论")
+    }
+
+    post, err := uc.postRepo.GetByID(comment.PostID)
+    if err != nil {
+        return errors.New("文章不存在")
+    }
+
+    for parentID := comment.ParentID; parentID != nil; {
+        parent, err := uc.commentRepo.GetByIDWithDeleted(*parentID)
+        if err != nil {
+            return errors.New("父评论已被永久删除")
+        }
+        if parent.DeletedAt.Valid {
+            if err := uc.restoreAs(parent.ID, true); err != nil {
+                return err
+            }
+        }
+        parentID = parent.ParentID
+    }
+
+    if err := uc.restoreAs(id, false); err != nil {
+        return err
+    }
+
+    if post.IsPublic() {
+        comment.Deleted = false
+        if err := uc.searchIndex.Index(commentDocument(comment, post)); err != nil {
+            logger.Error("更新搜索索引失败", err)
+        }
+    }
+    return nil
+}
+
+// restoreAs 将评论移出回收站，并设置是否为占位评论
+func (uc *commentUseCase) restoreAs(id uint, tombstone bool) error {
+    comment, err := uc.commentRepo.GetByIDWithDeleted(id)
+    if err != nil {
+        return err
+    }
+    if comment.DeletedAt.Valid {
+        if err := uc.commentRepo.Restore(id); err != nil {
+            return err
+        }
+        if comment, err = uc.commentRepo.GetByID(id); err != nil {
+            return err
+        }
+    }
+    if comment.Deleted == tombstone {
+        return nil
+    }
+    comment.Deleted = tombstone
+    return uc.commentRepo.Update(comment)
+}
+
+// pruneTombstones 回复全部删除后，逐级清理不再有回复的占位评论
+func (uc *commentUseCase) pruneTombstones(parentID *uint) {
+    for parentID != nil {
+        parent, err := uc.commentRepo.GetByID(*parentID)
+        if err != nil || !parent.Deleted {
+            return
+        }
+
+        replies, err := uc.commentRepo.CountReplies(parent.ID)
+        if err != nil || replies > 0 {
+            return
+        }
+
+        if err := uc.commentRepo.Delete(parent.ID); err != nil {
+            logger.Error("清理已删除评论失败", err)
+            return
+        }
+        parentID = parent.ParentID
+    }
+}
+
+// buildCommentTree 将回复挂到各自的父评论下，回复按时间正序排列
+func buildCommentTree(roots, replies []*model.Comment) []*model.Comment {
+    byID := make(map[uint]*model.Comment, len(roots)+len(replies))
+    for _, root := range roots {
+        byID[root.ID] = hideTombstone(root)
+    }
+    for _, reply := range replies {
+        byID[reply.ID] = hideTombstone(reply)
+    }
+
+    for _, reply := range replies {
+        if parent, ok := byID[*reply.ParentID]; ok {
+            parent.Replies = append(parent.Replies, reply)
+        }
+    }
+    return roots
+}
+
+// hideTombstone 占位评论不展示内容和作者信息
+func hideTombstone(comment *model.Comment) *model.Comment {
+    if comment.Deleted {
+        comment.Content = ""
+        comment.UserID = 0
+        comment.User = model.User{}
+    }
+    return comment
 }
\ No newline at end of file
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+    "time"
+    "unicode/utf8"
+)
+
+// 登录记录字段长度，与数据表一致
+const (
+    maxLoginSubjectLength   = 100
+    maxLoginUserAgentLength = 255
+)
+
+// LockoutPolicy 登录失败锁定策略
+type LockoutPolicy struct {
+    UsernameThreshold int           // 同一用户名连续失败多少次后锁定，0表示不按用户名锁定
+    IPThreshold       int           // 同一IP连续失败多少次后锁定，0表示不按IP锁定
+    BaseDuration      time.Duration // 首次锁定时长，此后每多失败一次翻倍
+    MaxDuration       time.Duration // 锁定时长上限
+    ResetAfter        time.Duration // 超过这段时间没有失败则重新计数
+    AttemptRetention  time.Duration // 登录记录保留时长
+}
+
+// LoginLockedError 用户名或IP被临时锁定
+type LoginLockedError struct {
+    Until time.Time // 锁定截止时间
+}
+
+// Error 实现error接口，不区分被锁定的是用户名还是IP
+func (e *LoginLockedError) Error() string {
+    return "登录失败次数过多，请稍后再试"
+}
+
+// LoginGuard 登录失败计数、临时锁定和登录审计
+type LoginGuard interface {
+    // Check 检查用户名和IP是否被锁定，被锁定时返回*LoginLockedError
+    Check(username, ip string) error
+    // Record 保存登录记录；失败时累加用户名和IP的计数，达到阈值后按指数退避锁定，成功时清除用户名的计数
+    Record(attempt *model.LoginAttempt) error
+    ListAttempts(adminID uint, username, ip string, page, limit int) ([]*model.LoginAttempt, int64, error)
+    // ListLockouts 获取锁定记录，all为true时包含尚未锁定的失败计数
+    ListLockouts(adminID uint, all bool, page, limit int) ([]*model.LoginLockout, int64, error)
+    // ClearLockout 解除锁定并清除失败计数
+    ClearLockout(adminID, id uint) error
+    // PurgeExpired 删除超过保留期的登录记录和已失效的失败计数，供调度器定期调用
+    PurgeExpired(now time.Time) (int, error)
+}
+
+type loginGuard struct {
+    attemptRepo repository.LoginAttemptRepository
+    lockoutRepo repository.LoginLockoutRepository
+    userRepo    repository.UserRepository
+    policy      LockoutPolicy
+}
+
+// NewLoginGuard 创建登录失败计数和审计用例
+func NewLoginGuard(
+    attemptRepo repository.LoginAttemptRepository,
+    lockoutRepo repository.LoginLockoutRepository,
+    userRepo repository.UserRepository,
+    lockoutPolicy LockoutPolicy,
+) LoginGuard {
+    return &loginGuard{
+        attemptRepo: attemptRepo,
+        lockoutRepo: lockoutRepo,
+        userRepo:    userRepo,
+        policy:      lockoutPolicy,
+    }
+}
+
+// Check 检查用户名和IP是否被锁定
+func (g *loginGuard) Check(username, ip string) error {
+    now := time.Now()
+    for _, key := range g.subjects(username, ip) {
+        lockout, err := g.lockoutRepo.Get(key.scope, key.subject)
+        if err != nil {
+            return err
+        }
+        if lockout != nil && lockout.IsLocked(now) {
+            return &LoginLockedError{Until: *lockout.LockedUntil}
+        }
+    }
+    return nil
+}
+
+// Record 保存登录记录并更新失败计数
+func (g *loginGuard) Record(attempt *model.LoginAttempt) error {
+    attempt.Username = truncateRunes(attempt.Username, maxLoginSubjectLength)
+    attempt.UserAgent = truncateRunes(attempt.UserAgent, maxLoginUserAgentLength)
+    if err := g.attemptRepo.Create(attempt); err != nil {
+        return err
+    }
+
+    switch attempt.Result {
+    case model.LoginResultSuccess:
+        // IP的计数不因登录成功而清除，避免攻击者用自己的账号重置计数后继续尝试其他用户名
+        if g.policy.UsernameThreshold > 0 {
+            return g.lockoutRepo.Reset(model.LockoutScopeUsername, attempt.Username)
+        }
+    case model.LoginResultFailed:
+        now := attempt.CreatedAt
+        for _, key := range g.subjects(attempt.Username, attempt.IP) {
+            lockout, err := g.lockoutRepo.AddFailure(key.scope, key.subject, now, now.Add(-g.policy.ResetAfter))
+            if err != nil {
+                return err
+            }
+            if lockout.Failures >= key.threshold {
+                if err := g.lockoutRepo.Lock(lockout.ID, now.Add(g.lockDuration(lockout.Failures-key.threshold))); err != nil {
+                    return err
+                }
+            }
+        }
+    }
+    return nil
+}
+
+// ListAttempts 管理员查询登录记录
+func (g *loginGuard) ListAttempts(adminID uint, username, ip string, page, limit int) ([]*model.LoginAttempt, int64, error) {
+    if err := g.requireAdmin(adminID); err != nil {
+        return nil, 0, err
+    }
+
+    return g.attemptRepo.List(username, ip, page, limit)
+}
+
+// ListLockouts 管理员查询锁定记录
+func (g *loginGuard) ListLockouts(adminID uint, all bool, page, limit int) ([]*model.LoginLockout, int64, error) {
+    if err := g.requireAdmin(adminID); err != nil {
+        return nil, 0, err
+    }
+
+    return g.lockoutRepo.List(!all, time.Now(), page, limit)
+}
+
+// ClearLockout 管理员解除锁定
+func (g *loginGuard) ClearLockout(adminID, id uint) error {
+    if err := g.requireAdmin(adminID); err != nil {
+        return err
+    }
+
+    if _, err := g.lockoutRepo.GetByID(id); err != nil {
+        return err
+    }
+    return g.lockoutRepo.Delete(id)
+}
+
+// PurgeExpired 删除超过保留期的登录记录和已失效的失败计数
+func (g *loginGuard) PurgeExpired(now time.Time) (int, error) {
+    attempts, err := g.attemptRepo.DeleteBefore(now.Add(-g.policy.AttemptRetention))
+    if err != nil {
+        return 0, err
+    }
+
+    lockouts, err := g.lockoutRepo.DeleteStale(now.Add(-g.policy.ResetAfter), now)
+    if err != nil {
+        return int(attempts), err
+    }
+    return int(attempts + lockouts), nil
+}
+
+// lockoutSubject 需要计数的维度及其锁定阈值
+type lockoutSubject struct {
+    scope     string
+    subject   string
+    threshold int
+}
+
+// subjects 返回启用了锁定的计数维度
+func (g *loginGuard) subjects(username, ip string) []lockoutSubject {
+    var subjects []lockoutSubject
+    if g.policy.UsernameThreshold > 0 {
+        subjects = append(subjects, lockoutSubject{model.LockoutScopeUsername, truncateRunes(username, maxLoginSubjectLength), g.policy.UsernameThreshold})
+    }
+    if g.policy.IPThreshold > 0 && ip != "" {
+        subjects = append(subjects, lockoutSubject{model.LockoutScopeIP, ip, g.policy.IPThreshold})
+    }
+    return subjects
+}
+
+// lockDuration 计算锁定时长，excess为超过阈值的失败次数，每多一次翻倍，不超过MaxDuration
+func (g *loginGuard) lockDuration(excess int) time.Duration {
+    d := g.policy.BaseDuration
+    for i := 0; i < excess && d < g.policy.MaxDuration; i++ {
+        d *= 2
+    }
+    if d > g.policy.MaxDuration {
+        d = g.policy.MaxDuration
+    }
+    return d
+}
+
+// requireAdmin 检查操作者是否为管理员
+func (g *loginGuard) requireAdmin(userID uint) error {
+    user, err := g.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanManageUsers(user) {
+        return errors.New("没有权限管理用户")
+    }
+    return nil
+}
+
+// truncateRunes 将字符串截断到不超过max字节，不会截断多字节字符
+func truncateRunes(s string, max int) string {
+    for len(s) > max {
+        _, size := utf8.DecodeLastRuneInString(s)
+        s = s[:len(s)-size]
+    }
+    return s
+}
@@ -0,0 +1,373 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/oidc"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "context"
+    "crypto/rand"
+    "encoding/base64"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+// OIDCStateTTL 从跳转到身份提供方到回调的最长时间
+const OIDCStateTTL = 10 * time.Minute
+
+// oidcUsernameMaxLength 根据外部身份生成的用户名最多字符数
+const oidcUsernameMaxLength = 32
+
+// OIDCOptions 单点登录参数
+type OIDCOptions struct {
+    // LinkByEmail 身份提供方确认过（email_verified）的邮箱与已有账号相同时，首次登录自动绑定到该账号
+    LinkByEmail bool
+}
+
+// OIDCUseCase OpenID Connect单点登录用例接口
+type OIDCUseCase interface {
+    // AuthorizationURL 生成身份提供方的授权地址和其中的state，userID不为0时表示已登录用户绑定外部身份
+    AuthorizationURL(userID uint) (authURL, state string, err error)
+    // Login 处理登录的授权回调，首次登录时创建用户；启用了两步验证时返回*MFARequiredError
+    Login(code, state, ip, userAgent string) (*auth.TokenPair, error)
+    // Link 处理绑定外部身份的授权回调
+    Link(userID uint, code, state string) (*model.UserIdentity, error)
+    ListIdentities(userID uint) ([]*model.UserIdentity, error)
+    Unlink(userID, identityID uint) error
+    PurgeExpiredStates(now time.Time) (int, error)
+}
+
+type oidcUseCase struct {
+    provider     oidc.Provider
+    identityRepo repository.UserIdentityRepository
+    stateRepo    repository.OIDCStateRepository
+    userRepo     repository.UserRepository
+    totpRepo     repository.UserTOTPRepository
+    transactor   repository.Transactor
+    jwtService   auth.JWTService
+    loginGuard   LoginGuard
+    options      OIDCOptions
+}
+
+// NewOIDCUseCase 创建单点登录用例
+func NewOIDCUseCase(
+    provider oidc.Provider,
+    identityRepo repository.UserIdentityRepository,
+    stateRepo repository.OIDCStateRepository,
+    userRepo repository.UserRepository,
+    totpRepo repository.UserTOTPRepository,
+    transactor repository.Transactor,
+    jwtService auth.JWTService,
+    loginGuard LoginGuard,
+    options OIDCOptions,
+) OIDCUseCase {
+    return &oidcUseCase{
+        provider:     provider,
+        identityRepo: identityRepo,
+        stateRepo:    stateRepo,
+        userRepo:     userRepo,
+        totpRepo:     totpRepo,
+        transactor:   transactor,
+        jwtService:   jwtService,
+        loginGuard:   loginGuard,
+        options:      options,
+    }
+}
+
+// AuthorizationURL 生成state、nonce和PKCE参数并保存，返回授权地址和state
+func (uc *oidcUseCase) AuthorizationURL(userID uint) (string, string, error) {
+    state, err := randomToken(32)
+    if err != nil {
+        return "", "", err
+    }
+    nonce, err := randomToken(32)
+    if err != nil {
+        return "", "", err
+    }
+    verifier, err := oidc.NewCodeVerifier()
+    if err != nil {
+        return "", "", err
+    }
+
+    record := &model.OIDCState{
+        StateHash:    hashAccountToken(state),
+        Nonce:        nonce,
+        CodeVerifier: verifier,
+        ExpiresAt:    time.Now().Add(OIDCStateTTL),
+    }
+    if userID != 0 {
+        record.UserID = &userID
+    }
+    if err := uc.stateRepo.Create(record); err != nil {
+        return "", "", err
+    }
+
+    authURL, err := uc.provider.AuthCodeURL(context.Background(), state, nonce, oidc.CodeChallenge(verifier))
+    if err != nil {
+        logger.Error("生成单点登录地址失败", err)
+        return "", "", errors.New("身份提供方暂时不可用，请稍后再试")
+    }
+    return authURL, state, nil
+}
+
+// Login 处理登录回调，外部身份未绑定时按邮箱绑定已有账号或创建新用户
+func (uc *oidcUseCase) Login(code, state, ip, userAgent string) (*auth.TokenPair, error) {
+    record, identity, err := uc.exchange(code, state)
+    if err != nil {
+        return nil, err
+    }
+    // 绑定用的授权请求不能用于登录
+    if record.UserID != nil {
+        return nil, errors.New("登录请求无效或已过期，请重新登录")
+    }
+
+    user, err := uc.resolveUser(identity)
+    if err != nil {
+        return nil, err
+    }
+
+    attempt := &model.LoginAttempt{Username: user.Username, UserID: &user.ID, IP: ip, UserAgent: userAgent}
+    if user.Banned {
+        attempt.Result = model.LoginResultBanned
+        uc.recordLogin(attempt)
+        return nil, errors.New("账号已被封禁")
+    }
+
+    // 外部身份只代替密码，启用了两步验证的用户仍需提交验证码
+    tokens, err := issueLoginTokens(uc.totpRepo, uc.jwtService, user)
+    var mfa *MFARequiredError
+    switch {
+    case errors.As(err, &mfa):
+        attempt.Result = model.LoginResultMFARequired
+    case err != nil:
+        return nil, err
+    default:
+        attempt.Result = model.LoginResultSuccess
+    }
+    uc.recordLogin(attempt)
+    return tokens, err
+}
+
+// Link 将外部身份绑定到当前用户
+func (uc *oidcUseCase) Link(userID uint, code, state string) (*model.UserIdentity, error) {
+    record, identity, err := uc.exchange(code, state)
+    if err != nil {
+        return nil, err
+    }
+    // 授权请求必须由当前用户发起，防止诱导用户把攻击者的外部账号绑定到自己的账号上
+    if record.UserID == nil || *record.UserID != userID {
+        return nil, errors.New("登录请求无效或已过期，请重新登录")
+    }
+
+    existing, err := uc.identityRepo.GetBySubject(identity.Issuer, identity.Subject)
+    if err != nil {
+        return nil, err
+    }
+    if existing != nil {
+        if existing.UserID == userID {
+            return existing, nil
+        }
+        return nil, errors.New("该外部账号已绑定其他用户")
+    }
+
+    linked := &model.UserIdentity{UserID: userID, Issuer: identity.Issuer, Subject: identity.Subject, Email: identity.Email}
+    if err := uc.identityRepo.Create(linked); err != nil {
+        return nil, err
+    }
+    return linked, nil
+}
+
+// ListIdentities 获取用户绑定的外部身份
+func (uc *oidcUseCase) ListIdentities(userID uint) ([]*model.UserIdentity, error) {
+    return uc.identityRepo.ListByUser(userID)
+}
+
+// Unlink 解除绑定；没有设置密码的用户不能解除最后一个外部身份，否则将无法登录
+func (uc *oidcUseCase) Unlink(userID, identityID uint) error {
+    identities, err := uc.identityRepo.ListByUser(userID)
+    if err != nil {
+        return err
+    }
+
+    var target *model.UserIdentity
+    for _, identity := range identities {
+        if identity.ID == identityID {
+            target = identity
+        }
+    }
+    if target == nil {
+        return errors.New("外部账号不存在")
+    }
+
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+    if user.Password == model.UnusablePassword && len(identities) == 1 {
+        return errors.New("请先通过找回密码设置密码，再解除绑定")
+    }
+    return uc.identityRepo.Delete(target.ID)
+}
+
+// PurgeExpiredStates 删除过期的授权请求，供调度器定期调用
+func (uc *oidcUseCase) PurgeExpiredStates(now time.Time) (int, error) {
+    count, err := uc.stateRepo.DeleteExpired(now)
+    return int(count), err
+}
+
+// exchange 校验state并用授权码换取外部身份，每个state只能使用一次
+func (uc *oidcUseCase) exchange(code, state string) (*model.OIDCState, *oidc.Identity, error) {
+    record, err := uc.stateRepo.Consume(hashAccountToken(state), time.Now())
+    if err != nil {
+        return nil, nil, err
+    }
+
+    identity, err := uc.provider.Exchange(context.Background(), code, record.CodeVerifier, record.Nonce)
+    if err != nil {
+        logger.Error("单点登录校验失败", err)
+        return nil, nil, errors.New("单点登录失败，请重新登录")
+    }
+    return record, identity, nil
+}
+
+// resolveUser 查找外部身份绑定的用户，未绑定时按邮箱绑定已有账号或创建新用户
+func (uc *oidcUseCase) resolveUser(identity *oidc.Identity) (*model.User, error) {
+    existing, err := uc.identityRepo.GetBySubject(identity.Issuer, identity.Subject)
+    if err != nil {
+        return nil, err
+    }
+    if existing != nil {
+        user, err := uc.userRepo.GetByID(existing.UserID)
+        if err == nil {
+            if err := uc.identityRepo.Touch(existing.ID, identity.Email); err != nil {
+                logger.Error("更新外部身份失败", err)
+            }
+            return user, nil
+        }
+        // 用户已被删除，绑定随之失效，按首次登录处理
+        if err := uc.identityRepo.Delete(existing.ID); err != nil {
+            return nil, err
+        }
+    }
+
+    if identity.Email == "" {
+        return nil, errors.New("身份提供方没有返回邮箱，无法创建账号")
+    }
+    if model.IsReservedUser("", identity.Email) {
+        return nil, errors.New("用户名或邮箱不可用")
+    }
+
+    if user, err := uc.userRepo.GetByEmail(identity.Email); err == nil {
+        // 只信任身份提供方确认过的邮箱，否则任何人都能在身份提供方填写他人邮箱接管账号
+        if !uc.options.LinkByEmail || !identity.EmailVerified {
+            return nil, errors.New("该邮箱已注册，请使用密码登录后绑定外部账号")
+        }
+        linked := &model.UserIdentity{UserID: user.ID, Issuer: identity.Issuer, Subject: identity.Subject, Email: identity.Email}
+        if err := uc.identityRepo.Create(linked); err != nil {
+            return nil, err
+        }
+        return user, nil
+    }
+
+    return uc.createUser(identity)
+}
+
+// createUser 根据外部身份创建用户并绑定，用户没有密码，需要时可以通过找回密码设置
+func (uc *oidcUseCase) createUser(identity *oidc.Identity) (*model.User, error) {
+    username, err := uc.availableUsername(identity)
+    if err != nil {
+        return nil, err
+    }
+
+    user := &model.User{
+        Username: username,
+        Password: model.UnusablePassword,
+        Email:    identity.Email,
+        Role:     model.RoleUser,
+        Verified: identity.EmailVerified,
+    }
+    err = uc.transactor.WithinTransaction(func(repos repository.TxRepositories) error {
+        if err := repos.Users.Create(user); err != nil {
+            return err
+        }
+        return repos.Identities.Create(&model.UserIdentity{
+            UserID:  user.ID,
+            Issuer:  identity.Issuer,
+            Subject: identity.Subject,
+            Email:   identity.Email,
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+    return user, nil
+}
+
+// availableUsername 依次尝试preferred_username、name和邮箱前缀，已被占用时追加数字
+func (uc *oidcUseCase) availableUsername(identity *oidc.Identity) (string, error) {
+    base := ""
+    for _, candidate := range []string{identity.PreferredUsername, identity.Name, strings.Split(identity.Email, "@")[0]} {
+        if base = sanitizeUsername(candidate); base != "" {
+            break
+        }
+    }
+    if base == "" {
+        base = "user"
+    }
+
+    for i := 1; i <= 20; i++ {
+        username := base
+        if i > 1 {
+            username = fmt.Sprintf("%s%d", base, i)
+        }
+        if model.IsReservedUser(username, "") {
+            continue
+        }
+        if _, err := uc.userRepo.GetByUsername(username); err != nil {
+            return username, nil
+        }
+    }
+
+    // 常见名字都被占用时追加随机后缀
+    suffix := make([]byte, 3)
+    if _, err := rand.Read(suffix); err != nil {
+        return "", err
+    }
+    return base + "-" + hex.EncodeToString(suffix), nil
+}
+
+// recordLogin 保存登录记录，失败时只记录日志，不影响登录结果
+func (uc *oidcUseCase) recordLogin(attempt *model.LoginAttempt) {
+    if err := uc.loginGuard.Record(attempt); err != nil {
+        logger.Error("保存登录记录失败", err)
+    }
+}
+
+// sanitizeUsername 去掉首尾空白和控制字符，并截断到最大长度
+func sanitizeUsername(name string) string {
+    name = strings.Map(func(r rune) rune {
+        if r < 0x20 || r == 0x7f {
+            return -1
+        }
+        return r
+    }, strings.TrimSpace(name))
+    for utf8.RuneCountInString(name) > oidcUsernameMaxLength {
+        _, size := utf8.DecodeLastRuneInString(name)
+        name = name[:len(name)-size]
+    }
+    return strings.TrimSpace(name)
+}
+
+// randomToken 生成n字节的随机令牌（base64url编码）
+func randomToken(n int) (string, error) {
+    b := make([]byte, n)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+)
+
+// cutPage 截取游标分页的一页，items需要按limit+1条查询，多出的一条说明还有下一页
+// 返回的游标指向本页最后一条记录，没有下一页时为nil
+func cutPage[T any](items []T, limit int, position func(T) repository.Cursor) ([]T, *repository.Cursor) {
+    if len(items) <= limit {
+        return items, nil
+    }
+    items = items[:limit]
+    next := position(items[limit-1])
+    return items, &next
+}
+
+// postPosition 文章在列表中的位置
+func postPosition(p *model.Post) repository.Cursor {
+    return repository.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+}
+
+// commentPosition 评论在列表中的位置
+func commentPosition(c *model.Comment) repository.Cursor {
+    return repository.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+}
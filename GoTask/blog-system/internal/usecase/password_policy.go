@@ -0,0 +1,43 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
+    "fmt"
+    "strings"
+    "unicode/utf8"
+)
+
+// PasswordPolicy 设置密码时的校验规则，只在注册、修改和重置密码时检查，已有密码不受影响
+type PasswordPolicy struct {
+    MinLength int                     // 最少字符数
+    MaxLength int                     // 最多字符数
+    Breached  *auth.BreachedPasswords // 已泄露的密码列表，为nil时不检查
+}
+
+// WeakPasswordError 密码不符合规则
+type WeakPasswordError struct {
+    Reason string
+}
+
+// Error 实现error接口
+func (e *WeakPasswordError) Error() string {
+    return e.Reason
+}
+
+// Validate 检查密码是否符合规则，不符合时返回*WeakPasswordError
+func (p PasswordPolicy) Validate(password, username, email string) error {
+    length := utf8.RuneCountInString(password)
+    if length < p.MinLength {
+        return &WeakPasswordError{Reason: fmt.Sprintf("密码至少需要%d个字符", p.MinLength)}
+    }
+    if p.MaxLength > 0 && length > p.MaxLength {
+        return &WeakPasswordError{Reason: fmt.Sprintf("密码不能超过%d个字符", p.MaxLength)}
+    }
+    if strings.EqualFold(password, username) || strings.EqualFold(password, email) {
+        return &WeakPasswordError{Reason: "密码不能与用户名或邮箱相同"}
+    }
+    if p.Breached.Contains(password) {
+        return &WeakPasswordError{Reason: "该密码已出现在公开泄露的密码库中，请更换密码"}
+    }
+    return nil
+}
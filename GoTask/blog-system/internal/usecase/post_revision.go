@@ -0,0 +1,143 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/diff"
+    "errors"
+)
+
+// 版本对比的上限，超过时不再逐行比较，避免两个差别很大的长版本耗尽内存
+const (
+    maxDiffLines = 10000 // 去掉相同首尾后两个版本的总行数
+    maxDiffEdits = 1000  // 编辑距离（新增和删除的行数之和）
+)
+
+// ListRevisions 获取文章的修订记录（分页，按版本号倒序）
+func (uc *postUseCase) ListRevisions(postID, userID uint, page, limit int) ([]*model.PostRevision, int64, error) {
+    if _, err := uc.revisionViewer(postID, userID); err != nil {
+        return nil, 0, err
+    }
+    return uc.revisionRepo.GetByPostID(postID, page, limit)
+}
+
+// GetRevision 获取指定版本的修订记录
+func (uc *postUseCase) GetRevision(postID, userID uint, revision int) (*model.PostRevision, error) {
+    if _, err := uc.revisionViewer(postID, userID); err != nil {
+        return nil, err
+    }
+    return uc.revisionRepo.GetByRevision(postID, revision)
+}
+
+// DiffRevisions 比较两个修订版本的内容（逐行）
+func (uc *postUseCase) DiffRevisions(postID, userID uint, from, to int) (*model.RevisionDiff, error) {
+    if _, err := uc.revisionViewer(postID, userID); err != nil {
+        return nil, err
+    }
+
+    fromRev, err := uc.revisionRepo.GetByRevision(postID, from)
+    if err != nil {
+        return nil, err
+    }
+    toRev, err := uc.revisionRepo.GetByRevision(postID, to)
+    if err != nil {
+        return nil, err
+    }
+
+    lines, replaced := diff.Bounded(fromRev.Content, toRev.Content, maxDiffLines, maxDiffEdits)
+    added, removed := diff.Stats(lines)
+    return &model.RevisionDiff{
+        PostID:    postID,
+        From:      from,
+        To:        to,
+        FromTitle: fromRev.Title,
+        ToTitle:   toRev.Title,
+        Added:     added,
+        Removed:   removed,
+        Replaced:  replaced,
+        Lines:     lines,
+    }, nil
+}
+
+// RestoreRevision 将文章恢复到指定版本（仅作者），恢复本身会产生新的修订记录
+func (uc *postUseCase) RestoreRevision(postID, userID uint, revision int) error {
+    post, err := uc.postRepo.GetByID(postID)
+    if err != nil {
+        return err
+    }
+
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanUpdatePost(user, post) {
+        return errors.New("没有权限恢复此文章")
+    }
+
+    rev, err := uc.revisionRepo.GetByRevision(postID, revision)
+    if err != nil {
+        return err
+    }
+    if rev.Title == post.Title && rev.Content == post.Content {
+        return nil
+    }
+
+    wasPublic := post.IsPublic()
+    post.Title = rev.Title
+    post.Content = rev.Content
+    post.ContentHTML = renderContent(rev.Content)
+    err = uc.transactor.WithinTransaction(func(repos repository.TxRepositories) error {
+        if err := repos.Posts.Update(post); err != nil {
+            return err
+        }
+        return recordRevision(repos.Revisions, post, userID)
+    })
+    if err != nil {
+        return err
+    }
+
+    uc.syncSearchIndex(post, wasPublic)
+    return nil
+}
+
+// revisionViewer 检查用户是否可以查看文章的修订记录
+func (uc *postUseCase) revisionViewer(postID, userID uint) (*model.Post, error) {
+    post, err := uc.postRepo.GetByID(postID)
+    if err != nil {
+        return nil, err
+    }
+
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return nil, errors.New("用户不存在")
+    }
+
+    if !policy.CanViewRevisions(user, post) {
+        return nil, errors.New("没有权限查看修订记录")
+    }
+    return post, nil
+}
+
+// recordRevision 以文章当前的标题和内容写入一条新的修订记录
+func recordRevision(revisions repository.PostRevisionRepository, post *model.Post, userID uint) error {
+    return revisions.Create(&model.PostRevision{
+        PostID:  post.ID,
+        Title:   post.Title,
+        Content: post.Content,
+        UserID:  userID,
+    })
+}
+
+// ensureBaseline 文章还没有修订记录时（如修订功能上线前创建的文章），先保存修改前的版本
+func ensureBaseline(revisions repository.PostRevisionRepository, before *model.Post) error {
+    _, total, err := revisions.GetByPostID(before.ID, 1, 1)
+    if err != nil {
+        return err
+    }
+    if total > 0 {
+        return nil
+    }
+    return recordRevision(revisions, before, before.UserID)
+}
@@ -2,58 +2,176 @@ package usecase
 
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/markdown"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/sanitize"
     "errors"
+    "strings"
+    "time"
 )
 
+// 标签限制
+const (
+    maxTagsPerPost = 10
+    maxTagLength   = 32
+)
+
+// PostInput 创建或更新文章的参数
+type PostInput struct {
+    Title       string
+    Content     string
+    Tags        []string   // 标签名，不存在时自动创建；更新时为nil表示不修改
+    CategoryIDs []uint     // 分类ID，必须已存在；更新时为nil表示不修改
+    Status      string     // 文章状态；创建时为空表示直接发布，更新时为空表示不修改
+    PublishAt   *time.Time // 定时发布时间，仅在状态为scheduled时使用
+}
+
 // PostUseCase 文章用例接口
 type PostUseCase interface {
-    Create(title, content string, userID uint) error
-    GetByID(id uint) (*model.Post, error)
-    GetAll(page, limit int) ([]*model.Post, int64, error)
+    Create(userID uint, input PostInput) error
+    GetByID(id, viewerID uint) (*model.Post, error)
+    GetAll(filter repository.PostFilter, page, limit int) ([]*model.Post, int64, error)
     GetByUserID(userID uint, page, limit int) ([]*model.Post, int64, error)
-    Update(id, userID uint, title, content string) error
+    GetAllAfter(filter repository.PostFilter, after *repository.Cursor, limit int) ([]*model.Post, *repository.Cursor, error)
+    GetByUserIDAfter(userID uint, after *repository.Cursor, limit int) ([]*model.Post, *repository.Cursor, error)
+    GetMine(userID uint, status string, page, limit int) ([]*model.Post, int64, error)
+    Update(id, userID uint, input PostInput) error
     Delete(id, userID uint) error
+    GetTrash(userID uint, page, limit int) ([]*model.Post, int64, error)
+    Restore(id, userID uint) error
+    SetHidden(id, userID uint, hidden bool) error
+    PublishDuePosts(now time.Time) (int, error)
+    ListRevisions(postID, userID uint, page, limit int) ([]*model.PostRevision, int64, error)
+    GetRevision(postID, userID uint, revision int) (*model.PostRevision, error)
+    DiffRevisions(postID, userID uint, from, to int) (*model.RevisionDiff, error)
+    RestoreRevision(postID, userID uint, revision int) error
+    Search(query, docType string, page, limit int) ([]*model.SearchHit, int64, error)
+    RebuildSearchIndex() error
+    RenderMissingContentHTML() (int, error)
 }
 
 type postUseCase struct {
-    postRepo repository.PostRepository
-    userRepo repository.UserRepository
+    postRepo     repository.PostRepository
+    userRepo     repository.UserRepository
+    commentRepo  repository.CommentRepository
+    tagRepo      repository.TagRepository
+    categoryRepo repository.CategoryRepository
+    revisionRepo repository.PostRevisionRepository
+    transactor   repository.Transactor
+    searchIndex  repository.SearchIndex
 }
 
 // NewPostUseCase 创建文章用例
-func NewPostUseCase(postRepo repository.PostRepository, userRepo repository.UserRepository) PostUseCase {
+func NewPostUseCase(
+    postRepo repository.PostRepository,
+    userRepo repository.UserRepository,
+    commentRepo repository.CommentRepository,
+    tagRepo repository.TagRepository,
+    categoryRepo repository.CategoryRepository,
+    revisionRepo repository.PostRevisionRepository,
+    transactor repository.Transactor,
+    searchIndex repository.SearchIndex,
+) PostUseCase {
     return &postUseCase{
-        postRepo: postRepo,
-        userRepo: userRepo,
+        postRepo:     postRepo,
+        userRepo:     userRepo,
+        commentRepo:  commentRepo,
+        tagRepo:      tagRepo,
+        categoryRepo: categoryRepo,
+        revisionRepo: revisionRepo,
+        transactor:   transactor,
+        searchIndex:  searchIndex,
     }
 }
 
 // Create 创建文章
-func (uc *postUseCase) Create(title, content string, userID uint) error {
+func (uc *postUseCase) Create(userID uint, input PostInput) error {
     // 检查用户是否存在
-    _, err := uc.userRepo.GetByID(userID)
+    user, err := uc.userRepo.GetByID(userID)
     if err != nil {
         return errors.New("用户不存在")
     }
 
+    if err := contentCreationError(user); err != nil {
+        return err
+    }
+
+    // 先校验标签，避免请求被拒绝时留下新建的标签
+    tagNames, err := normalizeTags(input.Tags)
+    if err != nil {
+        return err
+    }
+
     post := &model.Post{
-        Title:   title,
-        Content: content,
-        UserID:  userID,
+        Title:       input.Title,
+        Content:     input.Content,
+        ContentHTML: renderContent(input.Content),
+        UserID:      userID,
+    }
+
+    status := input.Status
+    if status == "" {
+        status = model.PostStatusPublished
+    }
+    if status == model.PostStatusArchived {
+        return errors.New("新文章不能直接归档")
+    }
+    if err := applyStatus(post, status, input.PublishAt, time.Now()); err != nil {
+        return err
+    }
+
+    // 文章、标签、分类关联和首个修订版本在同一个事务中写入
+    err = uc.transactor.WithinTransaction(func(repos repository.TxRepositories) error {
+        tagIDs, err := resolveTags(repos.Tags, tagNames)
+        if err != nil {
+            return err
+        }
+        categoryIDs, err := resolveCategories(repos.Categories, input.CategoryIDs)
+        if err != nil {
+            return err
+        }
+
+        if err := repos.Posts.Create(post); err != nil {
+            return err
+        }
+        if err := setAssociations(repos, post.ID, tagIDs, categoryIDs); err != nil {
+            return err
+        }
+        return recordRevision(repos.Revisions, post, userID)
+    })
+    if err != nil {
+        return err
     }
 
-    return uc.postRepo.Create(post)
+    uc.syncSearchIndex(post, false)
+    return nil
 }
 
-// GetByID 根据ID获取文章
-func (uc *postUseCase) GetByID(id uint) (*model.Post, error) {
-    return uc.postRepo.GetByID(id)
+// GetByID 根据ID获取文章，未发布的文章只有作者本人可见（viewerID为0表示匿名访问）
+func (uc *postUseCase) GetByID(id, viewerID uint) (*model.Post, error) {
+    post, err := uc.postRepo.GetByID(id)
+    if err != nil {
+        return nil, err
+    }
+
+    // 被隐藏的文章对外不可见
+    if post.Hidden {
+        return nil, errors.New("文章不存在")
+    }
+
+    if post.Status != model.PostStatusPublished && (viewerID == 0 || post.UserID != viewerID) {
+        return nil, errors.New("文章不存在")
+    }
+
+    return post, nil
 }
 
-// GetAll 获取所有文章（分页）
-func (uc *postUseCase) GetAll(page, limit int) ([]*model.Post, int64, error) {
-    return uc.postRepo.GetAll(page, limit)
+// GetAll 获取所有文章（分页），可按标签和分类筛选
+func (uc *postUseCase) GetAll(filter repository.PostFilter, page, limit int) ([]*model.Post, int64, error) {
+    filter.Tag = normalizeTagName(filter.Tag)
+    return uc.postRepo.GetAll(filter, page, limit)
 }
 
 // GetByUserID 获取指定用户的所有文章（分页）
@@ -67,22 +185,108 @@ func (uc *postUseCase) GetByUserID(userID uint, page, limit int) ([]*model.Post,
     return uc.postRepo.GetByUserID(userID, page, limit)
 }
 
+// GetAllAfter 按游标获取文章，返回下一页的游标，没有下一页时为nil
+func (uc *postUseCase) GetAllAfter(filter repository.PostFilter, after *repository.Cursor, limit int) ([]*model.Post, *repository.Cursor, error) {
+    filter.Tag = normalizeTagName(filter.Tag)
+    posts, err := uc.postRepo.GetAllAfter(filter, after, limit+1)
+    if err != nil {
+        return nil, nil, err
+    }
+    posts, next := cutPage(posts, limit, postPosition)
+    return posts, next, nil
+}
+
+// GetByUserIDAfter 按游标获取指定用户的文章
+func (uc *postUseCase) GetByUserIDAfter(userID uint, after *repository.Cursor, limit int) ([]*model.Post, *repository.Cursor, error) {
+    if _, err := uc.userRepo.GetByID(userID); err != nil {
+        return nil, nil, errors.New("用户不存在")
+    }
+
+    posts, err := uc.postRepo.GetByUserIDAfter(userID, after, limit+1)
+    if err != nil {
+        return nil, nil, err
+    }
+    posts, next := cutPage(posts, limit, postPosition)
+    return posts, next, nil
+}
+
+// GetMine 获取当前用户自己的文章（包含草稿、定时和归档），status为空表示全部
+func (uc *postUseCase) GetMine(userID uint, status string, page, limit int) ([]*model.Post, int64, error) {
+    if status != "" && !model.IsValidPostStatus(status) {
+        return nil, 0, errors.New("无效的文章状态")
+    }
+    return uc.postRepo.GetByAuthor(userID, status, page, limit)
+}
+
 // Update 更新文章
-func (uc *postUseCase) Update(id, userID uint, title, content string) error {
+func (uc *postUseCase) Update(id, userID uint, input PostInput) error {
     post, err := uc.postRepo.GetByID(id)
     if err != nil {
         return err
     }
 
-    // 检查是否是文章作者
-    if post.UserID != userID {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanUpdatePost(user, post) {
         return errors.New("没有权限修改此文章")
     }
 
-    post.Title = title
-    post.Content = content
+    tagNames, err := normalizeTags(input.Tags)
+    if err != nil {
+        return err
+    }
+
+    wasPublic := post.IsPublic()
+    before := *post
+    changed := post.Title != input.Title || post.Content != input.Content
+
+    post.Title = input.Title
+    post.Content = input.Content
+    post.ContentHTML = renderContent(input.Content)
+
+    if input.Status != "" {
+        if err := applyStatus(post, input.Status, input.PublishAt, time.Now()); err != nil {
+            return err
+        }
+    }
+
+    err = uc.transactor.WithinTransaction(func(repos repository.TxRepositories) error {
+        tagIDs, err := resolveTags(repos.Tags, tagNames)
+        if err != nil {
+            return err
+        }
+        categoryIDs, err := resolveCategories(repos.Categories, input.CategoryIDs)
+        if err != nil {
+            return err
+        }
+
+        if changed {
+            if err := ensureBaseline(repos.Revisions, &before); err != nil {
+                return err
+            }
+        }
+        if err := repos.Posts.Update(post); err != nil {
+            return err
+        }
+        if err := setAssociations(repos, post.ID, tagIDs, categoryIDs); err != nil {
+            return err
+        }
+
+        // 标题或内容有变化时记录新的修订版本
+        if changed {
+            return recordRevision(repos.Revisions, post, userID)
+        }
+        return nil
+    })
+    if err != nil {
+        return err
+    }
 
-    return uc.postRepo.Update(post)
+    uc.syncSearchIndex(post, wasPublic)
+    return nil
 }
 
 // Delete 删除文章
@@ -92,10 +296,330 @@ func (uc *postUseCase) Delete(id, userID uint) error {
         return err
     }
 
-    // 检查是否是文章作者
-    if post.UserID != userID {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanDeletePost(user, post) {
         return errors.New("没有权限删除此文章")
     }
 
-    return uc.postRepo.Delete(id)
+    if err := uc.postRepo.Delete(id); err != nil {
+        return err
+    }
+
+    if err := uc.searchIndex.RemovePost(id); err != nil {
+        logger.Error("更新搜索索引失败", err)
+    }
+    return nil
+}
+
+// GetTrash 获取当前用户回收站中的文章（分页）
+func (uc *postUseCase) GetTrash(userID uint, page, limit int) ([]*model.Post, int64, error) {
+    return uc.postRepo.GetDeletedByAuthor(userID, page, limit)
+}
+
+// Restore 从回收站恢复文章，评论、标签和修订记录随文章一起恢复
+func (uc *postUseCase) Restore(id, userID uint) error {
+    post, err := uc.postRepo.GetDeletedByID(id)
+    if err != nil {
+        return err
+    }
+
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanRestorePost(user, post) {
+        return errors.New("没有权限恢复此文章")
+    }
+
+    if err := uc.postRepo.Restore(id); err != nil {
+        return err
+    }
+
+    uc.syncSearchIndex(post, false)
+    return nil
+}
+
+// SetHidden 隐藏或恢复文章（版主操作）
+func (uc *postUseCase) SetHidden(id, userID uint, hidden bool) error {
+    post, err := uc.postRepo.GetByID(id)
+    if err != nil {
+        return err
+    }
+
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanHidePost(user, post) {
+        return errors.New("没有权限隐藏此文章")
+    }
+
+    wasPublic := post.IsPublic()
+    post.Hidden = hidden
+    if err := uc.postRepo.Update(post); err != nil {
+        return err
+    }
+
+    // 隐藏的文章及其评论不出现在搜索结果中
+    uc.syncSearchIndex(post, wasPublic)
+    return nil
+}
+
+// PublishDuePosts 发布所有到期的定时文章，返回发布的数量
+func (uc *postUseCase) PublishDuePosts(now time.Time) (int, error) {
+    const batchSize = 100
+    count := 0
+    for {
+        posts, err := uc.postRepo.GetDueScheduled(now, batchSize)
+        if err != nil {
+            return count, err
+        }
+        for _, post := range posts {
+            post.Status = model.PostStatusPublished
+            if err := uc.postRepo.Update(post); err != nil {
+                return count, err
+            }
+            uc.syncSearchIndex(post, false)
+            count++
+        }
+        if len(posts) < batchSize {
+            return count, nil
+        }
+    }
+}
+
+// Search 全文搜索文章和评论
+func (uc *postUseCase) Search(query, docType string, page, limit int) ([]*model.SearchHit, int64, error) {
+    return uc.searchIndex.Search(query, docType, page, limit)
+}
+
+// RebuildSearchIndex 从数据库重建搜索索引，内存索引在启动时调用
+func (uc *postUseCase) RebuildSearchIndex() error {
+    const batchSize = 100
+    for page := 1; ; page++ {
+        posts, _, err := uc.postRepo.GetAll(repository.PostFilter{}, page, batchSize)
+        if err != nil {
+            return err
+        }
+        for _, post := range posts {
+            if err := uc.indexPostWithComments(post); err != nil {
+                return err
+            }
+        }
+        if len(posts) < batchSize {
+            return nil
+        }
+    }
+}
+
+// RenderMissingContentHTML 为还没有content_html的文章（升级前创建的）生成HTML，启动时调用
+func (uc *postUseCase) RenderMissingContentHTML() (int, error) {
+    const batchSize = 100
+    rendered := 0
+    for {
+        posts, err := uc.postRepo.GetWithoutContentHTML(batchSize)
+        if err != nil {
+            return rendered, err
+        }
+        for _, post := range posts {
+            if err := uc.postRepo.SetContentHTML(post.ID, renderContent(post.Content)); err != nil {
+                return rendered, err
+            }
+            rendered++
+        }
+        if len(posts) < batchSize {
+            return rendered, nil
+        }
+    }
+}
+
+// renderContent 将Markdown内容渲染为清洗后的HTML
+func renderContent(content string) string {
+    return sanitize.HTML(markdown.Render(content))
+}
+
+// normalizeTags 规范化并去重标签名，校验长度和数量；names为nil时返回nil
+func normalizeTags(names []string) ([]string, error) {
+    if names == nil {
+        return nil, nil
+    }
+
+    seen := make(map[string]bool)
+    result := []string{}
+    for _, raw := range names {
+        name := normalizeTagName(raw)
+        if name == "" || seen[name] {
+            continue
+        }
+        if len([]rune(name)) > maxTagLength {
+            return nil, errors.New("标签长度不能超过32个字符")
+        }
+        seen[name] = true
+        result = append(result, name)
+    }
+
+    if len(result) > maxTagsPerPost {
+        return nil, errors.New("每篇文章最多10个标签")
+    }
+    return result, nil
+}
+
+// resolveTags 获取已规范化的标签的ID，不存在的标签自动创建；names为nil时返回nil
+func resolveTags(tags repository.TagRepository, names []string) ([]uint, error) {
+    if names == nil {
+        return nil, nil
+    }
+
+    ids := make([]uint, 0, len(names))
+    for _, name := range names {
+        tag, err := tags.GetByName(name)
+        if err != nil {
+            tag = &model.Tag{Name: name}
+            if err := tags.Create(tag); err != nil {
+                return nil, err
+            }
+        }
+        ids = append(ids, tag.ID)
+    }
+    return ids, nil
+}
+
+// resolveCategories 校验分类是否存在；ids为nil时返回nil
+func resolveCategories(categories repository.CategoryRepository, ids []uint) ([]uint, error) {
+    if ids == nil {
+        return nil, nil
+    }
+
+    seen := make(map[uint]bool)
+    result := []uint{}
+    for _, id := range ids {
+        if seen[id] {
+            continue
+        }
+        seen[id] = true
+
+        if _, err := categories.GetByID(id); err != nil {
+            return nil, err
+        }
+        result = append(result, id)
+    }
+    return result, nil
+}
+
+// setAssociations 保存文章的标签和分类，参数为nil时不修改
+func setAssociations(repos repository.TxRepositories, postID uint, tagIDs, categoryIDs []uint) error {
+    if tagIDs != nil {
+        if err := repos.Tags.SetPostTags(postID, tagIDs); err != nil {
+            return err
+        }
+    }
+    if categoryIDs != nil {
+        if err := repos.Categories.SetPostCategories(postID, categoryIDs); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// normalizeTagName 标签名统一去除首尾空白并转为小写
+func normalizeTagName(name string) string {
+    return strings.ToLower(strings.TrimSpace(name))
+}
+
+// applyStatus 校验并设置文章状态和发布时间
+func applyStatus(post *model.Post, status string, publishAt *time.Time, now time.Time) error {
+    switch status {
+    case model.PostStatusDraft:
+        post.PublishAt = nil
+    case model.PostStatusPublished:
+        // 已发布的文章保留原发布时间
+        if post.Status != model.PostStatusPublished || post.PublishAt == nil {
+            post.PublishAt = &now
+        }
+    case model.PostStatusScheduled:
+        if publishAt == nil || !publishAt.After(now) {
+            return errors.New("定时发布时间必须晚于当前时间")
+        }
+        t := *publishAt
+        post.PublishAt = &t
+    case model.PostStatusArchived:
+        if post.Status != model.PostStatusPublished && post.Status != model.PostStatusArchived {
+            return errors.New("只有已发布的文章可以归档")
+        }
+    default:
+        return errors.New("无效的文章状态")
+    }
+
+    post.Status = status
+    return nil
+}
+
+// syncSearchIndex 根据文章当前是否公开更新搜索索引，失败时只记录日志
+func (uc *postUseCase) syncSearchIndex(post *model.Post, wasPublic bool) {
+    var err error
+    switch {
+    case !post.IsPublic():
+        // 未发布、归档或隐藏的文章及其评论不出现在搜索结果中
+        err = uc.searchIndex.RemovePost(post.ID)
+    case wasPublic:
+        err = uc.searchIndex.Index(postDocument(post))
+    default:
+        err = uc.indexPostWithComments(post)
+    }
+    if err != nil {
+        logger.Error("更新搜索索引失败", err)
+    }
+}
+
+// indexPostWithComments 将文章及其所有评论写入搜索索引
+func (uc *postUseCase) indexPostWithComments(post *model.Post) error {
+    if err := uc.searchIndex.Index(postDocument(post)); err != nil {
+        return err
+    }
+
+    const batchSize = 100
+    for page := 1; ; page++ {
+        comments, _, err := uc.commentRepo.GetByPostID(post.ID, page, batchSize)
+        if err != nil {
+            return err
+        }
+        for _, comment := range comments {
+            if err := uc.searchIndex.Index(commentDocument(comment, post)); err != nil {
+                return err
+            }
+        }
+        if len(comments) < batchSize {
+            return nil
+        }
+    }
+}
+
+// postDocument 构造文章的搜索文档
+func postDocument(post *model.Post) *model.SearchDocument {
+    return &model.SearchDocument{
+        Type:      model.SearchTypePost,
+        ID:        post.ID,
+        PostID:    post.ID,
+        Title:     post.Title,
+        Content:   post.Content,
+        CreatedAt: post.CreatedAt,
+    }
+}
+
+// commentDocument 构造评论的搜索文档
+func commentDocument(comment *model.Comment, post *model.Post) *model.SearchDocument {
+    return &model.SearchDocument{
+        Type:      model.SearchTypeComment,
+        ID:        comment.ID,
+        PostID:    post.ID,
+        Title:     post.Title,
+        Content:   comment.Content,
+        CreatedAt: comment.CreatedAt,
+    }
 }
\ No newline at end of file
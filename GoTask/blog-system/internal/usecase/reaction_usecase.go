@@ -0,0 +1,207 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+)
+
+// 表情回应和收藏的错误，处理器据此选择状态码
+var (
+    ErrInvalidReaction = errors.New("不支持的表情")
+    ErrPostNotFound    = errors.New("文章不存在")
+    ErrCommentNotFound = errors.New("评论不存在")
+)
+
+// ReactionUseCase 表情回应和收藏用例
+// 添加和取消都是幂等的：重复添加或取消不存在的回应不会报错，计数也不会重复变化
+type ReactionUseCase interface {
+    // Emojis 可用的表情
+    Emojis() []string
+    // React 回应文章或评论，返回回应后的统计
+    React(userID uint, targetType string, targetID uint, emoji string) (*model.ReactionSummary, error)
+    // Unreact 取消回应，表情已不在可用集合中时也可以取消
+    Unreact(userID uint, targetType string, targetID uint, emoji string) (*model.ReactionSummary, error)
+    // Summary 文章或评论的回应统计，viewerID为0时不返回本人的回应
+    Summary(targetType string, targetID, viewerID uint) (*model.ReactionSummary, error)
+    // Bookmark 收藏文章，返回文章的收藏数
+    Bookmark(userID, postID uint) (int64, error)
+    // Unbookmark 取消收藏，文章已不公开时也可以取消
+    Unbookmark(userID, postID uint) (int64, error)
+    // ListBookmarks 当前用户收藏的公开文章（分页），按收藏时间倒序
+    ListBookmarks(userID uint, page, limit int) ([]*model.Post, int64, error)
+}
+
+type reactionUseCase struct {
+    reactionRepo repository.ReactionRepository
+    bookmarkRepo repository.BookmarkRepository
+    postRepo     repository.PostRepository
+    commentRepo  repository.CommentRepository
+    userRepo     repository.UserRepository
+    emojis       []string
+}
+
+// NewReactionUseCase 创建表情回应和收藏用例，emojis为可用的表情
+func NewReactionUseCase(reactionRepo repository.ReactionRepository, bookmarkRepo repository.BookmarkRepository, postRepo repository.PostRepository,
+    commentRepo repository.CommentRepository, userRepo repository.UserRepository, emojis []string) ReactionUseCase {
+    return &reactionUseCase{
+        reactionRepo: reactionRepo,
+        bookmarkRepo: bookmarkRepo,
+        postRepo:     postRepo,
+        commentRepo:  commentRepo,
+        userRepo:     userRepo,
+        emojis:       emojis,
+    }
+}
+
+// Emojis 可用的表情
+func (uc *reactionUseCase) Emojis() []string {
+    return uc.emojis
+}
+
+// React 回应文章或评论
+func (uc *reactionUseCase) React(userID uint, targetType string, targetID uint, emoji string) (*model.ReactionSummary, error) {
+    if !uc.isAllowed(emoji) {
+        return nil, ErrInvalidReaction
+    }
+    if err := uc.checkUser(userID); err != nil {
+        return nil, err
+    }
+    if err := uc.checkTarget(targetType, targetID); err != nil {
+        return nil, err
+    }
+
+    reaction := &model.Reaction{
+        UserID:     userID,
+        TargetType: targetType,
+        TargetID:   targetID,
+        Emoji:      emoji,
+    }
+    if _, err := uc.reactionRepo.Add(reaction); err != nil {
+        return nil, err
+    }
+    return uc.summary(targetType, targetID, userID)
+}
+
+// Unreact 取消回应，目标已不公开或已删除时也可以取消
+func (uc *reactionUseCase) Unreact(userID uint, targetType string, targetID uint, emoji string) (*model.ReactionSummary, error) {
+    if emoji == "" || len(emoji) > model.MaxReactionEmojiBytes {
+        return nil, ErrInvalidReaction
+    }
+
+    if _, err := uc.reactionRepo.Remove(userID, targetType, targetID, emoji); err != nil {
+        return nil, err
+    }
+    return uc.summary(targetType, targetID, userID)
+}
+
+// Summary 文章或评论的回应统计
+func (uc *reactionUseCase) Summary(targetType string, targetID, viewerID uint) (*model.ReactionSummary, error) {
+    if err := uc.checkTarget(targetType, targetID); err != nil {
+        return nil, err
+    }
+    return uc.summary(targetType, targetID, viewerID)
+}
+
+// Bookmark 收藏文章
+func (uc *reactionUseCase) Bookmark(userID, postID uint) (int64, error) {
+    if err := uc.checkUser(userID); err != nil {
+        return 0, err
+    }
+    if err := uc.checkTarget(model.ReactionTargetPost, postID); err != nil {
+        return 0, err
+    }
+
+    if _, err := uc.bookmarkRepo.Add(&model.Bookmark{UserID: userID, PostID: postID}); err != nil {
+        return 0, err
+    }
+    return uc.bookmarkCount(postID)
+}
+
+// Unbookmark 取消收藏
+func (uc *reactionUseCase) Unbookmark(userID, postID uint) (int64, error) {
+    if _, err := uc.bookmarkRepo.Remove(userID, postID); err != nil {
+        return 0, err
+    }
+    return uc.bookmarkCount(postID)
+}
+
+// ListBookmarks 当前用户收藏的公开文章
+func (uc *reactionUseCase) ListBookmarks(userID uint, page, limit int) ([]*model.Post, int64, error) {
+    return uc.bookmarkRepo.ListPosts(userID, page, limit)
+}
+
+// isAllowed 表情是否在可用集合中
+func (uc *reactionUseCase) isAllowed(emoji string) bool {
+    for _, allowed := range uc.emojis {
+        if emoji == allowed {
+            return true
+        }
+    }
+    return false
+}
+
+// checkUser 被封禁的用户不能添加回应和收藏，取消不受限制
+func (uc *reactionUseCase) checkUser(userID uint) error {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+    if !policy.CanReact(user) {
+        return errors.New("账号已被封禁")
+    }
+    return nil
+}
+
+// checkTarget 只能回应公开的文章和公开文章下未删除的评论
+func (uc *reactionUseCase) checkTarget(targetType string, targetID uint) error {
+    switch targetType {
+    case model.ReactionTargetPost:
+        post, err := uc.postRepo.GetByID(targetID)
+        if err != nil || !post.IsPublic() {
+            return ErrPostNotFound
+        }
+    case model.ReactionTargetComment:
+        comment, err := uc.commentRepo.GetByID(targetID)
+        if err != nil || comment.Deleted {
+            return ErrCommentNotFound
+        }
+        post, err := uc.postRepo.GetByID(comment.PostID)
+        if err != nil || !post.IsPublic() {
+            return ErrCommentNotFound
+        }
+    default:
+        return errors.New("不支持的回应对象: " + targetType)
+    }
+    return nil
+}
+
+// summary 统计目标的回应，viewerID不为0时附带本人的回应
+func (uc *reactionUseCase) summary(targetType string, targetID, viewerID uint) (*model.ReactionSummary, error) {
+    counts, err := uc.reactionRepo.CountByTarget(targetType, targetID)
+    if err != nil {
+        return nil, err
+    }
+
+    summary := &model.ReactionSummary{Counts: counts, Mine: []string{}}
+    for _, count := range counts {
+        summary.Total += count
+    }
+    if viewerID != 0 {
+        if summary.Mine, err = uc.reactionRepo.ListByUser(viewerID, targetType, targetID); err != nil {
+            return nil, err
+        }
+    }
+    return summary, nil
+}
+
+// bookmarkCount 文章当前的收藏数
+func (uc *reactionUseCase) bookmarkCount(postID uint) (int64, error) {
+    post, err := uc.postRepo.GetByID(postID)
+    if err != nil {
+        // 文章已进入回收站时不再返回计数
+        return 0, nil
+    }
+    return post.BookmarkCount, nil
+}
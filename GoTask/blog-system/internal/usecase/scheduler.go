@@ -0,0 +1,67 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "fmt"
+    "time"
+)
+
+// ScheduledTask 调度任务，返回本次处理的记录数
+type ScheduledTask func(now time.Time) (int, error)
+
+// Scheduler 后台调度器，按固定间隔执行任务（如发布定时文章、清理回收站）
+type Scheduler struct {
+    name     string
+    interval time.Duration
+    task     ScheduledTask
+    stop     chan struct{}
+    done     chan struct{}
+}
+
+// NewScheduler 创建调度器，name用于日志输出
+func NewScheduler(name string, interval time.Duration, task ScheduledTask) *Scheduler {
+    return &Scheduler{
+        name:     name,
+        interval: interval,
+        task:     task,
+        stop:     make(chan struct{}),
+        done:     make(chan struct{}),
+    }
+}
+
+// Start 在后台goroutine中启动调度器，启动时立即执行一次
+func (s *Scheduler) Start() {
+    go func() {
+        defer close(s.done)
+
+        ticker := time.NewTicker(s.interval)
+        defer ticker.Stop()
+
+        s.run()
+        for {
+            select {
+            case <-ticker.C:
+                s.run()
+            case <-s.stop:
+                return
+            }
+        }
+    }()
+}
+
+// Stop 停止调度器并等待当前任务结束
+func (s *Scheduler) Stop() {
+    close(s.stop)
+    <-s.done
+}
+
+// run 执行一次任务并记录结果
+func (s *Scheduler) run() {
+    count, err := s.task(time.Now())
+    if err != nil {
+        logger.Error(s.name+"失败", err)
+    }
+    if count > 0 {
+        logger.Info(fmt.Sprintf("%s：已处理 %d 条记录", s.name, count))
+    }
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "errors"
+)
+
+// TagUseCase 标签用例接口
+type TagUseCase interface {
+    GetAll() ([]*model.Tag, error)
+    Cloud(limit int) ([]*model.TagCount, error)
+    Create(userID uint, name string) (*model.Tag, error)
+    Update(userID, id uint, name string) error
+    Delete(userID, id uint) error
+}
+
+type tagUseCase struct {
+    tagRepo  repository.TagRepository
+    userRepo repository.UserRepository
+}
+
+// NewTagUseCase 创建标签用例
+func NewTagUseCase(tagRepo repository.TagRepository, userRepo repository.UserRepository) TagUseCase {
+    return &tagUseCase{
+        tagRepo:  tagRepo,
+        userRepo: userRepo,
+    }
+}
+
+// GetAll 获取所有标签
+func (uc *tagUseCase) GetAll() ([]*model.Tag, error) {
+    return uc.tagRepo.GetAll()
+}
+
+// Cloud 获取标签云
+func (uc *tagUseCase) Cloud(limit int) ([]*model.TagCount, error) {
+    return uc.tagRepo.Cloud(limit)
+}
+
+// Create 创建标签
+func (uc *tagUseCase) Create(userID uint, name string) (*model.Tag, error) {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return nil, errors.New("用户不存在")
+    }
+
+    if err := contentCreationError(user); err != nil {
+        return nil, err
+    }
+
+    name, err = validateTagName(name)
+    if err != nil {
+        return nil, err
+    }
+
+    if existing, _ := uc.tagRepo.GetByName(name); existing != nil {
+        return nil, errors.New("标签已存在")
+    }
+
+    tag := &model.Tag{Name: name}
+    if err := uc.tagRepo.Create(tag); err != nil {
+        return nil, err
+    }
+    return tag, nil
+}
+
+// Update 修改标签名称
+func (uc *tagUseCase) Update(userID, id uint, name string) error {
+    if err := uc.requireManager(userID); err != nil {
+        return err
+    }
+
+    tag, err := uc.tagRepo.GetByID(id)
+    if err != nil {
+        return err
+    }
+
+    name, err = validateTagName(name)
+    if err != nil {
+        return err
+    }
+
+    if existing, _ := uc.tagRepo.GetByName(name); existing != nil && existing.ID != id {
+        return errors.New("标签已存在")
+    }
+
+    tag.Name = name
+    return uc.tagRepo.Update(tag)
+}
+
+// Delete 删除标签
+func (uc *tagUseCase) Delete(userID, id uint) error {
+    if err := uc.requireManager(userID); err != nil {
+        return err
+    }
+
+    if _, err := uc.tagRepo.GetByID(id); err != nil {
+        return err
+    }
+
+    return uc.tagRepo.Delete(id)
+}
+
+// requireManager 检查操作者是否可以管理标签
+func (uc *tagUseCase) requireManager(userID uint) error {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanManageTags(user) {
+        return errors.New("没有权限管理标签")
+    }
+    return nil
+}
+
+// validateTagName 规范化并校验标签名
+func validateTagName(name string) (string, error) {
+    name = normalizeTagName(name)
+    if name == "" {
+        return "", errors.New("标签名不能为空")
+    }
+    if len([]rune(name)) > maxTagLength {
+        return "", errors.New("标签长度不能超过32个字符")
+    }
+    return name, nil
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "time"
+)
+
+// TrashUseCase 回收站用例，负责永久删除超过保留期的记录
+type TrashUseCase interface {
+    PurgeExpired(now time.Time) (int, error)
+}
+
+type trashUseCase struct {
+    userRepo       repository.UserRepository
+    postRepo       repository.PostRepository
+    commentRepo    repository.CommentRepository
+    attachmentRepo repository.AttachmentRepository
+    storage        repository.FileStorage
+    retention      time.Duration
+}
+
+// NewTrashUseCase 创建回收站用例，retention为回收站记录的保留时长
+func NewTrashUseCase(
+    userRepo repository.UserRepository,
+    postRepo repository.PostRepository,
+    commentRepo repository.CommentRepository,
+    attachmentRepo repository.AttachmentRepository,
+    storage repository.FileStorage,
+    retention time.Duration,
+) TrashUseCase {
+    return &trashUseCase{
+        userRepo:       userRepo,
+        postRepo:       postRepo,
+        commentRepo:    commentRepo,
+        attachmentRepo: attachmentRepo,
+        storage:        storage,
+        retention:      retention,
+    }
+}
+
+// PurgeExpired 永久删除超过保留期的文章、评论、附件和用户，返回删除总数
+// 先删文章（连同其评论，文章中的附件解除关联），再删评论，然后删除已删除用户未关联文章的附件，
+// 最后删除名下已无内容的用户
+func (uc *trashUseCase) PurgeExpired(now time.Time) (int, error) {
+    before := now.Add(-uc.retention)
+
+    posts, err := uc.postRepo.Purge(before)
+    if err != nil {
+        return int(posts), err
+    }
+
+    comments, err := uc.commentRepo.Purge(before)
+    if err != nil {
+        return int(posts + comments), err
+    }
+
+    attachments, err := uc.purgeOrphanedAttachments(before)
+    if err != nil {
+        return int(posts+comments) + attachments, err
+    }
+
+    users, err := uc.userRepo.Purge(before)
+    return int(posts+comments+users) + attachments, err
+}
+
+// purgeOrphanedAttachments 删除before之前被删除的用户留下的、未关联文章的附件及其文件
+func (uc *trashUseCase) purgeOrphanedAttachments(before time.Time) (int, error) {
+    const batchSize = 100
+    count := 0
+    for {
+        attachments, err := uc.attachmentRepo.GetOrphaned(before, batchSize)
+        if err != nil {
+            return count, err
+        }
+        for _, attachment := range attachments {
+            if err := removeAttachment(uc.attachmentRepo, uc.storage, attachment); err != nil {
+                return count, err
+            }
+            count++
+        }
+        if len(attachments) < batchSize {
+            return count, nil
+        }
+    }
+}
@@ -0,0 +1,339 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/totp"
+    "crypto/rand"
+    "errors"
+    "strings"
+    "time"
+)
+
+// 恢复码参数：每次生成10个，每个10位（约50位熵），字符表去掉了容易混淆的0、1、l、o
+const (
+    recoveryCodeCount    = 10
+    recoveryCodeLength   = 10
+    recoveryCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+)
+
+// totpSkew 校验验证码时允许的时钟误差（时间步）
+const totpSkew = 1
+
+// ErrMFANotSatisfied 启用了两步验证的用户使用未通过两步验证的令牌执行敏感操作
+var ErrMFANotSatisfied = errors.New("该操作需要先通过两步验证，请重新登录")
+
+// MFARequiredError 密码正确但还需要提交两步验证码，Token用于提交验证码
+type MFARequiredError struct {
+    Token     string
+    ExpiresIn int64 // Token有效期（秒）
+}
+
+// Error 实现error接口
+func (e *MFARequiredError) Error() string {
+    return "需要两步验证"
+}
+
+// TwoFactorSetup 开始启用两步验证时返回的密钥
+type TwoFactorSetup struct {
+    Secret string `json:"secret"`
+    URI    string `json:"otpauth_uri"` // 验证器应用扫码使用的地址
+}
+
+// TwoFactorEnabled 启用两步验证的结果，恢复码只在此时返回一次
+type TwoFactorEnabled struct {
+    RecoveryCodes []string        `json:"recovery_codes"`
+    Tokens        *auth.TokenPair `json:"tokens"` // 之前签发的令牌已失效，使用新令牌继续访问
+}
+
+// TwoFactorStatus 两步验证状态
+type TwoFactorStatus struct {
+    Enabled                bool       `json:"enabled"`
+    EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+    RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorStatus 获取两步验证状态
+func (uc *userUseCase) TwoFactorStatus(userID uint) (*TwoFactorStatus, error) {
+    record, err := uc.totpRepo.GetByUser(userID)
+    if err != nil {
+        return nil, err
+    }
+    if !record.Enabled() {
+        return &TwoFactorStatus{}, nil
+    }
+
+    remaining, err := uc.recoveryRepo.CountUnused(userID)
+    if err != nil {
+        return nil, err
+    }
+    return &TwoFactorStatus{Enabled: true, EnabledAt: record.EnabledAt, RecoveryCodesRemaining: remaining}, nil
+}
+
+// SetupTwoFactor 生成新的TOTP密钥，用验证码确认后才会启用；重复调用时替换尚未确认的密钥
+func (uc *userUseCase) SetupTwoFactor(userID uint) (*TwoFactorSetup, error) {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return nil, errors.New("用户不存在")
+    }
+    record, err := uc.totpRepo.GetByUser(userID)
+    if err != nil {
+        return nil, err
+    }
+    if record.Enabled() {
+        return nil, errors.New("两步验证已启用")
+    }
+
+    secret, err := totp.GenerateSecret()
+    if err != nil {
+        return nil, err
+    }
+    if record == nil {
+        record = &model.UserTOTP{UserID: userID}
+    }
+    record.Secret = secret
+    record.LastStep = 0
+    if err := uc.totpRepo.Save(record); err != nil {
+        return nil, err
+    }
+
+    return &TwoFactorSetup{Secret: secret, URI: totp.URI(uc.account.TOTPIssuer, user.Username, secret)}, nil
+}
+
+// EnableTwoFactor 使用验证器应用生成的验证码确认并启用两步验证，之前签发的令牌全部失效
+func (uc *userUseCase) EnableTwoFactor(userID uint, code string) (*TwoFactorEnabled, error) {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return nil, errors.New("用户不存在")
+    }
+    record, err := uc.totpRepo.GetByUser(userID)
+    if err != nil {
+        return nil, err
+    }
+    if record == nil {
+        return nil, errors.New("请先获取两步验证密钥")
+    }
+    if record.Enabled() {
+        return nil, errors.New("两步验证已启用")
+    }
+
+    now := time.Now()
+    step, ok := totp.Validate(record.Secret, strings.TrimSpace(code), now, totpSkew)
+    if !ok {
+        return nil, errors.New("验证码错误")
+    }
+    record.EnabledAt = &now
+    record.LastStep = step
+    if err := uc.totpRepo.Save(record); err != nil {
+        return nil, err
+    }
+
+    codes, err := uc.replaceRecoveryCodes(userID)
+    if err != nil {
+        return nil, err
+    }
+
+    // 启用前签发的令牌和个人访问令牌没有通过两步验证，全部吊销
+    if err := uc.apiTokenRepo.DeleteByUser(userID); err != nil {
+        return nil, err
+    }
+    if err := uc.jwtService.RevokeUserTokens(userID); err != nil {
+        return nil, err
+    }
+    tokens, err := uc.jwtService.GenerateTokenPair(user, true)
+    if err != nil {
+        return nil, err
+    }
+    return &TwoFactorEnabled{RecoveryCodes: codes, Tokens: tokens}, nil
+}
+
+// DisableTwoFactor 关闭两步验证，需要提供密码和验证码（或恢复码）
+func (uc *userUseCase) DisableTwoFactor(userID uint, password, code string) error {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+    if !uc.hasher.Verify(password, user.Password) {
+        return errors.New("密码错误")
+    }
+
+    if err := uc.requireSecondFactor(userID, code); err != nil {
+        return err
+    }
+    if err := uc.recoveryRepo.DeleteByUser(userID); err != nil {
+        return err
+    }
+    return uc.totpRepo.DeleteByUser(userID)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，之前的恢复码全部失效
+func (uc *userUseCase) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+    if err := uc.requireSecondFactor(userID, code); err != nil {
+        return nil, err
+    }
+    return uc.replaceRecoveryCodes(userID)
+}
+
+// VerifyTwoFactor 提交两步验证码完成登录，验证失败与密码错误一样计入登录失败次数
+func (uc *userUseCase) VerifyTwoFactor(mfaToken, code, ip, userAgent string) (*auth.TokenPair, error) {
+    claims, err := uc.jwtService.ValidateMFAToken(mfaToken)
+    if err != nil {
+        return nil, errors.New("验证已过期，请重新登录")
+    }
+    attempt := &model.LoginAttempt{Username: claims.Username, UserID: &claims.UserID, IP: ip, UserAgent: userAgent}
+
+    if err := uc.loginGuard.Check(claims.Username, ip); err != nil {
+        var locked *LoginLockedError
+        if errors.As(err, &locked) {
+            attempt.Result = model.LoginResultLocked
+            uc.recordLogin(attempt)
+        }
+        return nil, err
+    }
+
+    user, err := uc.userRepo.GetByID(claims.UserID)
+    if err != nil {
+        return nil, errors.New("用户不存在")
+    }
+    if user.Banned {
+        attempt.Result = model.LoginResultBanned
+        uc.recordLogin(attempt)
+        return nil, errors.New("账号已被封禁")
+    }
+
+    if err := uc.requireSecondFactor(user.ID, code); err != nil {
+        attempt.Result = model.LoginResultFailed
+        uc.recordLogin(attempt)
+        return nil, err
+    }
+
+    // 两步验证令牌只能使用一次
+    if err := uc.jwtService.RevokeToken(claims); err != nil {
+        return nil, err
+    }
+    attempt.Result = model.LoginResultSuccess
+    uc.recordLogin(attempt)
+
+    return uc.jwtService.GenerateTokenPair(user, true)
+}
+
+// RequireMFA 检查敏感操作的令牌：启用了两步验证的用户，令牌必须在签发时通过了两步验证
+func (uc *userUseCase) RequireMFA(userID uint, satisfied bool) error {
+    return requireMFA(uc.totpRepo, userID, satisfied)
+}
+
+// requireMFA 用户启用了两步验证且当前令牌未通过两步验证时返回ErrMFANotSatisfied
+func requireMFA(totpRepo repository.UserTOTPRepository, userID uint, satisfied bool) error {
+    if satisfied {
+        return nil
+    }
+
+    record, err := totpRepo.GetByUser(userID)
+    if err != nil {
+        return err
+    }
+    if record.Enabled() {
+        return ErrMFANotSatisfied
+    }
+    return nil
+}
+
+// issueLoginTokens 用户通过密码或外部身份认证后签发令牌
+// 启用了两步验证时只返回提交验证码用的令牌（*MFARequiredError），验证通过后才签发令牌
+func issueLoginTokens(totpRepo repository.UserTOTPRepository, jwtService auth.JWTService, user *model.User) (*auth.TokenPair, error) {
+    record, err := totpRepo.GetByUser(user.ID)
+    if err != nil {
+        return nil, err
+    }
+    if record.Enabled() {
+        token, expiresIn, err := jwtService.GenerateMFAToken(user)
+        if err != nil {
+            return nil, err
+        }
+        return nil, &MFARequiredError{Token: token, ExpiresIn: expiresIn}
+    }
+    return jwtService.GenerateTokenPair(user, false)
+}
+
+// requireSecondFactor 校验TOTP验证码或恢复码，6位数字按验证码处理，其他按恢复码处理
+func (uc *userUseCase) requireSecondFactor(userID uint, code string) error {
+    record, err := uc.totpRepo.GetByUser(userID)
+    if err != nil {
+        return err
+    }
+    if !record.Enabled() {
+        return errors.New("两步验证未启用")
+    }
+
+    now := time.Now()
+    code = strings.TrimSpace(code)
+    var ok bool
+    if isTOTPCode(code) {
+        step, valid := totp.Validate(record.Secret, code, now, totpSkew)
+        if valid {
+            // 同一个验证码在有效期内不能再次使用
+            ok, err = uc.totpRepo.UseStep(userID, step)
+        }
+    } else {
+        ok, err = uc.recoveryRepo.Consume(userID, hashAccountToken(normalizeRecoveryCode(code)), now)
+    }
+    if err != nil {
+        return err
+    }
+    if !ok {
+        return errors.New("验证码错误")
+    }
+    return nil
+}
+
+// replaceRecoveryCodes 生成新的恢复码并保存其哈希，返回明文恢复码
+func (uc *userUseCase) replaceRecoveryCodes(userID uint) ([]string, error) {
+    codes := make([]string, recoveryCodeCount)
+    hashes := make([]string, recoveryCodeCount)
+    for i := range codes {
+        code, err := newRecoveryCode()
+        if err != nil {
+            return nil, err
+        }
+        codes[i] = code
+        hashes[i] = hashAccountToken(normalizeRecoveryCode(code))
+    }
+
+    if err := uc.recoveryRepo.Replace(userID, hashes); err != nil {
+        return nil, err
+    }
+    return codes, nil
+}
+
+// newRecoveryCode 生成 xxxxx-xxxxx 格式的恢复码
+func newRecoveryCode() (string, error) {
+    b := make([]byte, recoveryCodeLength)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    // 字符表长度为32，能整除256，取模不会产生偏差
+    for i := range b {
+        b[i] = recoveryCodeAlphabet[int(b[i])%len(recoveryCodeAlphabet)]
+    }
+    return string(b[:recoveryCodeLength/2]) + "-" + string(b[recoveryCodeLength/2:]), nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格和连字符
+func normalizeRecoveryCode(code string) string {
+    code = strings.ToLower(code)
+    return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// isTOTPCode 是否为6位数字验证码
+func isTOTPCode(code string) bool {
+    if len(code) != totp.Digits {
+        return false
+    }
+    for _, r := range code {
+        if r < '0' || r > '9' {
+            return false
+        }
+    }
+    return true
+}
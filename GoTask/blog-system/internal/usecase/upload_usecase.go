@@ -0,0 +1,249 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/thumbnail"
+    "bytes"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "io"
+    "mime"
+    "net/http"
+    "path"
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// 上传限制
+const (
+    thumbnailMaxSide  = 320 // 缩略图最长边（像素）
+    maxFilenameLength = 255
+)
+
+// uploadTypes 允许上传的文件类型（根据文件内容识别）及保存时使用的扩展名
+var uploadTypes = map[string]string{
+    "image/jpeg":      ".jpg",
+    "image/png":       ".png",
+    "image/gif":       ".gif",
+    "image/webp":      ".webp",
+    "application/pdf": ".pdf",
+    "text/plain":      ".txt",
+}
+
+// UploadInput 上传文件的参数
+type UploadInput struct {
+    Filename string    // 客户端提供的文件名，仅用于展示
+    Reader   io.Reader // 文件内容
+    PostID   *uint     // 关联的文章，为空表示暂不关联
+}
+
+// UploadUseCase 文件上传用例接口
+type UploadUseCase interface {
+    Upload(userID uint, input UploadInput) (*model.Attachment, error)
+    GetMine(userID uint, page, limit int) ([]*model.Attachment, int64, error)
+    Delete(id, userID uint) error
+    // Open 读取已上传的文件，返回内容和MIME类型
+    Open(key string) (io.ReadCloser, string, error)
+}
+
+type uploadUseCase struct {
+    attachmentRepo repository.AttachmentRepository
+    postRepo       repository.PostRepository
+    userRepo       repository.UserRepository
+    storage        repository.FileStorage
+    maxSize        int64
+}
+
+// NewUploadUseCase 创建文件上传用例，maxSize为单个文件的最大字节数
+func NewUploadUseCase(
+    attachmentRepo repository.AttachmentRepository,
+    postRepo repository.PostRepository,
+    userRepo repository.UserRepository,
+    storage repository.FileStorage,
+    maxSize int64,
+) UploadUseCase {
+    return &uploadUseCase{
+        attachmentRepo: attachmentRepo,
+        postRepo:       postRepo,
+        userRepo:       userRepo,
+        storage:        storage,
+        maxSize:        maxSize,
+    }
+}
+
+// Upload 校验并保存文件，图片同时生成缩略图
+// 文件类型根据内容识别，不信任客户端提供的文件名和Content-Type
+func (uc *uploadUseCase) Upload(userID uint, input UploadInput) (*model.Attachment, error) {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return nil, errors.New("用户不存在")
+    }
+    if err := contentCreationError(user); err != nil {
+        return nil, err
+    }
+
+    if input.PostID != nil {
+        post, err := uc.postRepo.GetByID(*input.PostID)
+        if err != nil {
+            return nil, err
+        }
+        if !policy.CanUpdatePost(user, post) {
+            return nil, errors.New("没有权限向此文章上传附件")
+        }
+    }
+
+    data, err := io.ReadAll(io.LimitReader(input.Reader, uc.maxSize+1))
+    if err != nil {
+        return nil, err
+    }
+    if int64(len(data)) > uc.maxSize {
+        return nil, errors.New("文件大小超过限制")
+    }
+    if len(data) == 0 {
+        return nil, errors.New("文件内容为空")
+    }
+
+    contentType := detectContentType(data)
+    ext, ok := uploadTypes[contentType]
+    if !ok {
+        return nil, errors.New("不支持的文件类型")
+    }
+
+    attachment := &model.Attachment{
+        UserID:      userID,
+        PostID:      input.PostID,
+        Filename:    cleanFilename(input.Filename, ext),
+        ContentType: contentType,
+        Size:        int64(len(data)),
+    }
+
+    // 先生成缩略图，图片无法解码时直接拒绝，避免保存伪装成图片的文件
+    var thumb *thumbnail.Thumbnail
+    if attachment.IsImage() && contentType != "image/webp" {
+        thumb, err = thumbnail.Generate(data, thumbnailMaxSide)
+        if err != nil {
+            return nil, err
+        }
+        attachment.Width, attachment.Height = thumb.SourceWidth, thumb.SourceHeight
+    }
+
+    base, err := newStorageKey()
+    if err != nil {
+        return nil, err
+    }
+    attachment.StorageKey = base + ext
+    if err := uc.storage.Put(attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+        return nil, err
+    }
+    attachment.URL = uc.storage.URL(attachment.StorageKey)
+
+    if thumb != nil {
+        attachment.ThumbnailKey = base + "_thumb" + thumb.Ext
+        if err := uc.storage.Put(attachment.ThumbnailKey, bytes.NewReader(thumb.Data), int64(len(thumb.Data)), thumb.ContentType); err != nil {
+            removeFiles(uc.storage, attachment)
+            return nil, err
+        }
+        attachment.ThumbnailURL = uc.storage.URL(attachment.ThumbnailKey)
+    }
+
+    if err := uc.attachmentRepo.Create(attachment); err != nil {
+        removeFiles(uc.storage, attachment)
+        return nil, err
+    }
+    return attachment, nil
+}
+
+// GetMine 获取自己上传的附件
+func (uc *uploadUseCase) GetMine(userID uint, page, limit int) ([]*model.Attachment, int64, error) {
+    return uc.attachmentRepo.GetByUserID(userID, page, limit)
+}
+
+// Delete 删除附件及其文件
+func (uc *uploadUseCase) Delete(id, userID uint) error {
+    attachment, err := uc.attachmentRepo.GetByID(id)
+    if err != nil {
+        return err
+    }
+
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+    if !policy.CanDeleteAttachment(user, attachment) {
+        return errors.New("没有权限删除此附件")
+    }
+
+    return removeAttachment(uc.attachmentRepo, uc.storage, attachment)
+}
+
+// Open 读取已上传的文件，MIME类型由扩展名决定
+func (uc *uploadUseCase) Open(key string) (io.ReadCloser, string, error) {
+    contentType := mime.TypeByExtension(path.Ext(key))
+    if contentType == "" {
+        contentType = "application/octet-stream"
+    }
+    f, err := uc.storage.Open(key)
+    if err != nil {
+        return nil, "", err
+    }
+    return f, contentType, nil
+}
+
+// removeAttachment 删除附件记录和文件；先删记录，文件删除失败只会留下无人引用的文件
+func removeAttachment(repo repository.AttachmentRepository, storage repository.FileStorage, attachment *model.Attachment) error {
+    if err := repo.Delete(attachment.ID); err != nil {
+        return err
+    }
+    removeFiles(storage, attachment)
+    return nil
+}
+
+// removeFiles 删除附件及缩略图文件，失败时只记录日志
+func removeFiles(storage repository.FileStorage, attachment *model.Attachment) {
+    for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+        if key == "" {
+            continue
+        }
+        if err := storage.Delete(key); err != nil {
+            logger.Error("删除文件失败: "+key, err)
+        }
+    }
+}
+
+// detectContentType 根据文件内容识别MIME类型，去掉charset等参数
+func detectContentType(data []byte) string {
+    contentType := http.DetectContentType(data)
+    if i := strings.IndexByte(contentType, ';'); i >= 0 {
+        contentType = contentType[:i]
+    }
+    return strings.TrimSpace(contentType)
+}
+
+// cleanFilename 只保留文件名部分并限制长度，为空时使用默认名称
+func cleanFilename(name, ext string) string {
+    name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+    name = strings.Map(func(r rune) rune {
+        if r < 0x20 || r == 0x7f {
+            return -1
+        }
+        return r
+    }, name)
+    if name == "" || name == "." || name == "/" {
+        name = "file" + ext
+    }
+    return truncateRunes(name, maxFilenameLength)
+}
+
+// newStorageKey 生成不含扩展名的随机存储路径，按上传月份分目录
+func newStorageKey() (string, error) {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return "attachments/" + time.Now().Format("2006/01") + "/" + hex.EncodeToString(b), nil
+}
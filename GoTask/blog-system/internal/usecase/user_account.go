@@ -0,0 +1,205 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/mail"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "net/url"
+    "time"
+)
+
+// AccountOptions 邮箱验证、找回密码和两步验证的参数
+type AccountOptions struct {
+    BaseURL    string        // 邮件中链接的前缀（前端地址），链接为 BaseURL/verify-email?token=...
+    VerifyTTL  time.Duration // 邮箱验证链接有效期
+    ResetTTL   time.Duration // 找回密码链接有效期
+    TOTPIssuer string        // 验证器应用中显示的服务名称
+}
+
+// VerifyEmail 使用邮件中的令牌验证邮箱
+func (uc *userUseCase) VerifyEmail(token string) error {
+    record, err := uc.tokenRepo.Consume(model.TokenPurposeVerifyEmail, hashAccountToken(token), time.Now())
+    if err != nil {
+        return err
+    }
+
+    user, err := uc.userRepo.GetByID(record.UserID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+    // 发送验证邮件后修改过邮箱，旧链接不能验证新邮箱
+    if record.Email != user.Email {
+        return errors.New("链接无效或已过期")
+    }
+    if user.Verified {
+        return nil
+    }
+
+    user.Verified = true
+    return uc.userRepo.Update(user)
+}
+
+// ResendVerification 重新发送验证邮件，之前发送的链接随即失效
+func (uc *userUseCase) ResendVerification(userID uint) error {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+    if user.Verified {
+        return errors.New("邮箱已验证")
+    }
+
+    return uc.sendVerification(user)
+}
+
+// ChangePassword 修改密码，需要提供当前密码；修改后已签发的令牌全部失效
+func (uc *userUseCase) ChangePassword(userID uint, oldPassword, newPassword string) error {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !uc.hasher.Verify(oldPassword, user.Password) {
+        return errors.New("当前密码错误")
+    }
+
+    return uc.setPassword(user, newPassword)
+}
+
+// ForgotPassword 向邮箱发送找回密码链接；邮箱未注册时同样返回成功，避免泄露邮箱是否已注册
+func (uc *userUseCase) ForgotPassword(email string) error {
+    if model.IsReservedUser("", email) {
+        return nil
+    }
+    user, err := uc.userRepo.GetByEmail(email)
+    if err != nil {
+        return nil
+    }
+
+    if err := uc.tokenRepo.DeleteByUser(user.ID, model.TokenPurposeResetPassword); err != nil {
+        return err
+    }
+    token, err := uc.issueToken(user, model.TokenPurposeResetPassword, uc.account.ResetTTL)
+    if err != nil {
+        return err
+    }
+
+    body := fmt.Sprintf("%s，您好：\n\n我们收到了重置您账号密码的请求，请在%s内打开以下链接设置新密码：\n\n%s\n\n"+
+        "如果这不是您本人的操作，请忽略本邮件，您的密码不会被修改。\n",
+        user.Username, formatTTL(uc.account.ResetTTL), uc.accountLink("reset-password", token))
+    // 发送失败只记录日志，返回错误会暴露该邮箱已注册
+    if err := uc.mailer.Send(mail.Message{To: user.Email, Subject: "重置密码", Body: body}); err != nil {
+        logger.Error("发送找回密码邮件失败", err)
+    }
+    return nil
+}
+
+// ResetPassword 使用找回密码令牌设置新密码；修改后已签发的令牌全部失效
+func (uc *userUseCase) ResetPassword(token, newPassword string) error {
+    record, err := uc.tokenRepo.Consume(model.TokenPurposeResetPassword, hashAccountToken(token), time.Now())
+    if err != nil {
+        return err
+    }
+
+    user, err := uc.userRepo.GetByID(record.UserID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    // 能收到邮件说明邮箱属于该用户，顺带完成邮箱验证
+    if record.Email == user.Email {
+        user.Verified = true
+    }
+    return uc.setPassword(user, newPassword)
+}
+
+// PurgeExpiredTokens 删除过期和已使用的一次性令牌，供调度器定期调用
+func (uc *userUseCase) PurgeExpiredTokens(now time.Time) (int, error) {
+    count, err := uc.tokenRepo.DeleteExpired(now)
+    return int(count), err
+}
+
+// setPassword 校验并保存新密码，使所有找回密码链接、个人访问令牌和已签发的令牌失效
+func (uc *userUseCase) setPassword(user *model.User, password string) error {
+    if err := uc.passwords.Validate(password, user.Username, user.Email); err != nil {
+        return err
+    }
+    hashedPassword, err := uc.hasher.Hash(password)
+    if err != nil {
+        return err
+    }
+
+    user.Password = hashedPassword
+    if err := uc.userRepo.Update(user); err != nil {
+        return err
+    }
+    if err := uc.tokenRepo.DeleteByUser(user.ID, model.TokenPurposeResetPassword); err != nil {
+        return err
+    }
+    if err := uc.apiTokenRepo.DeleteByUser(user.ID); err != nil {
+        return err
+    }
+    return uc.jwtService.RevokeUserTokens(user.ID)
+}
+
+// sendVerification 生成邮箱验证令牌并发送验证邮件，之前的验证链接随即失效
+func (uc *userUseCase) sendVerification(user *model.User) error {
+    if err := uc.tokenRepo.DeleteByUser(user.ID, model.TokenPurposeVerifyEmail); err != nil {
+        return err
+    }
+    token, err := uc.issueToken(user, model.TokenPurposeVerifyEmail, uc.account.VerifyTTL)
+    if err != nil {
+        return err
+    }
+
+    body := fmt.Sprintf("%s，您好：\n\n请在%s内打开以下链接验证您的邮箱，验证后即可发表文章和评论：\n\n%s\n\n"+
+        "如果您没有注册账号，请忽略本邮件。\n",
+        user.Username, formatTTL(uc.account.VerifyTTL), uc.accountLink("verify-email", token))
+    return uc.mailer.Send(mail.Message{To: user.Email, Subject: "验证邮箱", Body: body})
+}
+
+// issueToken 生成一次性令牌并保存其哈希，返回明文令牌
+func (uc *userUseCase) issueToken(user *model.User, purpose string, ttl time.Duration) (string, error) {
+    b := make([]byte, 32)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    token := base64.RawURLEncoding.EncodeToString(b)
+
+    err := uc.tokenRepo.Create(&model.UserToken{
+        UserID:    user.ID,
+        Purpose:   purpose,
+        TokenHash: hashAccountToken(token),
+        Email:     user.Email,
+        ExpiresAt: time.Now().Add(ttl),
+    })
+    if err != nil {
+        return "", err
+    }
+    return token, nil
+}
+
+// accountLink 生成邮件中的链接
+func (uc *userUseCase) accountLink(path, token string) string {
+    return uc.account.BaseURL + "/" + path + "?token=" + url.QueryEscape(token)
+}
+
+// hashAccountToken 计算令牌的SHA-256，数据库中不保存明文令牌
+func hashAccountToken(token string) string {
+    sum := sha256.Sum256([]byte(token))
+    return hex.EncodeToString(sum[:])
+}
+
+// formatTTL 将有效期格式化为邮件中的中文描述
+func formatTTL(d time.Duration) string {
+    if d >= time.Hour && d%time.Hour == 0 {
+        return fmt.Sprintf("%d小时", int(d/time.Hour))
+    }
+    return fmt.Sprintf("%d分钟", int(d.Round(time.Minute)/time.Minute))
+}
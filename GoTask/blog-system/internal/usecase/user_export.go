@@ -0,0 +1,301 @@
+package usecase
+
+import (
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
+    "archive/zip"
+    "encoding/json"
+    "fmt"
+    "io"
+    "strings"
+    "time"
+)
+
+// exportBatchSize 导出数据时每次从仓储读取的记录数
+const exportBatchSize = 100
+
+// ExportUseCase 个人数据导出用例
+type ExportUseCase interface {
+    // Export 将用户的全部数据写成ZIP归档，数据按页读取并直接写入w，不会一次性加载到内存
+    Export(userID uint, w io.Writer) error
+}
+
+type exportUseCase struct {
+    repos repository.TxRepositories
+}
+
+// NewExportUseCase 创建个人数据导出用例
+func NewExportUseCase(userRepo repository.UserRepository, postRepo repository.PostRepository, commentRepo repository.CommentRepository, revisionRepo repository.PostRevisionRepository, bookmarkRepo repository.BookmarkRepository) ExportUseCase {
+    return &exportUseCase{
+        repos: repository.TxRepositories{
+            Users:     userRepo,
+            Posts:     postRepo,
+            Comments:  commentRepo,
+            Revisions: revisionRepo,
+            Bookmarks: bookmarkRepo,
+        },
+    }
+}
+
+// Export 将用户的全部数据写成ZIP归档
+// 归档结构：profile.json、posts/<id>.json、posts/<id>.md、posts/<id>.revisions.json、comments.json、bookmarks.json
+func (uc *exportUseCase) Export(userID uint, w io.Writer) error {
+    user, err := uc.repos.Users.GetByID(userID)
+    if err != nil {
+        return err
+    }
+
+    archive := zip.NewWriter(w)
+    if err := writeJSONEntry(archive, "profile.json", exportProfile(user)); err != nil {
+        return err
+    }
+
+    err = eachUserPost(uc.repos, userID, func(post *model.Post) error {
+        name := fmt.Sprintf("posts/%d", post.ID)
+        if err := writeJSONEntry(archive, name+".json", post); err != nil {
+            return err
+        }
+        if err := writeEntry(archive, name+".md", postMarkdown(post)); err != nil {
+            return err
+        }
+
+        revisions, err := newJSONArrayEntry(archive, name+".revisions.json")
+        if err != nil {
+            return err
+        }
+        if err := eachPostRevision(uc.repos, post.ID, func(rev *model.PostRevision) error {
+            return revisions.add(rev)
+        }); err != nil {
+            return err
+        }
+        return revisions.close()
+    })
+    if err != nil {
+        return err
+    }
+
+    comments, err := newJSONArrayEntry(archive, "comments.json")
+    if err != nil {
+        return err
+    }
+    if err := eachUserComment(uc.repos, userID, func(comment *model.Comment) error {
+        return comments.add(comment)
+    }); err != nil {
+        return err
+    }
+    if err := comments.close(); err != nil {
+        return err
+    }
+
+    bookmarks, err := uc.repos.Bookmarks.ListByUser(userID)
+    if err != nil {
+        return err
+    }
+    if err := writeJSONEntry(archive, "bookmarks.json", nonNilBookmarks(bookmarks)); err != nil {
+        return err
+    }
+
+    return archive.Close()
+}
+
+// collectUserExport 收集用户名下的全部数据：资料、文章（包括草稿和回收站中的）、评论、修订记录和收藏
+func collectUserExport(repos repository.TxRepositories, user *model.User) (*model.UserExport, error) {
+    data := &model.UserExport{
+        User:       exportProfile(user),
+        Posts:      []*model.Post{},
+        Comments:   []*model.Comment{},
+        Revisions:  []*model.PostRevision{},
+        Bookmarks:  []*model.Bookmark{},
+        ExportedAt: time.Now(),
+    }
+
+    err := eachUserPost(repos, user.ID, func(post *model.Post) error {
+        data.Posts = append(data.Posts, post)
+        return eachPostRevision(repos, post.ID, func(rev *model.PostRevision) error {
+            data.Revisions = append(data.Revisions, rev)
+            return nil
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    err = eachUserComment(repos, user.ID, func(comment *model.Comment) error {
+        data.Comments = append(data.Comments, comment)
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    bookmarks, err := repos.Bookmarks.ListByUser(user.ID)
+    if err != nil {
+        return nil, err
+    }
+    data.Bookmarks = nonNilBookmarks(bookmarks)
+
+    return data, nil
+}
+
+// nonNilBookmarks 没有收藏时返回空切片，使JSON输出为[]而不是null
+func nonNilBookmarks(bookmarks []*model.Bookmark) []*model.Bookmark {
+    if bookmarks == nil {
+        return []*model.Bookmark{}
+    }
+    return bookmarks
+}
+
+// exportProfile 复制用户资料并去掉密码
+func exportProfile(user *model.User) *model.User {
+    profile := *user
+    profile.Password = ""
+    return &profile
+}
+
+// eachUserPost 按页遍历用户的全部文章，包括草稿和回收站中的文章
+func eachUserPost(repos repository.TxRepositories, userID uint, handle func(post *model.Post) error) error {
+    err := eachPage(func(page int) ([]*model.Post, int64, error) {
+        return repos.Posts.GetByAuthor(userID, "", page, exportBatchSize)
+    }, handle)
+    if err != nil {
+        return err
+    }
+    return eachPage(func(page int) ([]*model.Post, int64, error) {
+        return repos.Posts.GetDeletedByAuthor(userID, page, exportBatchSize)
+    }, handle)
+}
+
+// eachPostRevision 按页遍历文章的全部修订记录
+func eachPostRevision(repos repository.TxRepositories, postID uint, handle func(rev *model.PostRevision) error) error {
+    return eachPage(func(page int) ([]*model.PostRevision, int64, error) {
+        return repos.Revisions.GetByPostID(postID, page, exportBatchSize)
+    }, handle)
+}
+
+// eachUserComment 按页遍历用户的全部评论，包括占位评论和回收站中的评论
+func eachUserComment(repos repository.TxRepositories, userID uint, handle func(comment *model.Comment) error) error {
+    return eachPage(func(page int) ([]*model.Comment, int64, error) {
+        return repos.Comments.GetByUserID(userID, page, exportBatchSize)
+    }, handle)
+}
+
+// eachPage 按页读取全部记录并逐条处理
+func eachPage[T any](next func(page int) ([]T, int64, error), handle func(item T) error) error {
+    for page := 1; ; page++ {
+        items, _, err := next(page)
+        if err != nil {
+            return err
+        }
+        for _, item := range items {
+            if err := handle(item); err != nil {
+                return err
+            }
+        }
+        if len(items) < exportBatchSize {
+            return nil
+        }
+    }
+}
+
+// postMarkdown 将文章转换为Markdown文档，元数据以列表形式放在标题之后
+func postMarkdown(post *model.Post) string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "# %s\n\n", post.Title)
+    fmt.Fprintf(&b, "- 状态: %s\n", post.Status)
+    fmt.Fprintf(&b, "- 创建时间: %s\n", post.CreatedAt.Format(time.RFC3339))
+    fmt.Fprintf(&b, "- 更新时间: %s\n", post.UpdatedAt.Format(time.RFC3339))
+    if post.PublishAt != nil {
+        fmt.Fprintf(&b, "- 发布时间: %s\n", post.PublishAt.Format(time.RFC3339))
+    }
+    if post.DeletedAt.Valid {
+        fmt.Fprintf(&b, "- 删除时间: %s\n", post.DeletedAt.Time.Format(time.RFC3339))
+    }
+    if len(post.Tags) > 0 {
+        names := make([]string, 0, len(post.Tags))
+        for _, tag := range post.Tags {
+            names = append(names, tag.Name)
+        }
+        fmt.Fprintf(&b, "- 标签: %s\n", strings.Join(names, ", "))
+    }
+    if len(post.Categories) > 0 {
+        names := make([]string, 0, len(post.Categories))
+        for _, category := range post.Categories {
+            names = append(names, category.Name)
+        }
+        fmt.Fprintf(&b, "- 分类: %s\n", strings.Join(names, ", "))
+    }
+    fmt.Fprintf(&b, "\n%s\n", post.Content)
+    return b.String()
+}
+
+// createEntry 在归档中创建压缩文件，修改时间为导出时间
+func createEntry(archive *zip.Writer, name string) (io.Writer, error) {
+    return archive.CreateHeader(&zip.FileHeader{
+        Name:     name,
+        Method:   zip.Deflate,
+        Modified: time.Now(),
+    })
+}
+
+// writeEntry 向归档写入一个文本文件
+func writeEntry(archive *zip.Writer, name, content string) error {
+    f, err := createEntry(archive, name)
+    if err != nil {
+        return err
+    }
+    _, err = io.WriteString(f, content)
+    return err
+}
+
+// writeJSONEntry 向归档写入一个JSON文件
+func writeJSONEntry(archive *zip.Writer, name string, value interface{}) error {
+    f, err := createEntry(archive, name)
+    if err != nil {
+        return err
+    }
+    encoder := json.NewEncoder(f)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(value)
+}
+
+// jsonArrayEntry 逐条写入的JSON数组文件，避免先把整个数组放进内存
+type jsonArrayEntry struct {
+    w     io.Writer
+    count int
+}
+
+// newJSONArrayEntry 在归档中创建JSON数组文件
+func newJSONArrayEntry(archive *zip.Writer, name string) (*jsonArrayEntry, error) {
+    f, err := createEntry(archive, name)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := io.WriteString(f, "["); err != nil {
+        return nil, err
+    }
+    return &jsonArrayEntry{w: f}, nil
+}
+
+// add 追加一个数组元素
+func (e *jsonArrayEntry) add(value interface{}) error {
+    data, err := json.Marshal(value)
+    if err != nil {
+        return err
+    }
+    sep := ",\n"
+    if e.count == 0 {
+        sep = "\n"
+    }
+    e.count++
+    if _, err := io.WriteString(e.w, sep); err != nil {
+        return err
+    }
+    _, err = e.w.Write(data)
+    return err
+}
+
+// close 结束数组
+func (e *jsonArrayEntry) close() error {
+    _, err := io.WriteString(e.w, "\n]\n")
+    return err
+}
@@ -2,36 +2,110 @@ package usecase
 
 import (
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/model"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/policy"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/domain/repository"
     "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/auth"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/internal/infrastructure/mail"
+    "github.com/adamlizp/MetaNode/GoTask/blog-system/pkg/logger"
     "errors"
-    "golang.org/x/crypto/bcrypt"
+    "fmt"
+    "time"
 )
 
 // UserUseCase 用户用例接口
 type UserUseCase interface {
     Register(username, password, email string) error
-    Login(username, password string) (string, error)
+    // Login 用户登录，ip和userAgent用于失败计数和登录审计
+    Login(username, password, ip, userAgent string) (*auth.TokenPair, error)
+    Refresh(refreshToken string) (*auth.TokenPair, error)
+    Logout(claims *auth.JWTClaims, refreshToken string) error
     GetProfile(userID uint) (*model.User, error)
     UpdateProfile(userID uint, username, email string) error
-    DeleteUser(userID uint) error
+    DeleteUser(userID uint, mode string, export bool) (*model.UserExport, error)
+    ListUsers(adminID uint, page, limit int) ([]*model.User, int64, error)
+    ChangeRole(adminID, targetID uint, role string) error
+    SetBanned(adminID, targetID uint, banned bool) error
+    ListDeletedUsers(adminID uint, page, limit int) ([]*model.User, int64, error)
+    RestoreUser(adminID, targetID uint) error
+    VerifyEmail(token string) error
+    ResendVerification(userID uint) error
+    ChangePassword(userID uint, oldPassword, newPassword string) error
+    ForgotPassword(email string) error
+    ResetPassword(token, newPassword string) error
+    PurgeExpiredTokens(now time.Time) (int, error)
+    TwoFactorStatus(userID uint) (*TwoFactorStatus, error)
+    SetupTwoFactor(userID uint) (*TwoFactorSetup, error)
+    EnableTwoFactor(userID uint, code string) (*TwoFactorEnabled, error)
+    DisableTwoFactor(userID uint, password, code string) error
+    RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+    // VerifyTwoFactor 登录返回*MFARequiredError后，使用其中的令牌和验证码完成登录
+    VerifyTwoFactor(mfaToken, code, ip, userAgent string) (*auth.TokenPair, error)
+    // RequireMFA 敏感操作前调用，satisfied为当前令牌是否通过了两步验证
+    RequireMFA(userID uint, satisfied bool) error
 }
 
 type userUseCase struct {
-    userRepo   repository.UserRepository
-    jwtService auth.JWTService
+    userRepo     repository.UserRepository
+    tokenRepo    repository.UserTokenRepository
+    totpRepo     repository.UserTOTPRepository
+    recoveryRepo repository.RecoveryCodeRepository
+    apiTokenRepo repository.APITokenRepository
+    transactor   repository.Transactor
+    searchIndex  repository.SearchIndex
+    jwtService   auth.JWTService
+    loginGuard   LoginGuard
+    mailer       mail.Mailer
+    account      AccountOptions
+    hasher       auth.PasswordHasher
+    passwords    PasswordPolicy
+    dummyHash    string // 用户不存在时用于校验的哈希，使响应时间与用户存在时一致
 }
 
 // NewUserUseCase 创建用户用例
-func NewUserUseCase(userRepo repository.UserRepository, jwtService auth.JWTService) UserUseCase {
+func NewUserUseCase(
+    userRepo repository.UserRepository,
+    tokenRepo repository.UserTokenRepository,
+    totpRepo repository.UserTOTPRepository,
+    recoveryRepo repository.RecoveryCodeRepository,
+    apiTokenRepo repository.APITokenRepository,
+    transactor repository.Transactor,
+    searchIndex repository.SearchIndex,
+    jwtService auth.JWTService,
+    loginGuard LoginGuard,
+    mailer mail.Mailer,
+    account AccountOptions,
+    hasher auth.PasswordHasher,
+    passwords PasswordPolicy,
+) UserUseCase {
+    dummyHash, err := hasher.Hash("dummy-password")
+    if err != nil {
+        logger.Error("生成占位密码哈希失败", err)
+    }
+
     return &userUseCase{
-        userRepo:   userRepo,
-        jwtService: jwtService,
+        userRepo:     userRepo,
+        tokenRepo:    tokenRepo,
+        totpRepo:     totpRepo,
+        recoveryRepo: recoveryRepo,
+        apiTokenRepo: apiTokenRepo,
+        transactor:   transactor,
+        searchIndex:  searchIndex,
+        jwtService:   jwtService,
+        loginGuard:   loginGuard,
+        mailer:       mailer,
+        account:      account,
+        hasher:       hasher,
+        passwords:    passwords,
+        dummyHash:    dummyHash,
     }
 }
 
 // Register 用户注册
 func (uc *userUseCase) Register(username, password, email string) error {
+    if model.IsReservedUser(username, email) {
+        return errors.New("用户名或邮箱不可用")
+    }
+
     // 检查用户名是否已存在
     existingUser, _ := uc.userRepo.GetByUsername(username)
     if existingUser != nil {
@@ -44,41 +118,174 @@ func (uc *userUseCase) Register(username, password, email string) error {
         return errors.New("邮箱已存在")
     }
 
+    if err := uc.passwords.Validate(password, username, email); err != nil {
+        return err
+    }
+
     // 密码加密
-    hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+    hashedPassword, err := uc.hasher.Hash(password)
     if err != nil {
         return err
     }
 
     user := &model.User{
         Username: username,
-        Password: string(hashedPassword),
+        Password: hashedPassword,
         Email:    email,
+        Role:     model.RoleUser,
+    }
+
+    if err := uc.userRepo.Create(user); err != nil {
+        return err
     }
 
-    return uc.userRepo.Create(user)
+    // 注册已经成功，验证邮件发送失败时用户可以重新发送
+    if err := uc.sendVerification(user); err != nil {
+        logger.Error("发送验证邮件失败", err)
+    }
+    return nil
 }
 
-// Login 用户登录
-func (uc *userUseCase) Login(username, password string) (string, error) {
+// Login 用户登录，用户名或IP连续失败过多时临时锁定
+func (uc *userUseCase) Login(username, password, ip, userAgent string) (*auth.TokenPair, error) {
+    attempt := &model.LoginAttempt{Username: username, IP: ip, UserAgent: userAgent}
+
+    // 被锁定时不校验密码，避免锁定期间继续猜测
+    if err := uc.loginGuard.Check(username, ip); err != nil {
+        var locked *LoginLockedError
+        if errors.As(err, &locked) {
+            attempt.Result = model.LoginResultLocked
+            uc.recordLogin(attempt)
+        }
+        return nil, err
+    }
+
     user, err := uc.userRepo.GetByUsername(username)
     if err != nil {
-        return "", errors.New("用户名或密码错误")
+        // 同样计算一次哈希，避免通过响应时间判断用户名是否存在
+        uc.hasher.Verify(password, uc.dummyHash)
+        attempt.Result = model.LoginResultFailed
+        uc.recordLogin(attempt)
+        return nil, errors.New("用户名或密码错误")
     }
+    attempt.UserID = &user.ID
 
     // 验证密码
-    err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+    if !uc.hasher.Verify(password, user.Password) {
+        attempt.Result = model.LoginResultFailed
+        uc.recordLogin(attempt)
+        return nil, errors.New("用户名或密码错误")
+    }
+
+    if user.Banned {
+        attempt.Result = model.LoginResultBanned
+        uc.recordLogin(attempt)
+        return nil, errors.New("账号已被封禁")
+    }
+
+    // 哈希的算法或参数已过时，趁登录时拿到明文密码重新哈希
+    if uc.hasher.NeedsRehash(user.Password) {
+        uc.rehashPassword(user, password)
+    }
+
+    // 生成JWT令牌，启用了两步验证时返回*MFARequiredError
+    tokens, err := issueLoginTokens(uc.totpRepo, uc.jwtService, user)
+    var mfa *MFARequiredError
+    switch {
+    case errors.As(err, &mfa):
+        attempt.Result = model.LoginResultMFARequired
+    case err != nil:
+        return nil, err
+    default:
+        attempt.Result = model.LoginResultSuccess
+    }
+    uc.recordLogin(attempt)
+    return tokens, err
+}
+
+// recordLogin 保存登录记录，失败时只记录日志，不影响登录结果
+func (uc *userUseCase) recordLogin(attempt *model.LoginAttempt) {
+    if err := uc.loginGuard.Record(attempt); err != nil {
+        logger.Error("保存登录记录失败", err)
+    }
+}
+
+// rehashPassword 使用当前配置的算法重新哈希密码，失败时只记录日志，不影响登录结果
+func (uc *userUseCase) rehashPassword(user *model.User, password string) {
+    hashedPassword, err := uc.hasher.Hash(password)
     if err != nil {
-        return "", errors.New("用户名或密码错误")
+        logger.Error("重新哈希密码失败", err)
+        return
     }
 
-    // 生成JWT令牌
-    token, err := uc.jwtService.GenerateToken(user)
+    user.Password = hashedPassword
+    if err := uc.userRepo.Update(user); err != nil {
+        logger.Error("保存重新哈希的密码失败", err)
+    }
+}
+
+// Refresh 使用刷新令牌换取新的令牌对，旧的刷新令牌随即失效
+// 已轮换的刷新令牌再次出现说明令牌可能被盗用，此时吊销该用户的全部令牌
+func (uc *userUseCase) Refresh(refreshToken string) (*auth.TokenPair, error) {
+    claims, err := uc.jwtService.ValidateRefreshToken(refreshToken)
+    if errors.Is(err, auth.ErrTokenRevoked) {
+        return nil, uc.refreshTokenReused(claims.UserID)
+    }
+    if err != nil {
+        return nil, errors.New("无效的刷新令牌")
+    }
+
+    user, err := uc.userRepo.GetByID(claims.UserID)
     if err != nil {
-        return "", err
+        return nil, errors.New("用户不存在")
     }
 
-    return token, nil
+    if user.Banned {
+        return nil, errors.New("账号已被封禁")
+    }
+
+    // 轮换刷新令牌：吊销是原子的，并发使用同一个刷新令牌时只有一个请求成功
+    first, err := uc.jwtService.ConsumeToken(claims)
+    if err != nil {
+        return nil, err
+    }
+    if !first {
+        return nil, uc.refreshTokenReused(claims.UserID)
+    }
+
+    // 新令牌沿用原登录是否通过两步验证
+    return uc.jwtService.GenerateTokenPair(user, claims.MFA)
+}
+
+// refreshTokenReused 吊销用户的全部令牌，返回刷新失败的错误
+func (uc *userUseCase) refreshTokenReused(userID uint) error {
+    logger.Warn(fmt.Sprintf("用户 %d 的刷新令牌被重复使用，已吊销该用户的全部令牌", userID))
+    if err := uc.jwtService.RevokeUserTokens(userID); err != nil {
+        return err
+    }
+    return errors.New("无效的刷新令牌")
+}
+
+// Logout 用户登出，吊销当前访问令牌以及可选的刷新令牌
+func (uc *userUseCase) Logout(claims *auth.JWTClaims, refreshToken string) error {
+    if err := uc.jwtService.RevokeToken(claims); err != nil {
+        return err
+    }
+
+    if refreshToken == "" {
+        return nil
+    }
+
+    refreshClaims, err := uc.jwtService.ValidateRefreshToken(refreshToken)
+    if err != nil {
+        // 刷新令牌已过期或已失效，无需再吊销
+        return nil
+    }
+    if refreshClaims.UserID != claims.UserID {
+        return errors.New("刷新令牌不属于当前用户")
+    }
+
+    return uc.jwtService.RevokeToken(refreshClaims)
 }
 
 // GetProfile 获取用户资料
@@ -101,6 +308,10 @@ func (uc *userUseCase) UpdateProfile(userID uint, username, email string) error
         return err
     }
     
+    if model.IsReservedUser(username, email) {
+        return errors.New("用户名或邮箱不可用")
+    }
+
     // 检查新用户名是否已被其他用户使用
     if username != user.Username {
         existingUser, _ := uc.userRepo.GetByUsername(username)
@@ -117,14 +328,227 @@ func (uc *userUseCase) UpdateProfile(userID uint, username, email string) error
         }
     }
     
-    // 更新用户信息
+    // 更新用户信息，修改邮箱后需要重新验证
+    emailChanged := email != user.Email
     user.Username = username
     user.Email = email
-    
-    return uc.userRepo.Update(user)
+    if emailChanged {
+        user.Verified = false
+    }
+
+    if err := uc.userRepo.Update(user); err != nil {
+        return err
+    }
+
+    if emailChanged {
+        // 已发送到旧邮箱的重置密码链接随即失效
+        if err := uc.tokenRepo.DeleteByUser(userID, model.TokenPurposeResetPassword); err != nil {
+            return err
+        }
+        if err := uc.sendVerification(user); err != nil {
+            logger.Error("发送验证邮件失败", err)
+        }
+    }
+    return nil
+}
+
+// DeleteUser 删除用户账号，mode决定名下文章和评论的处理方式，export为true时返回删除前导出的数据
+// 导出、内容处理和账号删除在同一个事务中完成，任一步失败都不会留下部分删除的数据
+func (uc *userUseCase) DeleteUser(userID uint, mode string, export bool) (*model.UserExport, error) {
+    if !model.IsValidDeletionMode(mode) {
+        return nil, errors.New("无效的删除方式")
+    }
+
+    var data *model.UserExport
+    var postIDs, commentIDs []uint
+    err := uc.transactor.WithinTransaction(func(repos repository.TxRepositories) error {
+        user, err := repos.Users.GetByID(userID)
+        if err != nil {
+            return err
+        }
+
+        if export {
+            if data, err = collectUserExport(repos, user); err != nil {
+                return err
+            }
+        }
+
+        placeholder, err := deletedUserPlaceholder(repos.Users)
+        if err != nil {
+            return err
+        }
+
+        switch mode {
+        case model.AccountDeletionAnonymize:
+            if err := repos.Posts.ReassignUser(userID, placeholder.ID); err != nil {
+                return err
+            }
+            if err := repos.Comments.ReassignUser(userID, placeholder.ID); err != nil {
+                return err
+            }
+        case model.AccountDeletionCascade:
+            if postIDs, err = repos.Posts.DeleteByUserID(userID); err != nil {
+                return err
+            }
+            if commentIDs, err = repos.Comments.DeleteByUserID(userID, placeholder.ID); err != nil {
+                return err
+            }
+        }
+
+        // 撤回用户的回应和收藏，保持文章和评论上的计数准确
+        if err := repos.Reactions.DeleteByUser(userID); err != nil {
+            return err
+        }
+        if err := repos.Bookmarks.DeleteByUser(userID); err != nil {
+            return err
+        }
+
+        // 个人访问令牌不受令牌吊销影响，需要一并删除
+        if err := repos.APITokens.DeleteByUser(userID); err != nil {
+            return err
+        }
+        return repos.Users.Delete(userID)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    for _, id := range postIDs {
+        if err := uc.searchIndex.RemovePost(id); err != nil {
+            logger.Error("更新搜索索引失败", err)
+        }
+    }
+    for _, id := range commentIDs {
+        if err := uc.searchIndex.Remove(model.SearchTypeComment, id); err != nil {
+            logger.Error("更新搜索索引失败", err)
+        }
+    }
+
+    // 账号删除后已签发的令牌全部失效
+    return data, uc.jwtService.RevokeUserTokens(userID)
+}
+
+// deletedUserPlaceholder 获取已注销用户占位账号，不存在时创建
+func deletedUserPlaceholder(users repository.UserRepository) (*model.User, error) {
+    if user, err := users.GetByUsername(model.DeletedUsername); err == nil {
+        return user, nil
+    }
+
+    user := &model.User{
+        Username: model.DeletedUsername,
+        Email:    model.DeletedEmail,
+        Password: model.UnusablePassword,
+        Role:     model.RoleUser,
+        Banned:   true,
+    }
+    if err := users.Create(user); err != nil {
+        return nil, err
+    }
+    return user, nil
 }
 
-// DeleteUser 删除用户
-func (uc *userUseCase) DeleteUser(userID uint) error {
-    return uc.userRepo.Delete(userID)
-}
\ No newline at end of file
+// ListUsers 管理员获取用户列表（分页）
+func (uc *userUseCase) ListUsers(adminID uint, page, limit int) ([]*model.User, int64, error) {
+    if err := uc.requireAdmin(adminID); err != nil {
+        return nil, 0, err
+    }
+
+    return uc.userRepo.List(page, limit)
+}
+
+// ChangeRole 管理员修改用户角色
+func (uc *userUseCase) ChangeRole(adminID, targetID uint, role string) error {
+    if err := uc.requireAdmin(adminID); err != nil {
+        return err
+    }
+
+    if !model.IsValidRole(role) {
+        return errors.New("无效的角色")
+    }
+
+    if adminID == targetID {
+        return errors.New("不能修改自己的角色")
+    }
+
+    user, err := uc.userRepo.GetByID(targetID)
+    if err != nil {
+        return err
+    }
+
+    user.Role = role
+    if err := uc.userRepo.Update(user); err != nil {
+        return err
+    }
+
+    // 令牌中携带角色信息，角色变更后要求重新登录
+    return uc.jwtService.RevokeUserTokens(targetID)
+}
+
+// SetBanned 管理员封禁或解封用户
+func (uc *userUseCase) SetBanned(adminID, targetID uint, banned bool) error {
+    if err := uc.requireAdmin(adminID); err != nil {
+        return err
+    }
+
+    if adminID == targetID {
+        return errors.New("不能封禁自己")
+    }
+
+    user, err := uc.userRepo.GetByID(targetID)
+    if err != nil {
+        return err
+    }
+
+    user.Banned = banned
+    if err := uc.userRepo.Update(user); err != nil {
+        return err
+    }
+
+    if banned {
+        // 封禁后立即使已签发的令牌失效
+        return uc.jwtService.RevokeUserTokens(targetID)
+    }
+    return nil
+}
+
+// ListDeletedUsers 管理员获取回收站中的用户（分页）
+func (uc *userUseCase) ListDeletedUsers(adminID uint, page, limit int) ([]*model.User, int64, error) {
+    if err := uc.requireAdmin(adminID); err != nil {
+        return nil, 0, err
+    }
+
+    return uc.userRepo.GetDeleted(page, limit)
+}
+
+// RestoreUser 管理员从回收站恢复用户
+func (uc *userUseCase) RestoreUser(adminID, targetID uint) error {
+    if err := uc.requireAdmin(adminID); err != nil {
+        return err
+    }
+
+    return uc.userRepo.Restore(targetID)
+}
+
+// requireAdmin 检查操作者是否为管理员
+func (uc *userUseCase) requireAdmin(userID uint) error {
+    user, err := uc.userRepo.GetByID(userID)
+    if err != nil {
+        return errors.New("用户不存在")
+    }
+
+    if !policy.CanManageUsers(user) {
+        return errors.New("没有权限管理用户")
+    }
+    return nil
+}
+
+// contentCreationError 用户不能发表内容时返回具体原因
+func contentCreationError(user *model.User) error {
+    if policy.CanCreateContent(user) {
+        return nil
+    }
+    if user.Banned {
+        return errors.New("账号已被封禁")
+    }
+    return errors.New("请先验证邮箱")
+}
@@ -0,0 +1,12 @@
+// Package migrations 存放按数据库方言划分的版本化SQL迁移文件，并嵌入到二进制中。
+//
+// 文件命名格式：<版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql，
+// 例如 0001_init.up.sql。每个版本必须同时提供 mysql 和 sqlite 两个目录下的文件。
+package migrations
+
+import "embed"
+
+// FS 嵌入的迁移文件
+//
+//go:embed mysql/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,35 @@
+-- 初始表结构，与引入迁移前 AutoMigrate 创建的 users、posts、comments 三张表一致；
+-- 使用 IF NOT EXISTS 以便接管这样的数据库，之后新增的表和列都放在后续迁移中
+CREATE TABLE IF NOT EXISTS users (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    username VARCHAR(191) NOT NULL,
+    password LONGTEXT NOT NULL,
+    email VARCHAR(191) NOT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uni_users_username (username),
+    UNIQUE KEY uni_users_email (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS posts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    title LONGTEXT NOT NULL,
+    content LONGTEXT NOT NULL,
+    user_id BIGINT UNSIGNED NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_posts_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS comments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    content LONGTEXT NOT NULL,
+    user_id BIGINT UNSIGNED NULL,
+    post_id BIGINT UNSIGNED NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_comments_post FOREIGN KEY (post_id) REFERENCES posts (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,18 @@
+-- 令牌吊销：按jti吊销单个令牌，按用户吊销此前签发的全部令牌
+CREATE TABLE revoked_tokens (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    jti VARCHAR(64) NOT NULL,
+    user_id BIGINT UNSIGNED NULL,
+    expires_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_revoked_tokens_jti (jti),
+    KEY idx_revoked_tokens_user_id (user_id),
+    KEY idx_revoked_tokens_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE user_token_revocations (
+    user_id BIGINT UNSIGNED NOT NULL,
+    revoked_at DATETIME(3) NULL,
+    PRIMARY KEY (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+ALTER TABLE posts DROP COLUMN hidden;
+
+ALTER TABLE users
+    DROP COLUMN banned,
+    DROP COLUMN role;
@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP INDEX ft_comments_content;
+ALTER TABLE posts DROP INDEX ft_posts_title_content;
@@ -0,0 +1,3 @@
+-- 全文搜索索引，使用ngram分词以支持中文
+ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_title_content (title, content) WITH PARSER ngram;
+ALTER TABLE comments ADD FULLTEXT INDEX ft_comments_content (content) WITH PARSER ngram;
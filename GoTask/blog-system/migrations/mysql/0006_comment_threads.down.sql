@@ -0,0 +1,7 @@
+ALTER TABLE comments
+    DROP INDEX idx_comments_root_id,
+    DROP INDEX idx_comments_parent_id,
+    DROP COLUMN deleted,
+    DROP COLUMN depth,
+    DROP COLUMN root_id,
+    DROP COLUMN parent_id;
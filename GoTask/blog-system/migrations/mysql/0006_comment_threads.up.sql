@@ -0,0 +1,8 @@
+-- 评论回复：父评论、所属顶层评论、嵌套层级和删除占位标记
+ALTER TABLE comments
+    ADD COLUMN parent_id BIGINT UNSIGNED NULL,
+    ADD COLUMN root_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    ADD COLUMN depth INT NOT NULL DEFAULT 0,
+    ADD COLUMN deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD INDEX idx_comments_parent_id (parent_id),
+    ADD INDEX idx_comments_root_id (root_id);
@@ -0,0 +1,5 @@
+ALTER TABLE posts
+    DROP INDEX idx_posts_publish_at,
+    DROP INDEX idx_posts_status,
+    DROP COLUMN publish_at,
+    DROP COLUMN status;
@@ -0,0 +1,8 @@
+-- 文章状态和定时发布时间；已有文章视为已发布
+ALTER TABLE posts
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published',
+    ADD COLUMN publish_at DATETIME(3) NULL,
+    ADD INDEX idx_posts_status (status),
+    ADD INDEX idx_posts_publish_at (publish_at);
+
+UPDATE posts SET publish_at = created_at WHERE publish_at IS NULL;
@@ -0,0 +1,17 @@
+-- 文章修订记录；为已有文章写入初始版本
+CREATE TABLE post_revisions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    post_id BIGINT UNSIGNED NOT NULL,
+    revision BIGINT NOT NULL,
+    title LONGTEXT NOT NULL,
+    content LONGTEXT NOT NULL,
+    user_id BIGINT UNSIGNED NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_post_revisions_post_revision (post_id, revision),
+    KEY idx_post_revisions_user_id (user_id),
+    CONSTRAINT fk_post_revisions_post FOREIGN KEY (post_id) REFERENCES posts (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO post_revisions (post_id, revision, title, content, user_id, created_at)
+SELECT id, 1, title, content, user_id, COALESCE(updated_at, created_at) FROM posts;
@@ -0,0 +1,19 @@
+-- 回滚前永久删除回收站中的文章和评论，否则它们会重新出现；回收站中的用户将恢复为正常账号
+DELETE FROM comments WHERE deleted_at IS NOT NULL;
+DELETE FROM comments WHERE post_id IN (SELECT id FROM posts WHERE deleted_at IS NOT NULL);
+DELETE FROM post_revisions WHERE post_id IN (SELECT id FROM posts WHERE deleted_at IS NOT NULL);
+DELETE FROM post_tags WHERE post_id IN (SELECT id FROM posts WHERE deleted_at IS NOT NULL);
+DELETE FROM post_categories WHERE post_id IN (SELECT id FROM posts WHERE deleted_at IS NOT NULL);
+DELETE FROM posts WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE comments
+    DROP INDEX idx_comments_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE posts
+    DROP INDEX idx_posts_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE users
+    DROP INDEX idx_users_deleted_at,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,12 @@
+-- 用户、文章和评论支持软删除（回收站）
+ALTER TABLE users
+    ADD COLUMN deleted_at DATETIME(3) NULL,
+    ADD INDEX idx_users_deleted_at (deleted_at);
+
+ALTER TABLE posts
+    ADD COLUMN deleted_at DATETIME(3) NULL,
+    ADD INDEX idx_posts_deleted_at (deleted_at);
+
+ALTER TABLE comments
+    ADD COLUMN deleted_at DATETIME(3) NULL,
+    ADD INDEX idx_comments_deleted_at (deleted_at);
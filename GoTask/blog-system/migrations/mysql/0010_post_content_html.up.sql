@@ -0,0 +1,2 @@
+-- 文章内容按Markdown渲染，缓存清洗后的HTML；已有文章在服务启动时补齐
+ALTER TABLE posts ADD COLUMN content_html LONGTEXT NULL;
@@ -0,0 +1,22 @@
+-- 上传的图片和附件，文件保存在文件存储中，这里只保存元数据
+CREATE TABLE attachments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id BIGINT UNSIGNED NOT NULL,
+    post_id BIGINT UNSIGNED NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    width BIGINT NULL,
+    height BIGINT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NULL,
+    url VARCHAR(1024) NOT NULL,
+    thumbnail_url VARCHAR(1024) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    KEY idx_attachments_user_id (user_id),
+    KEY idx_attachments_post_id (post_id),
+    UNIQUE KEY idx_attachments_storage_key (storage_key),
+    CONSTRAINT fk_attachments_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_attachments_post FOREIGN KEY (post_id) REFERENCES posts (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,28 @@
+-- 登录尝试审计记录，用户名可能不存在，因此user_id不设外键
+CREATE TABLE login_attempts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    username VARCHAR(100) NOT NULL,
+    user_id BIGINT UNSIGNED NULL,
+    ip VARCHAR(64) NOT NULL,
+    user_agent VARCHAR(255) NULL,
+    result VARCHAR(20) NOT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    KEY idx_login_attempts_username (username),
+    KEY idx_login_attempts_user_id (user_id),
+    KEY idx_login_attempts_ip (ip),
+    KEY idx_login_attempts_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- 按用户名或IP统计的连续登录失败次数及锁定状态
+CREATE TABLE login_lockouts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    scope VARCHAR(20) NOT NULL,
+    subject VARCHAR(100) NOT NULL,
+    failures BIGINT NOT NULL DEFAULT 0,
+    last_failed_at DATETIME(3) NULL,
+    locked_until DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_login_lockouts_subject (scope, subject)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,19 @@
+-- 邮箱验证状态；升级前注册的用户视为已验证
+ALTER TABLE users ADD COLUMN verified TINYINT(1) NOT NULL DEFAULT 0;
+UPDATE users SET verified = 1;
+
+-- 邮箱验证和找回密码的一次性令牌，只保存令牌的哈希
+CREATE TABLE user_tokens (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id BIGINT UNSIGNED NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    expires_at DATETIME(3) NULL,
+    used_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_user_tokens_token_hash (token_hash),
+    KEY idx_user_tokens_user_id (user_id),
+    KEY idx_user_tokens_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
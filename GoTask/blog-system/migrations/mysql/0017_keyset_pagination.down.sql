@@ -0,0 +1,5 @@
+-- 外键 fk_comments_post 自动创建的索引可能已被联合索引取代，删除前先补一个post_id上的索引
+ALTER TABLE comments ADD INDEX idx_comments_post_id (post_id);
+ALTER TABLE comments DROP INDEX idx_comments_post_id_created_at_id;
+
+ALTER TABLE posts DROP INDEX idx_posts_created_at_id;
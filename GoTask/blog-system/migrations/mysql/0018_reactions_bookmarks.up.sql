@@ -0,0 +1,32 @@
+-- 文章和评论的表情回应，同一用户对同一目标的同一表情只有一条
+-- 使用 utf8mb4_bin 按字节比较表情，utf8mb4_general_ci 会把不同的表情视为相同而触发唯一索引冲突
+CREATE TABLE reactions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id BIGINT UNSIGNED NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id BIGINT UNSIGNED NOT NULL,
+    emoji VARCHAR(32) NOT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_reactions_target_user_emoji (target_type, target_id, user_id, emoji),
+    KEY idx_reactions_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+-- 用户收藏的文章
+CREATE TABLE bookmarks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id BIGINT UNSIGNED NOT NULL,
+    post_id BIGINT UNSIGNED NOT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_bookmarks_user_post (user_id, post_id),
+    KEY idx_bookmarks_post_id (post_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- 计数随回应和收藏在同一事务中更新
+ALTER TABLE posts
+    ADD COLUMN reaction_count BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN bookmark_count BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE comments
+    ADD COLUMN reaction_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- 用户级吊销改为按令牌代数判断；已有吊销记录的用户从代数1开始，升级前签发的令牌需要重新登录
+ALTER TABLE user_token_revocations ADD COLUMN generation BIGINT UNSIGNED NOT NULL DEFAULT 0;
+
+UPDATE user_token_revocations SET generation = 1;
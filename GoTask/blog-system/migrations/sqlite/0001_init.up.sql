@@ -0,0 +1,27 @@
+-- 初始表结构，与引入迁移前 AutoMigrate 创建的 users、posts、comments 三张表一致；
+-- 使用 IF NOT EXISTS 以便接管这样的数据库，之后新增的表和列都放在后续迁移中
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL UNIQUE,
+    password TEXT NOT NULL,
+    email TEXT NOT NULL UNIQUE,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    user_id INTEGER REFERENCES users (id),
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    content TEXT NOT NULL,
+    user_id INTEGER REFERENCES users (id),
+    post_id INTEGER REFERENCES posts (id),
+    created_at DATETIME
+);
@@ -0,0 +1,16 @@
+-- 令牌吊销：按jti吊销单个令牌，按用户吊销此前签发的全部令牌
+CREATE TABLE revoked_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    jti VARCHAR(64) NOT NULL,
+    user_id INTEGER,
+    expires_at DATETIME,
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_revoked_tokens_jti ON revoked_tokens (jti);
+CREATE INDEX idx_revoked_tokens_user_id ON revoked_tokens (user_id);
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+CREATE TABLE user_token_revocations (
+    user_id INTEGER PRIMARY KEY,
+    revoked_at DATETIME
+);
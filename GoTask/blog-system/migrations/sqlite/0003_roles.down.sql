@@ -0,0 +1,3 @@
+ALTER TABLE posts DROP COLUMN hidden;
+ALTER TABLE users DROP COLUMN banned;
+ALTER TABLE users DROP COLUMN role;
@@ -0,0 +1,4 @@
+-- 用户角色和封禁状态，文章被版主隐藏的标记
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN banned NUMERIC NOT NULL DEFAULT false;
+ALTER TABLE posts ADD COLUMN hidden NUMERIC NOT NULL DEFAULT false;
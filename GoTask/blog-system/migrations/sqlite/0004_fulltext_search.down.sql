@@ -0,0 +1 @@
+-- SQLite不支持MySQL FULLTEXT索引，搜索使用内存倒排索引（SEARCH_BACKEND=memory）
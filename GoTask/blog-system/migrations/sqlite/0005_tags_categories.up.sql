@@ -0,0 +1,28 @@
+CREATE TABLE tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(32) NOT NULL,
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_tags_name ON tags (name);
+
+CREATE TABLE categories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(64) NOT NULL,
+    description VARCHAR(255),
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_categories_name ON categories (name);
+
+CREATE TABLE post_tags (
+    post_id INTEGER NOT NULL REFERENCES posts (id),
+    tag_id INTEGER NOT NULL REFERENCES tags (id),
+    PRIMARY KEY (post_id, tag_id)
+);
+CREATE INDEX idx_post_tags_tag_id ON post_tags (tag_id);
+
+CREATE TABLE post_categories (
+    post_id INTEGER NOT NULL REFERENCES posts (id),
+    category_id INTEGER NOT NULL REFERENCES categories (id),
+    PRIMARY KEY (post_id, category_id)
+);
+CREATE INDEX idx_post_categories_category_id ON post_categories (category_id);
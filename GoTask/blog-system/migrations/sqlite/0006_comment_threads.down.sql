@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_comments_root_id;
+DROP INDEX IF EXISTS idx_comments_parent_id;
+ALTER TABLE comments DROP COLUMN deleted;
+ALTER TABLE comments DROP COLUMN depth;
+ALTER TABLE comments DROP COLUMN root_id;
+ALTER TABLE comments DROP COLUMN parent_id;
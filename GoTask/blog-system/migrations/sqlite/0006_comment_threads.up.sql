@@ -0,0 +1,7 @@
+-- 评论回复：父评论、所属顶层评论、嵌套层级和删除占位标记
+ALTER TABLE comments ADD COLUMN parent_id INTEGER;
+ALTER TABLE comments ADD COLUMN root_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN depth INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN deleted NUMERIC NOT NULL DEFAULT false;
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
+CREATE INDEX idx_comments_root_id ON comments (root_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_publish_at;
+DROP INDEX IF EXISTS idx_posts_status;
+ALTER TABLE posts DROP COLUMN publish_at;
+ALTER TABLE posts DROP COLUMN status;
@@ -0,0 +1,7 @@
+-- 文章状态和定时发布时间；已有文章视为已发布
+ALTER TABLE posts ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published';
+ALTER TABLE posts ADD COLUMN publish_at DATETIME;
+CREATE INDEX idx_posts_status ON posts (status);
+CREATE INDEX idx_posts_publish_at ON posts (publish_at);
+
+UPDATE posts SET publish_at = created_at WHERE publish_at IS NULL;
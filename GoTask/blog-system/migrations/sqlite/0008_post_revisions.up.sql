@@ -0,0 +1,15 @@
+-- 文章修订记录；为已有文章写入初始版本
+CREATE TABLE post_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id INTEGER NOT NULL REFERENCES posts (id),
+    revision INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    user_id INTEGER,
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_post_revisions_post_revision ON post_revisions (post_id, revision);
+CREATE INDEX idx_post_revisions_user_id ON post_revisions (user_id);
+
+INSERT INTO post_revisions (post_id, revision, title, content, user_id, created_at)
+SELECT id, 1, title, content, user_id, COALESCE(updated_at, created_at) FROM posts;
@@ -0,0 +1,9 @@
+-- 用户、文章和评论支持软删除（回收站）
+ALTER TABLE users ADD COLUMN deleted_at DATETIME;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+ALTER TABLE posts ADD COLUMN deleted_at DATETIME;
+CREATE INDEX idx_posts_deleted_at ON posts (deleted_at);
+
+ALTER TABLE comments ADD COLUMN deleted_at DATETIME;
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at);
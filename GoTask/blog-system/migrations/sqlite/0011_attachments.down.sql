@@ -0,0 +1,2 @@
+-- 只删除附件记录，已上传的文件需要手动清理
+DROP TABLE IF EXISTS attachments;
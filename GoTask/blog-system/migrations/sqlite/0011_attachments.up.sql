@@ -0,0 +1,19 @@
+-- 上传的图片和附件，文件保存在文件存储中，这里只保存元数据
+CREATE TABLE attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    post_id INTEGER REFERENCES posts (id),
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size INTEGER NOT NULL,
+    width INTEGER,
+    height INTEGER,
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255),
+    url VARCHAR(1024) NOT NULL,
+    thumbnail_url VARCHAR(1024),
+    created_at DATETIME
+);
+CREATE INDEX idx_attachments_user_id ON attachments (user_id);
+CREATE INDEX idx_attachments_post_id ON attachments (post_id);
+CREATE UNIQUE INDEX idx_attachments_storage_key ON attachments (storage_key);
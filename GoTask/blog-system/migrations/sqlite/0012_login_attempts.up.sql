@@ -0,0 +1,26 @@
+-- 登录尝试审计记录
+CREATE TABLE login_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username VARCHAR(100) NOT NULL,
+    user_id INTEGER,
+    ip VARCHAR(64) NOT NULL,
+    user_agent VARCHAR(255),
+    result VARCHAR(20) NOT NULL,
+    created_at DATETIME
+);
+CREATE INDEX idx_login_attempts_username ON login_attempts (username);
+CREATE INDEX idx_login_attempts_user_id ON login_attempts (user_id);
+CREATE INDEX idx_login_attempts_ip ON login_attempts (ip);
+CREATE INDEX idx_login_attempts_created_at ON login_attempts (created_at);
+
+-- 按用户名或IP统计的连续登录失败次数及锁定状态
+CREATE TABLE login_lockouts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    scope VARCHAR(20) NOT NULL,
+    subject VARCHAR(100) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failed_at DATETIME,
+    locked_until DATETIME,
+    updated_at DATETIME
+);
+CREATE UNIQUE INDEX idx_login_lockouts_subject ON login_lockouts (scope, subject);
@@ -0,0 +1,18 @@
+-- 邮箱验证状态；升级前注册的用户视为已验证
+ALTER TABLE users ADD COLUMN verified BOOLEAN NOT NULL DEFAULT 0;
+UPDATE users SET verified = 1;
+
+-- 邮箱验证和找回密码的一次性令牌，只保存令牌的哈希
+CREATE TABLE user_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    expires_at DATETIME,
+    used_at DATETIME,
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_user_tokens_token_hash ON user_tokens (token_hash);
+CREATE INDEX idx_user_tokens_user_id ON user_tokens (user_id);
+CREATE INDEX idx_user_tokens_expires_at ON user_tokens (expires_at);
@@ -0,0 +1,21 @@
+-- TOTP两步验证设置，每个用户一条
+CREATE TABLE user_totps (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at DATETIME,
+    last_step INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+CREATE UNIQUE INDEX idx_user_totps_user_id ON user_totps (user_id);
+
+-- 两步验证恢复码，只保存哈希
+CREATE TABLE recovery_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME
+);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
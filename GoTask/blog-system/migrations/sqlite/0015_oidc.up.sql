@@ -0,0 +1,25 @@
+-- 绑定到用户的外部身份（OpenID Connect）
+CREATE TABLE user_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at DATETIME,
+    updated_at DATETIME
+);
+CREATE UNIQUE INDEX idx_user_identities_issuer_subject ON user_identities (issuer, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+
+-- 进行中的授权请求，回调时校验并删除
+CREATE TABLE oidc_states (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    state_hash VARCHAR(64) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    user_id INTEGER,
+    expires_at DATETIME,
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_oidc_states_state_hash ON oidc_states (state_hash);
+CREATE INDEX idx_oidc_states_expires_at ON oidc_states (expires_at);
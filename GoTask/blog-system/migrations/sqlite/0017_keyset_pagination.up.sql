@@ -0,0 +1,3 @@
+-- 游标分页按 (created_at, id) 倒序读取，评论列表还需要先按文章过滤
+CREATE INDEX idx_posts_created_at_id ON posts (created_at, id);
+CREATE INDEX idx_comments_post_id_created_at_id ON comments (post_id, created_at, id);
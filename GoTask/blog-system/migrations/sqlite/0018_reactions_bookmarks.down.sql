@@ -0,0 +1,6 @@
+ALTER TABLE comments DROP COLUMN reaction_count;
+ALTER TABLE posts DROP COLUMN bookmark_count;
+ALTER TABLE posts DROP COLUMN reaction_count;
+
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS reactions;
@@ -0,0 +1,26 @@
+-- 文章和评论的表情回应，同一用户对同一目标的同一表情只有一条
+CREATE TABLE reactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id INTEGER NOT NULL,
+    emoji VARCHAR(32) NOT NULL,
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_reactions_target_user_emoji ON reactions (target_type, target_id, user_id, emoji);
+CREATE INDEX idx_reactions_user_id ON reactions (user_id);
+
+-- 用户收藏的文章
+CREATE TABLE bookmarks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    post_id INTEGER NOT NULL,
+    created_at DATETIME
+);
+CREATE UNIQUE INDEX idx_bookmarks_user_post ON bookmarks (user_id, post_id);
+CREATE INDEX idx_bookmarks_post_id ON bookmarks (post_id);
+
+-- 计数随回应和收藏在同一事务中更新
+ALTER TABLE posts ADD COLUMN reaction_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN bookmark_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN reaction_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,192 @@
+package diff
+
+import (
+    "strings"
+)
+
+// 差异操作类型
+const (
+    OpEqual  = "equal"
+    OpInsert = "insert"
+    OpDelete = "delete"
+)
+
+// Line 差异结果中的一行
+type Line struct {
+    Op      string `json:"op"`
+    Text    string `json:"text"`
+    OldLine int    `json:"old_line,omitempty"` // 在旧文本中的行号（从1开始），新增行为0
+    NewLine int    `json:"new_line,omitempty"` // 在新文本中的行号（从1开始），删除行为0
+}
+
+// Lines 计算两段文本的逐行差异（Myers算法）
+func Lines(oldText, newText string) []Line {
+    lines, _ := Bounded(oldText, newText, 0, 0)
+    return lines
+}
+
+// Bounded 同 Lines，但去掉相同的首尾后，剩余行数超过maxLines或编辑距离超过maxEdits时不再逐行比较，
+// 中间部分按整体替换输出（先删除全部旧行，再插入全部新行），并返回true；上限为0表示不限制
+// 逐行比较的时间为 O((N+M)·D)，内存为 O(N+M+D²)，D为编辑距离
+func Bounded(oldText, newText string, maxLines, maxEdits int) ([]Line, bool) {
+    a := splitLines(oldText)
+    b := splitLines(newText)
+
+    // 先去掉相同的首尾，减少需要比较的行数
+    prefix := 0
+    for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+        prefix++
+    }
+    suffix := 0
+    for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+        suffix++
+    }
+
+    result := make([]Line, 0, len(a)+len(b))
+    for i := 0; i < prefix; i++ {
+        result = append(result, Line{Op: OpEqual, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+    }
+
+    midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+    middle, ok := []Line(nil), false
+    if maxLines <= 0 || len(midA)+len(midB) <= maxLines {
+        middle, ok = myers(midA, midB, maxEdits)
+    }
+    if !ok {
+        middle = replace(midA, midB)
+    }
+
+    for _, line := range middle {
+        if line.OldLine > 0 {
+            line.OldLine += prefix
+        }
+        if line.NewLine > 0 {
+            line.NewLine += prefix
+        }
+        result = append(result, line)
+    }
+
+    for i := suffix; i > 0; i-- {
+        result = append(result, Line{
+            Op:      OpEqual,
+            Text:    a[len(a)-i],
+            OldLine: len(a) - i + 1,
+            NewLine: len(b) - i + 1,
+        })
+    }
+    return result, !ok
+}
+
+// Stats 统计新增和删除的行数
+func Stats(lines []Line) (added, removed int) {
+    for _, line := range lines {
+        switch line.Op {
+        case OpInsert:
+            added++
+        case OpDelete:
+            removed++
+        }
+    }
+    return added, removed
+}
+
+// splitLines 按行切分文本，空文本没有行
+func splitLines(text string) []string {
+    if text == "" {
+        return nil
+    }
+    text = strings.ReplaceAll(text, "\r\n", "\n")
+    return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// replace 整体替换：删除a的全部行，再插入b的全部行，行号相对于传入的切片
+func replace(a, b []string) []Line {
+    lines := make([]Line, 0, len(a)+len(b))
+    for i, text := range a {
+        lines = append(lines, Line{Op: OpDelete, Text: text, OldLine: i + 1})
+    }
+    for i, text := range b {
+        lines = append(lines, Line{Op: OpInsert, Text: text, NewLine: i + 1})
+    }
+    return lines
+}
+
+// myers 计算最短编辑脚本，返回的行号相对于传入的切片
+// 编辑距离超过maxEdits（大于0时）时放弃计算，返回false
+func myers(a, b []string, maxEdits int) ([]Line, bool) {
+    n, m := len(a), len(b)
+    max := n + m
+    if maxEdits > 0 && maxEdits < max {
+        max = maxEdits
+    }
+    offset := max + 1
+    v := make([]int, 2*max+3)
+
+    // 第d步只会用到对角线 -d-1..d+1 上的值，回溯时只保存这一段，
+    // trace[d][k+d+1] 即第d步开始时的 v[offset+k]
+    var trace [][]int
+    found := false
+search:
+    for d := 0; d <= max; d++ {
+        trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+        for k := -d; k <= d; k += 2 {
+            var x int
+            if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+                x = v[offset+k+1]
+            } else {
+                x = v[offset+k-1] + 1
+            }
+            y := x - k
+            for x < n && y < m && a[x] == b[y] {
+                x++
+                y++
+            }
+            v[offset+k] = x
+            if x >= n && y >= m {
+                found = true
+                break search
+            }
+        }
+    }
+    if !found {
+        return nil, false
+    }
+
+    // 从终点回溯，得到倒序的编辑脚本
+    var reversed []Line
+    x, y := n, m
+    for d := len(trace) - 1; d >= 0; d-- {
+        w := trace[d]
+        k := x - y
+        at := func(k int) int { return w[k+d+1] }
+
+        var prevK int
+        if k == -d || (k != d && at(k-1) < at(k+1)) {
+            prevK = k + 1
+        } else {
+            prevK = k - 1
+        }
+        prevX := at(prevK)
+        prevY := prevX - prevK
+
+        for x > prevX && y > prevY {
+            reversed = append(reversed, Line{Op: OpEqual, Text: a[x-1], OldLine: x, NewLine: y})
+            x--
+            y--
+        }
+        if d > 0 {
+            if x == prevX {
+                reversed = append(reversed, Line{Op: OpInsert, Text: b[y-1], NewLine: y})
+            } else {
+                reversed = append(reversed, Line{Op: OpDelete, Text: a[x-1], OldLine: x})
+            }
+        }
+        x, y = prevX, prevY
+    }
+
+    lines := make([]Line, len(reversed))
+    for i, line := range reversed {
+        lines[len(reversed)-1-i] = line
+    }
+    return lines, true
+}
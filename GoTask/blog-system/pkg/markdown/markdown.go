@@ -0,0 +1,335 @@
+package markdown
+
+import (
+    "html"
+    "regexp"
+    "strings"
+)
+
+// 块级语法
+var (
+    headingRe    = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)[ \t]*#*[ \t]*$`)
+    fenceRe      = regexp.MustCompile("^(```+|~~~+)[ \t]*([A-Za-z0-9_+#-]*)")
+    hrRe         = regexp.MustCompile(`^ {0,3}(-[ \t]*-[ \t]*-[- \t]*|\*[ \t]*\*[ \t]*\*[* \t]*|_[ \t]*_[ \t]*_[_ \t]*)$`)
+    bulletRe     = regexp.MustCompile(`^ {0,3}[-*+][ \t]+(.*)$`)
+    orderedRe    = regexp.MustCompile(`^ {0,3}([0-9]{1,9})[.)][ \t]+(.*)$`)
+    blockquoteRe = regexp.MustCompile(`^ {0,3}>[ ]?(.*)$`)
+)
+
+// Render 将Markdown转换为HTML
+// 支持标题、段落、引用、有序/无序列表、分隔线、围栏代码块，以及粗体、斜体、删除线、行内代码、链接和图片；
+// 原始HTML不会被解析，所有文本都会转义，链接只保留安全的协议
+func Render(src string) string {
+    src = strings.ReplaceAll(src, "\r\n", "\n")
+    src = strings.ReplaceAll(src, "\r", "\n")
+    var b strings.Builder
+    renderBlocks(&b, strings.Split(src, "\n"))
+    return b.String()
+}
+
+// renderBlocks 逐行解析块级元素
+func renderBlocks(b *strings.Builder, lines []string) {
+    for i := 0; i < len(lines); {
+        line := lines[i]
+        trimmed := strings.TrimSpace(line)
+
+        switch {
+        case trimmed == "":
+            i++
+
+        case fenceRe.MatchString(trimmed):
+            m := fenceRe.FindStringSubmatch(trimmed)
+            fence := m[1]
+            var code []string
+            i++
+            for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+                code = append(code, lines[i])
+                i++
+            }
+            i++ // 跳过结束围栏，文档结束时没有结束围栏也视为闭合
+            b.WriteString("<pre><code")
+            if m[2] != "" {
+                b.WriteString(` class="language-` + html.EscapeString(m[2]) + `"`)
+            }
+            b.WriteString(">")
+            if len(code) > 0 {
+                b.WriteString(html.EscapeString(strings.Join(code, "\n")) + "\n")
+            }
+            b.WriteString("</code></pre>\n")
+
+        case headingRe.MatchString(trimmed):
+            m := headingRe.FindStringSubmatch(trimmed)
+            level := string('0' + rune(len(m[1])))
+            b.WriteString("<h" + level + ">" + renderInline(m[2]) + "</h" + level + ">\n")
+            i++
+
+        case hrRe.MatchString(line):
+            b.WriteString("<hr>\n")
+            i++
+
+        case blockquoteRe.MatchString(line):
+            var inner []string
+            for i < len(lines) && blockquoteRe.MatchString(lines[i]) {
+                inner = append(inner, blockquoteRe.FindStringSubmatch(lines[i])[1])
+                i++
+            }
+            b.WriteString("<blockquote>\n")
+            renderBlocks(b, inner)
+            b.WriteString("</blockquote>\n")
+
+        case bulletRe.MatchString(line):
+            i = renderList(b, lines, i, bulletRe, "ul")
+
+        case orderedRe.MatchString(line):
+            i = renderList(b, lines, i, orderedRe, "ol")
+
+        default:
+            var para []string
+            for i < len(lines) && isParagraphLine(lines[i]) {
+                para = append(para, lines[i])
+                i++
+            }
+            b.WriteString("<p>" + renderParagraph(para) + "</p>\n")
+        }
+    }
+}
+
+// isParagraphLine 该行是否延续当前段落
+func isParagraphLine(line string) bool {
+    trimmed := strings.TrimSpace(line)
+    return trimmed != "" &&
+        !fenceRe.MatchString(trimmed) &&
+        !headingRe.MatchString(trimmed) &&
+        !hrRe.MatchString(line) &&
+        !blockquoteRe.MatchString(line) &&
+        !bulletRe.MatchString(line) &&
+        !orderedRe.MatchString(line)
+}
+
+// renderParagraph 渲染段落，行尾两个空格或反斜杠表示强制换行
+func renderParagraph(lines []string) string {
+    parts := make([]string, len(lines))
+    for i, line := range lines {
+        hardBreak := i < len(lines)-1 && (strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\"))
+        text := strings.TrimSpace(line)
+        if hardBreak {
+            text = strings.TrimSuffix(text, "\\")
+        }
+        parts[i] = renderInline(text)
+        if hardBreak {
+            parts[i] += "<br>"
+        }
+    }
+    return strings.Join(parts, "\n")
+}
+
+// renderList 渲染连续的列表项，列表项内缩进的行作为该项的续行
+func renderList(b *strings.Builder, lines []string, i int, marker *regexp.Regexp, tag string) int {
+    b.WriteString("<" + tag)
+    if tag == "ol" {
+        if start := marker.FindStringSubmatch(lines[i])[1]; strings.TrimLeft(start, "0") != "1" {
+            b.WriteString(` start="` + start + `"`)
+        }
+    }
+    b.WriteString(">\n")
+
+    for i < len(lines) && marker.MatchString(lines[i]) {
+        m := marker.FindStringSubmatch(lines[i])
+        item := []string{m[len(m)-1]}
+        i++
+        for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (strings.HasPrefix(lines[i], "  ") || strings.HasPrefix(lines[i], "\t")) {
+            item = append(item, strings.TrimSpace(lines[i]))
+            i++
+        }
+        b.WriteString("<li>" + renderParagraph(item) + "</li>\n")
+    }
+
+    b.WriteString("</" + tag + ">\n")
+    return i
+}
+
+// renderInline 渲染行内元素，其余文本做HTML转义
+func renderInline(text string) string {
+    var b strings.Builder
+    for i := 0; i < len(text); {
+        c := text[i]
+        switch {
+        case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_{}[]()#+-.!~<>|", text[i+1]) >= 0:
+            b.WriteString(html.EscapeString(text[i+1 : i+2]))
+            i += 2
+            continue
+
+        case c == '`':
+            if n, end := codeSpan(text, i); end > 0 {
+                b.WriteString("<code>" + html.EscapeString(strings.TrimSpace(text[i+n:end])) + "</code>")
+                i = end + n
+                continue
+            }
+
+        case c == '!' && strings.HasPrefix(text[i:], "!["):
+            if alt, url, title, next, ok := parseLink(text, i+1); ok {
+                if safeURL(url) {
+                    b.WriteString(`<img src="` + html.EscapeString(url) + `" alt="` + html.EscapeString(alt) + `"`)
+                    if title != "" {
+                        b.WriteString(` title="` + html.EscapeString(title) + `"`)
+                    }
+                    b.WriteString(">")
+                } else {
+                    b.WriteString(html.EscapeString(alt))
+                }
+                i = next
+                continue
+            }
+
+        case c == '[':
+            if label, url, title, next, ok := parseLink(text, i); ok {
+                if safeURL(url) {
+                    b.WriteString(`<a href="` + html.EscapeString(url) + `"`)
+                    if title != "" {
+                        b.WriteString(` title="` + html.EscapeString(title) + `"`)
+                    }
+                    b.WriteString(">" + renderInline(label) + "</a>")
+                } else {
+                    b.WriteString(renderInline(label))
+                }
+                i = next
+                continue
+            }
+
+        case c == '<':
+            if end := strings.IndexByte(text[i:], '>'); end > 0 {
+                url := text[i+1 : i+end]
+                if !strings.ContainsAny(url, " \t") && (strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")) {
+                    escaped := html.EscapeString(url)
+                    b.WriteString(`<a href="` + escaped + `">` + escaped + "</a>")
+                    i += end + 1
+                    continue
+                }
+            }
+
+        case c == '*' || c == '_' || c == '~':
+            if tag, delim, ok := emphasis(text, i); ok {
+                end := strings.Index(text[i+len(delim):], delim)
+                inner := text[i+len(delim) : i+len(delim)+end]
+                b.WriteString("<" + tag + ">" + renderInline(inner) + "</" + tag + ">")
+                i += len(delim)*2 + end
+                continue
+            }
+        }
+
+        b.WriteString(html.EscapeString(text[i : i+1]))
+        i++
+    }
+    return b.String()
+}
+
+// codeSpan 查找与起始反引号数量相同的结束反引号，返回反引号数量和结束位置
+func codeSpan(text string, start int) (int, int) {
+    n := 0
+    for start+n < len(text) && text[start+n] == '`' {
+        n++
+    }
+    delim := strings.Repeat("`", n)
+    end := strings.Index(text[start+n:], delim)
+    if end < 0 {
+        return n, -1
+    }
+    return n, start + n + end
+}
+
+// emphasis 识别强调语法：**粗体**、*斜体*、~~删除线~~，要求有结束标记且内容不以空白开头
+func emphasis(text string, i int) (string, string, bool) {
+    candidates := []struct{ delim, tag string }{
+        {"**", "strong"}, {"__", "strong"}, {"~~", "del"}, {"*", "em"}, {"_", "em"},
+    }
+    for _, c := range candidates {
+        if !strings.HasPrefix(text[i:], c.delim) {
+            continue
+        }
+        rest := text[i+len(c.delim):]
+        end := strings.Index(rest, c.delim)
+        if end <= 0 || strings.TrimSpace(rest[:1]) == "" || strings.TrimSpace(rest[end-1:end]) == "" {
+            continue
+        }
+        // 单词内部的下划线（如snake_case）不作为强调
+        if c.delim[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+            continue
+        }
+        return c.tag, c.delim, true
+    }
+    return "", "", false
+}
+
+// isWordByte 是否为字母或数字
+func isWordByte(c byte) bool {
+    return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// parseLink 解析[文本](地址 "标题")，start指向'['
+func parseLink(text string, start int) (label, url, title string, next int, ok bool) {
+    depth := 0
+    closeLabel := -1
+    for j := start; j < len(text); j++ {
+        if text[j] == '\\' {
+            j++
+            continue
+        }
+        if text[j] == '[' {
+            depth++
+        } else if text[j] == ']' {
+            depth--
+            if depth == 0 {
+                closeLabel = j
+                break
+            }
+        }
+    }
+    if closeLabel < 0 || closeLabel+1 >= len(text) || text[closeLabel+1] != '(' {
+        return "", "", "", 0, false
+    }
+    // 地址中允许成对的括号
+    closeDest, parens := -1, 0
+    for j := closeLabel + 2; j < len(text) && closeDest < 0; j++ {
+        switch text[j] {
+        case '(':
+            parens++
+        case ')':
+            if parens == 0 {
+                closeDest = j - closeLabel - 2
+            }
+            parens--
+        }
+    }
+    if closeDest < 0 {
+        return "", "", "", 0, false
+    }
+
+    dest := strings.TrimSpace(text[closeLabel+2 : closeLabel+2+closeDest])
+    if sp := strings.IndexAny(dest, " \t"); sp >= 0 {
+        t := strings.TrimSpace(dest[sp:])
+        if len(t) >= 2 && (t[0] == '"' && t[len(t)-1] == '"' || t[0] == '\'' && t[len(t)-1] == '\'') {
+            title = t[1 : len(t)-1]
+        }
+        dest = dest[:sp]
+    }
+    dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+    return text[start+1 : closeLabel], dest, title, closeLabel + 3 + closeDest, true
+}
+
+// safeURL 链接只允许http、https、mailto协议或相对地址
+func safeURL(url string) bool {
+    decoded := strings.ToLower(strings.TrimSpace(html.UnescapeString(url)))
+    if decoded == "" || strings.ContainsAny(decoded, "\x00\t\r\n") {
+        return false
+    }
+    colon := strings.IndexByte(decoded, ':')
+    if colon < 0 {
+        return true
+    }
+    // 冒号出现在路径、查询或锚点中时不是协议
+    if slash := strings.IndexAny(decoded, "/?#"); slash >= 0 && slash < colon {
+        return true
+    }
+    scheme := decoded[:colon]
+    return scheme == "http" || scheme == "https" || scheme == "mailto"
+}
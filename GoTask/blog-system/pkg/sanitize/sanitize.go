@@ -0,0 +1,190 @@
+package sanitize
+
+import (
+    "net/url"
+    "regexp"
+    "strings"
+
+    "golang.org/x/net/html"
+)
+
+// allowedTags 允许保留的标签及其允许的属性
+var allowedTags = map[string][]string{
+    "p":          nil,
+    "br":         nil,
+    "hr":         nil,
+    "h1":         nil,
+    "h2":         nil,
+    "h3":         nil,
+    "h4":         nil,
+    "h5":         nil,
+    "h6":         nil,
+    "strong":     nil,
+    "b":          nil,
+    "em":         nil,
+    "i":          nil,
+    "del":        nil,
+    "code":       {"class"},
+    "pre":        nil,
+    "blockquote": nil,
+    "ul":         nil,
+    "ol":         {"start"},
+    "li":         nil,
+    "a":          {"href", "title"},
+    "img":        {"src", "alt", "title"},
+}
+
+// voidTags 没有结束标签的元素
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// droppedTags 连同内容一起丢弃的标签
+var droppedTags = map[string]bool{
+    "script":   true,
+    "style":    true,
+    "iframe":   true,
+    "object":   true,
+    "embed":    true,
+    "noscript": true,
+    "template": true,
+    "textarea": true,
+    "title":    true,
+    "svg":      true,
+    "math":     true,
+}
+
+var (
+    languageClass = regexp.MustCompile(`^language-[A-Za-z0-9_+#-]{1,32}$`)
+    digits        = regexp.MustCompile(`^[0-9]{1,9}$`)
+)
+
+// HTML 清洗HTML片段：只保留白名单中的标签和属性，链接只允许http、https、mailto和站内地址，
+// 其余标签被去掉但保留文本，script等危险标签连同内容一起删除，未闭合的标签会被补齐
+func HTML(input string) string {
+    var b strings.Builder
+    var open []string // 已输出且尚未闭合的标签
+    skip := ""        // 正在丢弃内容的标签
+    depth := 0        // 被丢弃标签的嵌套层数
+
+    z := html.NewTokenizer(strings.NewReader(input))
+    for {
+        tt := z.Next()
+        if tt == html.ErrorToken {
+            break
+        }
+        token := z.Token()
+
+        if skip != "" {
+            switch {
+            case tt == html.StartTagToken && token.Data == skip:
+                depth++
+            case tt == html.EndTagToken && token.Data == skip:
+                depth--
+                if depth == 0 {
+                    skip = ""
+                }
+            }
+            continue
+        }
+
+        switch tt {
+        case html.TextToken:
+            b.WriteString(html.EscapeString(token.Data))
+        case html.StartTagToken, html.SelfClosingTagToken:
+            if droppedTags[token.Data] {
+                if tt == html.StartTagToken {
+                    skip, depth = token.Data, 1
+                }
+                continue
+            }
+            attrs, ok := allowedTags[token.Data]
+            if !ok {
+                continue
+            }
+            writeStartTag(&b, token, attrs)
+            if !voidTags[token.Data] {
+                if tt == html.SelfClosingTagToken {
+                    b.WriteString("</" + token.Data + ">")
+                } else {
+                    open = append(open, token.Data)
+                }
+            }
+        case html.EndTagToken:
+            // 闭合最近的同名标签，中间未闭合的标签一并闭合
+            for i := len(open) - 1; i >= 0; i-- {
+                if open[i] == token.Data {
+                    for j := len(open) - 1; j >= i; j-- {
+                        b.WriteString("</" + open[j] + ">")
+                    }
+                    open = open[:i]
+                    break
+                }
+            }
+        }
+    }
+
+    for i := len(open) - 1; i >= 0; i-- {
+        b.WriteString("</" + open[i] + ">")
+    }
+    return b.String()
+}
+
+// writeStartTag 输出开始标签，只保留允许且安全的属性
+func writeStartTag(b *strings.Builder, token html.Token, allowed []string) {
+    b.WriteString("<" + token.Data)
+    for _, attr := range token.Attr {
+        if attr.Namespace != "" || !contains(allowed, attr.Key) {
+            continue
+        }
+        value, ok := safeAttr(token.Data, attr.Key, attr.Val)
+        if !ok {
+            continue
+        }
+        b.WriteString(" " + attr.Key + `="` + html.EscapeString(value) + `"`)
+    }
+    if token.Data == "a" {
+        b.WriteString(` rel="nofollow noopener noreferrer"`)
+    }
+    b.WriteString(">")
+}
+
+// safeAttr 校验属性值
+func safeAttr(tag, key, value string) (string, bool) {
+    switch key {
+    case "href":
+        return value, SafeURL(value, "http", "https", "mailto")
+    case "src":
+        return value, SafeURL(value, "http", "https")
+    case "class":
+        return value, tag == "code" && languageClass.MatchString(value)
+    case "start":
+        return value, digits.MatchString(value)
+    }
+    return value, true
+}
+
+// SafeURL 检查链接是否为站内地址或使用允许的协议，拒绝javascript:、data:等
+func SafeURL(raw string, schemes ...string) bool {
+    raw = strings.TrimSpace(raw)
+    if raw == "" || strings.ContainsAny(raw, "\x00\t\r\n") {
+        return false
+    }
+    u, err := url.Parse(raw)
+    if err != nil {
+        return false
+    }
+    if u.Scheme == "" {
+        // 相对地址和站内锚点
+        return true
+    }
+    return contains(schemes, strings.ToLower(u.Scheme))
+}
+
+// contains 切片中是否包含指定字符串
+func contains(items []string, target string) bool {
+    for _, item := range items {
+        if item == target {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,115 @@
+package thumbnail
+
+import (
+    "bytes"
+    "errors"
+    "image"
+    "image/draw"
+    "image/gif"
+    "image/jpeg"
+    "image/png"
+)
+
+// MaxPixels 允许解码的最大像素数，防止小文件解压出超大图片耗尽内存
+const MaxPixels = 40_000_000
+
+// Thumbnail 生成的缩略图
+type Thumbnail struct {
+    Data         []byte
+    ContentType  string // image/jpeg 或 image/png
+    Ext          string // .jpg 或 .png
+    SourceWidth  int    // 原图宽度
+    SourceHeight int    // 原图高度
+}
+
+// Generate 解码JPEG、PNG或GIF（取第一帧）并按比例缩小到宽高都不超过maxSide；
+// JPEG生成JPEG缩略图，PNG和GIF生成PNG缩略图以保留透明度
+func Generate(data []byte, maxSide int) (*Thumbnail, error) {
+    cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+    if err != nil {
+        return nil, errors.New("无法识别的图片格式")
+    }
+    if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+        return nil, errors.New("图片尺寸过大")
+    }
+
+    var src image.Image
+    switch format {
+    case "jpeg":
+        src, err = jpeg.Decode(bytes.NewReader(data))
+    case "png":
+        src, err = png.Decode(bytes.NewReader(data))
+    case "gif":
+        src, err = gif.Decode(bytes.NewReader(data))
+    default:
+        return nil, errors.New("无法识别的图片格式")
+    }
+    if err != nil {
+        return nil, errors.New("图片已损坏")
+    }
+
+    thumb := &Thumbnail{SourceWidth: cfg.Width, SourceHeight: cfg.Height}
+    dst := Fit(src, maxSide)
+
+    var buf bytes.Buffer
+    if format == "jpeg" {
+        err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80})
+        thumb.ContentType, thumb.Ext = "image/jpeg", ".jpg"
+    } else {
+        err = png.Encode(&buf, dst)
+        thumb.ContentType, thumb.Ext = "image/png", ".png"
+    }
+    if err != nil {
+        return nil, err
+    }
+    thumb.Data = buf.Bytes()
+    return thumb, nil
+}
+
+// Fit 按比例缩小图片使宽高都不超过maxSide，使用区域平均采样；不超过maxSide的图片不放大
+func Fit(src image.Image, maxSide int) image.Image {
+    bounds := src.Bounds()
+    w, h := bounds.Dx(), bounds.Dy()
+    if w <= maxSide && h <= maxSide {
+        return src
+    }
+
+    dw, dh := maxSide, maxSide
+    if w >= h {
+        dh = max(1, h*maxSide/w)
+    } else {
+        dw = max(1, w*maxSide/h)
+    }
+
+    // 统一转换为RGBA（预乘透明度），便于直接读取像素并正确混合半透明像素
+    rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+    draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+    dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+    for y := 0; y < dh; y++ {
+        y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+        for x := 0; x < dw; x++ {
+            x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+            var r, g, b, a, n uint64
+            for sy := y0; sy < y1; sy++ {
+                row := rgba.Pix[sy*rgba.Stride:]
+                for sx := x0; sx < x1; sx++ {
+                    p := row[sx*4 : sx*4+4]
+                    r += uint64(p[0])
+                    g += uint64(p[1])
+                    b += uint64(p[2])
+                    a += uint64(p[3])
+                    n++
+                }
+            }
+
+            i := y*dst.Stride + x*4
+            dst.Pix[i] = uint8(r / n)
+            dst.Pix[i+1] = uint8(g / n)
+            dst.Pix[i+2] = uint8(b / n)
+            dst.Pix[i+3] = uint8(a / n)
+        }
+    }
+    return dst
+}
@@ -1,20 +0,0 @@
-package utils
-
-import (
-    "golang.org/x/crypto/bcrypt"
-)
-
-// HashPassword 对密码进行哈希处理
-func HashPassword(password string) (string, error) {
-    hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-    if err != nil {
-        return "", err
-    }
-    return string(hashedBytes), nil
-}
-
-// CheckPasswordHash 验证密码是否匹配
-func CheckPasswordHash(password, hash string) bool {
-    err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-    return err == nil
-}
\ No newline at end of file